@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	harbor "github.com/nevercase/harbor-api"
+	"gopkg.in/yaml.v3"
+)
+
+// loadHarborConfig reads the registries from the YAML file, e.g.
+//
+//   - url: https://harbor.domain.com
+//     admin: robot
+//     password: secret
+//
+// It returns nil if the path was empty.
+func loadHarborConfig(path string) ([]harbor.Config, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]harbor.Config, 0)
+	if err = yaml.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	for i, v := range res {
+		if v.Url == "" {
+			return nil, fmt.Errorf("%s: the url of the registry %d was empty", path, i)
+		}
+	}
+	return res, nil
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"flag"
+	"os"
+	"time"
+
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
@@ -9,17 +12,39 @@ import (
 	// _ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 
 	crd "github.com/Shanghai-Lunara/helixsaga-operator/pkg/controllers/helixsaga"
+	"github.com/Shanghai-Lunara/helixsaga-operator/pkg/conversionwebhook"
 	clientset "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	"github.com/Shanghai-Lunara/helixsaga-operator/pkg/registrywebhook"
+	"github.com/Shanghai-Lunara/helixsaga-operator/pkg/serviceloadbalancer"
 	"github.com/nevercase/k8s-controller-custom-resource/pkg/signals"
 )
 
 var (
-	masterURL  string
-	kubeconfig string
+	masterURL       string
+	kubeconfig      string
+	slbConfig       string
+	slbConfigPeriod time.Duration
+	webhookAddr     string
+	webhookSecret   string
+	webhookCert     string
+	webhookKey      string
+	harborConfig    string
+	registryWatch   bool
+	updateTimeout   time.Duration
+	conversionAddr  string
+	conversionCert  string
+	conversionKey   string
 )
 
 func main() {
 	klog.InitFlags(nil)
+	// the plan subcommand prints the changes of a proposed HelixSaga instead of running the operator
+	if len(os.Args) > 1 && os.Args[1] == "plan" {
+		if err := runPlan(os.Args[2:]); err != nil {
+			klog.Fatalf("Error planning the HelixSaga: %s", err.Error())
+		}
+		return
+	}
 	flag.Parse()
 
 	// set up signals so we handle the first shutdown signal gracefully
@@ -40,7 +65,47 @@ func main() {
 		klog.Fatalf("Error building example clientset: %s", err.Error())
 	}
 
-	controller := crd.NewController("helix-saga-controller", kubeClient, exampleClient, stopCh)
+	if slbConfig != "" {
+		serviceloadbalancer.Init(slbConfig)
+		go serviceloadbalancer.Watch(slbConfig, slbConfigPeriod, stopCh)
+	}
+
+	crd.PatchTimeout = updateTimeout
+	harbors, err := loadHarborConfig(harborConfig)
+	if err != nil {
+		klog.Fatalf("Error loading harbor config: %s", err.Error())
+	}
+	if registryWatch && len(harbors) == 0 {
+		klog.Fatal("Error running registry watch: no registry was configured by -harbor-config")
+	}
+	watchers := crd.NewWatchers(harbors)
+	if !registryWatch {
+		watchers.DisableRegistryWatch()
+	}
+	if webhookAddr != "" {
+		if webhookSecret == "" {
+			webhookSecret = os.Getenv("REGISTRY_WEBHOOK_SECRET")
+		}
+		// a push would roll out every subscribed HelixSaga, so the webhook never accepts the unauthenticated requests
+		if webhookSecret == "" {
+			klog.Fatal("Error running registry webhook: -webhook-secret or REGISTRY_WEBHOOK_SECRET is required")
+		}
+		go func() {
+			if err := registrywebhook.ListenAndServeTLS(webhookAddr, webhookCert, webhookKey, registrywebhook.NewHandler(webhookSecret, watchers.Notify), stopCh); err != nil {
+				klog.Fatalf("Error running registry webhook: %s", err.Error())
+			}
+		}()
+	}
+
+	if conversionAddr != "" {
+		go func() {
+			if err := conversionwebhook.ListenAndServeTLS(conversionAddr, conversionCert, conversionKey, conversionwebhook.NewHandler(), stopCh); err != nil {
+				klog.Fatalf("Error running conversion webhook: %s", err.Error())
+			}
+		}()
+	}
+
+	controller := crd.NewController("helix-saga-controller", kubeClient, exampleClient, watchers, stopCh)
 
 	if err = controller.Run(2, stopCh); err != nil {
 		klog.Fatalf("Error running controller: %s", err.Error())
@@ -50,4 +115,16 @@ func main() {
 func init() {
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	flag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	flag.StringVar(&slbConfig, "slbconfig", "", "Path to the annotations config of the LoadBalancer Services. It would be reloaded when the file changed.")
+	flag.DurationVar(&slbConfigPeriod, "slbconfig-period", time.Second*10, "The period of checking the slbconfig file for changes.")
+	flag.StringVar(&webhookAddr, "webhook-addr", "", "The address of the registry webhook receiver, e.g. :8443. Disabled if empty.")
+	flag.StringVar(&webhookSecret, "webhook-secret", "", "The shared secret of the registry webhook. Defaults to the env REGISTRY_WEBHOOK_SECRET. Required by the webhook.")
+	flag.StringVar(&webhookCert, "webhook-cert", "", "Path to the TLS certificate of the registry webhook. It serves plain HTTP if neither the cert nor the key was given.")
+	flag.StringVar(&webhookKey, "webhook-key", "", "Path to the TLS private key of the registry webhook.")
+	flag.StringVar(&harborConfig, "harbor-config", "", "Path to the YAML list of the registries in the form of {url, admin, password}, which were used by the registry watch and the digest resolving.")
+	flag.BoolVar(&registryWatch, "registry-watch", false, "Keep a long-lived watch connection to the registry for every image. It requires the -harbor-config.")
+	flag.DurationVar(&updateTimeout, "update-timeout", crd.PatchTimeout, "The deadline of an automatic image update, including waiting for the old pods to be closed.")
+	flag.StringVar(&conversionAddr, "conversion-addr", "", "The address of the CRD conversion webhook between the HelixSaga versions, e.g. :9443. Disabled if empty.")
+	flag.StringVar(&conversionCert, "conversion-cert", "", "Path to the TLS certificate of the conversion webhook.")
+	flag.StringVar(&conversionKey, "conversion-key", "", "Path to the TLS private key of the conversion webhook.")
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	helixsagav1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixsagav2 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v2"
+	crd "github.com/Shanghai-Lunara/helixsaga-operator/pkg/controllers/helixsaga"
+	clientset "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	k8scorev1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+)
+
+// runPlan prints the changes which the operator would make for the proposed HelixSaga in the file, without changing anything.
+// Usage: helixsaga plan -f helixsaga.yaml [-n namespace] [-o text|json] [-kubeconfig path]
+func runPlan(args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ExitOnError)
+	file := fs.String("f", "", "Path to the proposed HelixSaga in YAML or JSON, in nevercase.io/v1 or v2.")
+	namespace := fs.String("n", "", "The namespace of the HelixSaga. Overrides the one in the file, defaults to default.")
+	output := fs.String("o", "text", "The output format, text or json.")
+	timeout := fs.Duration("timeout", time.Minute, "The deadline of listing the live resources.")
+	fs.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	fs.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("the proposed HelixSaga was not specified by -f")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %s", *output)
+	}
+	data, err := ioutil.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	hs, err := decodeHelixSaga(data)
+	if err != nil {
+		return err
+	}
+	if *namespace != "" {
+		hs.Namespace = *namespace
+	}
+	if hs.Namespace == "" {
+		hs.Namespace = metav1.NamespaceDefault
+	}
+
+	cfg, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	client, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	live, err := client.NevercaseV1().HelixSagas(hs.Namespace).Get(ctx, hs.Name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		live = nil
+	}
+	// the listers of the workloads and the Services were registered by the KubernetesResource
+	factory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(hs.Namespace))
+	ks := k8scorev1.NewKubernetesResource(kubeClient, factory)
+	factory.Start(ctx.Done())
+	for t, ok := range factory.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			return fmt.Errorf("the cache of %v hadn't been synced", t)
+		}
+	}
+	p, err := crd.Plan(ks, live, hs)
+	if err != nil {
+		return err
+	}
+	if *output == "json" {
+		t, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(t))
+		return err
+	}
+	_, err = fmt.Fprint(os.Stdout, p.String())
+	return err
+}
+
+// decodeHelixSaga decodes the HelixSaga in any of the served versions, and converts it into the storage version v1
+func decodeHelixSaga(data []byte) (*helixsagav1.HelixSaga, error) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(helixsagav1.AddToScheme(scheme))
+	utilruntime.Must(helixsagav2.AddToScheme(scheme))
+	obj, gvk, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	out, err := scheme.ConvertToVersion(obj, helixsagav1.SchemeGroupVersion)
+	if err != nil {
+		return nil, err
+	}
+	hs, ok := out.(*helixsagav1.HelixSaga)
+	if !ok {
+		return nil, fmt.Errorf("%s was not a HelixSaga", gvk)
+	}
+	return hs, nil
+}
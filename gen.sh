@@ -6,14 +6,15 @@ export GOPATH=`go env | grep -i gopath | awk '{split($0,a,"\""); print a[2]}'`
 ROOT_PACKAGE="github.com/Shanghai-Lunara/helixsaga-operator"
 # API Group
 CUSTOM_RESOURCE_NAME="helixsaga"
-# API Version
-CUSTOM_RESOURCE_VERSION="v1"
+CUSTOM_RESOURCE_GROUP="nevercase.io"
+# API Versions
+CUSTOM_RESOURCE_VERSION="v1,v2"
 
 GENS="$1"
 
 if [ "${GENS}" = "crd" ] || grep -qw "crd" <<<"${GENS}"; then
   cp ${GOPATH}/bin/go-to-protobuf-crd ${GOPATH}/bin/go-to-protobuf
-  Packages="$ROOT_PACKAGE/pkg/apis/$CUSTOM_RESOURCE_NAME/$CUSTOM_RESOURCE_VERSION"
+  Packages="$ROOT_PACKAGE/pkg/apis/$CUSTOM_RESOURCE_NAME/v1,$ROOT_PACKAGE/pkg/apis/$CUSTOM_RESOURCE_NAME/v2"
   "${GOPATH}/bin/go-to-protobuf" \
      --packages "${Packages}" \
      --clean=false \
@@ -23,5 +24,47 @@ if [ "${GENS}" = "crd" ] || grep -qw "crd" <<<"${GENS}"; then
      --proto-import ${GOPATH}/src/k8s.io/api/core/v1
 fi
 
+# 由 types.go 中的 +kubebuilder 标记生成 CRD，每个 app 有各自的 replicas，所以 HelixSaga 没有 scale 子资源
+if [ "${GENS}" = "manifests" ] || grep -qw "manifests" <<<"${GENS}"; then
+  CRD_DIR=$(mktemp -d)
+  "${GOPATH}/bin/controller-gen" crd:crdVersions=v1 paths=./pkg/apis/... output:crd:artifacts:config=${CRD_DIR}
+  # controller-gen 不生成 conversion，插入到 spec 下
+  cat > ${CRD_DIR}/conversion.yaml <<'EOF_CONVERSION'
+  # The versions were converted by the operator started with the flag -conversion-addr
+  conversion:
+    strategy: Webhook
+    webhook:
+      conversionReviewVersions: ["v1", "v1beta1"]
+      clientConfig:
+        service:
+          namespace: default
+          name: helixsaga-operator
+          path: /convert
+          port: 9443
+        # The base64 CA bundle which signed the -conversion-cert
+        caBundle: Cg==
+EOF_CONVERSION
+  {
+    echo "# Generated by gen.sh manifests, DO NOT EDIT."
+    echo "# It's larger than the last-applied annotation, apply it with: kubectl apply --server-side -f helixsaga.yaml"
+    sed "/^spec:$/r ${CRD_DIR}/conversion.yaml" ${CRD_DIR}/${CUSTOM_RESOURCE_GROUP}_helixsagas.yaml
+  } > examples/helixsaga/helixsaga.yaml
+  # HelixSagaTemplate 和 HelixSagaSet 只有 v1，不需要 conversion
+  for KIND in helixsagatemplates:helixsagatemplate helixsagasets:helixsagaset; do
+    {
+      echo "# Generated by gen.sh manifests, DO NOT EDIT."
+      echo "# Apply it with: kubectl apply --server-side -f ${KIND##*:}.yaml"
+      cat ${CRD_DIR}/${CUSTOM_RESOURCE_GROUP}_${KIND%%:*}.yaml
+    } > examples/helixsaga/${KIND##*:}.yaml
+  done
+  rm -rf ${CRD_DIR}
+fi
+
 # 执行代码自动生成，其中pkg/client是生成目标目录，pkg/apis是类型定义目录
 ${GOPATH}/src/k8s.io/code-generator/generate-groups.sh all "$ROOT_PACKAGE/pkg/generated/$CUSTOM_RESOURCE_NAME" "$ROOT_PACKAGE/pkg/apis" "$CUSTOM_RESOURCE_NAME:$CUSTOM_RESOURCE_VERSION"
+
+# v2 和 v1 之间的转换函数，手写的转换在 pkg/apis/helixsaga/v2/conversion.go
+"${GOPATH}/bin/conversion-gen" \
+   --input-dirs "$ROOT_PACKAGE/pkg/apis/$CUSTOM_RESOURCE_NAME/v2" \
+   --output-file-base zz_generated.conversion \
+   --go-header-file ${GOPATH}/src/k8s.io/code-generator/hack/boilerplate.go.txt
@@ -5,8 +5,10 @@ go 1.17
 require (
 	github.com/Shanghai-Lunara/pkg v0.0.0-20210410040202-9b354dbed557
 	github.com/gogo/protobuf v1.3.1
+	github.com/google/gofuzz v1.1.0
 	github.com/nevercase/harbor-api v0.0.0-20210624094246-26016650ee56
 	github.com/nevercase/k8s-controller-custom-resource v0.0.0-20210410075810-b0742ab026e1
+	github.com/robfig/cron v1.0.0
 	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776
 	k8s.io/api v0.20.4
 	k8s.io/apimachinery v0.20.4
@@ -36,7 +38,6 @@ require (
 	github.com/golang/protobuf v1.4.3 // indirect
 	github.com/gomodule/redigo v2.0.0+incompatible // indirect
 	github.com/google/go-cmp v0.5.2 // indirect
-	github.com/google/gofuzz v1.1.0 // indirect
 	github.com/google/uuid v1.1.2 // indirect
 	github.com/googleapis/gnostic v0.4.1 // indirect
 	github.com/gorilla/mux v1.7.4 // indirect
@@ -53,7 +54,6 @@ require (
 	github.com/opencontainers/go-digest v1.0.0 // indirect
 	github.com/opencontainers/image-spec v1.0.1 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/robfig/cron v1.0.0 // indirect
 	github.com/shiena/ansicolor v0.0.0-20151119151921-a422bbe96644 // indirect
 	github.com/sirupsen/logrus v1.4.2 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
@@ -42,6 +42,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		SchemeGroupVersion,
 		&HelixSaga{},
 		&HelixSagaList{},
+		&HelixSagaTemplate{},
+		&HelixSagaTemplateList{},
+		&HelixSagaSet{},
+		&HelixSagaSetList{},
 	)
 
 	// register the type in the scheme
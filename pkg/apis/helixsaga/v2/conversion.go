@@ -0,0 +1,99 @@
+package v2
+
+import (
+	v1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	"k8s.io/apimachinery/pkg/conversion"
+)
+
+// Convert_v1_HelixSaga_To_v2_HelixSaga moves the statuses of the apps into the top-level status
+func Convert_v1_HelixSaga_To_v2_HelixSaga(in *v1.HelixSaga, out *HelixSaga, s conversion.Scope) error {
+	if err := autoConvert_v1_HelixSaga_To_v2_HelixSaga(in, out, s); err != nil {
+		return err
+	}
+	out.Status.Apps = nil
+	if in.Spec.Applications != nil {
+		out.Status.Apps = make([]HelixSagaAppStatus, len(in.Spec.Applications))
+		for i := range in.Spec.Applications {
+			if err := Convert_v1_HelixSagaAppStatus_To_v2_HelixSagaAppStatus(&in.Spec.Applications[i].Status, &out.Status.Apps[i], s); err != nil {
+				return err
+			}
+			out.Status.Apps[i].Name = in.Spec.Applications[i].Spec.Name
+		}
+	}
+	return nil
+}
+
+// Convert_v2_HelixSaga_To_v1_HelixSaga moves the statuses of the apps back into the apps
+func Convert_v2_HelixSaga_To_v1_HelixSaga(in *HelixSaga, out *v1.HelixSaga, s conversion.Scope) error {
+	if err := autoConvert_v2_HelixSaga_To_v1_HelixSaga(in, out, s); err != nil {
+		return err
+	}
+	for i := range out.Spec.Applications {
+		status := findAppStatus(in.Status.Apps, i, out.Spec.Applications[i].Spec.Name)
+		if status == nil {
+			continue
+		}
+		if err := Convert_v2_HelixSagaAppStatus_To_v1_HelixSagaAppStatus(status, &out.Spec.Applications[i].Status, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findAppStatus returns the status of the app at the index i, the one at the same index would be preferred
+func findAppStatus(statuses []HelixSagaAppStatus, i int, name string) *HelixSagaAppStatus {
+	if i < len(statuses) && statuses[i].Name == name {
+		return &statuses[i]
+	}
+	for j := range statuses {
+		if statuses[j].Name == name {
+			return &statuses[j]
+		}
+	}
+	return nil
+}
+
+// Convert_v1_HelixSagaSpec_To_v2_HelixSagaSpec converts the specs of the Applications into the Apps
+func Convert_v1_HelixSagaSpec_To_v2_HelixSagaSpec(in *v1.HelixSagaSpec, out *HelixSagaSpec, s conversion.Scope) error {
+	if err := autoConvert_v1_HelixSagaSpec_To_v2_HelixSagaSpec(in, out, s); err != nil {
+		return err
+	}
+	out.Apps = nil
+	if in.Applications != nil {
+		out.Apps = make([]HelixSagaAppSpec, len(in.Applications))
+		for i := range in.Applications {
+			if err := Convert_v1_HelixSagaAppSpec_To_v2_HelixSagaAppSpec(&in.Applications[i].Spec, &out.Apps[i], s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Convert_v2_HelixSagaSpec_To_v1_HelixSagaSpec converts the Apps into the specs of the Applications,
+// the statuses of them would be converted by the HelixSaga
+func Convert_v2_HelixSagaSpec_To_v1_HelixSagaSpec(in *HelixSagaSpec, out *v1.HelixSagaSpec, s conversion.Scope) error {
+	if err := autoConvert_v2_HelixSagaSpec_To_v1_HelixSagaSpec(in, out, s); err != nil {
+		return err
+	}
+	out.Applications = nil
+	if in.Apps != nil {
+		out.Applications = make([]v1.HelixSagaApp, len(in.Apps))
+		for i := range in.Apps {
+			if err := Convert_v2_HelixSagaAppSpec_To_v1_HelixSagaAppSpec(&in.Apps[i], &out.Applications[i].Spec, s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Convert_v2_HelixSagaAppStatus_To_v1_HelixSagaAppStatus drops the Name, which was the Name of the spec in v1
+func Convert_v2_HelixSagaAppStatus_To_v1_HelixSagaAppStatus(in *HelixSagaAppStatus, out *v1.HelixSagaAppStatus, s conversion.Scope) error {
+	return autoConvert_v2_HelixSagaAppStatus_To_v1_HelixSagaAppStatus(in, out, s)
+}
+
+// Convert_v2_HelixSagaStatus_To_v1_HelixSagaStatus drops the Apps, which were moved back into the apps by the HelixSaga
+func Convert_v2_HelixSagaStatus_To_v1_HelixSagaStatus(in *HelixSagaStatus, out *v1.HelixSagaStatus, s conversion.Scope) error {
+	return autoConvert_v2_HelixSagaStatus_To_v1_HelixSagaStatus(in, out, s)
+}
@@ -0,0 +1,150 @@
+package v2
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	v1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	fuzz "github.com/google/gofuzz"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// fuzzerFuncs keeps the fuzzed objects valid for the json
+func fuzzerFuncs(codecs runtimeserializer.CodecFactory) []interface{} {
+	return []interface{}{
+		func(j *intstr.IntOrString, c fuzz.Continue) {
+			if c.RandBool() {
+				*j = intstr.FromInt(int(c.Int31()))
+			} else {
+				*j = intstr.FromString(c.RandString())
+			}
+		},
+		func(j *metav1.Time, c fuzz.Continue) {
+			j.Time = time.Unix(c.Int63n(1<<32), 0).UTC()
+		},
+		func(j *metav1.ObjectMeta, c fuzz.Continue) {
+			// the timestamps out of the range of RFC 3339 would be zero, which were lost in the json
+			c.FuzzNoCustom(j)
+		},
+		func(j *HelixSaga, c fuzz.Continue) {
+			c.FuzzNoCustom(j)
+			// the statuses were attached to the apps in v1
+			if j.Spec.Apps == nil {
+				j.Status.Apps = nil
+				return
+			}
+			statuses := make([]HelixSagaAppStatus, len(j.Spec.Apps))
+			for i := range j.Spec.Apps {
+				if i < len(j.Status.Apps) {
+					statuses[i] = j.Status.Apps[i]
+				}
+				statuses[i].Name = j.Spec.Apps[i].Name
+			}
+			j.Status.Apps = statuses
+		},
+	}
+}
+
+func newFuzzer(t *testing.T, s *runtime.Scheme) *fuzz.Fuzzer {
+	seed := rand.Int63()
+	t.Logf("seed: %d", seed)
+	funcs := fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, fuzzerFuncs)
+	return fuzzer.FuzzerFor(funcs, rand.NewSource(seed), runtimeserializer.NewCodecFactory(s))
+}
+
+func TestRoundTripV1(t *testing.T) {
+	s := newScheme(t)
+	f := newFuzzer(t, s)
+	for i := 0; i < 200; i++ {
+		in := &v1.HelixSaga{}
+		f.Fuzz(in)
+		hub := &HelixSaga{}
+		if err := s.Convert(in, hub, nil); err != nil {
+			t.Fatal(err)
+		}
+		out := &v1.HelixSaga{}
+		if err := s.Convert(hub, out, nil); err != nil {
+			t.Fatal(err)
+		}
+		if !apiequality.Semantic.DeepEqual(in, out) {
+			t.Fatalf("v1 -> v2 -> v1 was not lossless: %s", diff.ObjectReflectDiff(in, out))
+		}
+	}
+}
+
+func TestRoundTripV2(t *testing.T) {
+	s := newScheme(t)
+	f := newFuzzer(t, s)
+	for i := 0; i < 200; i++ {
+		in := &HelixSaga{}
+		f.Fuzz(in)
+		hub := &v1.HelixSaga{}
+		if err := s.Convert(in, hub, nil); err != nil {
+			t.Fatal(err)
+		}
+		out := &HelixSaga{}
+		if err := s.Convert(hub, out, nil); err != nil {
+			t.Fatal(err)
+		}
+		if !apiequality.Semantic.DeepEqual(in, out) {
+			t.Fatalf("v2 -> v1 -> v2 was not lossless: %s", diff.ObjectReflectDiff(in, out))
+		}
+	}
+}
+
+func TestConvertHelixSaga(t *testing.T) {
+	s := newScheme(t)
+	replicas := int32(2)
+	in := &HelixSaga{
+		ObjectMeta: metav1.ObjectMeta{Name: "hs", Namespace: "default"},
+		Spec: HelixSagaSpec{
+			Defaults: &HelixSagaAppDefaults{Image: "harbor.domain.com/helix-saga/go-all:latest"},
+			Apps: []HelixSagaAppSpec{
+				{Name: "game", Replicas: &replicas},
+				{Name: "lobby"},
+			},
+		},
+		Status: HelixSagaStatus{
+			Apps: []HelixSagaAppStatus{
+				{Name: "lobby", CurrentImage: ImageRecord{Digest: "sha256:2"}},
+				{Name: "game", CurrentImage: ImageRecord{Digest: "sha256:1"}},
+			},
+		},
+	}
+	out := &v1.HelixSaga{}
+	if err := s.Convert(in, out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Spec.Applications) != 2 {
+		t.Fatalf("Convert() applications = %v", out.Spec.Applications)
+	}
+	game, lobby := out.Spec.Applications[0], out.Spec.Applications[1]
+	if game.Spec.Name != "game" || *game.Spec.Replicas != 2 || game.Status.CurrentImage.Digest != "sha256:1" {
+		t.Errorf("Convert() game = %+v", game)
+	}
+	if lobby.Spec.Name != "lobby" || lobby.Status.CurrentImage.Digest != "sha256:2" {
+		t.Errorf("Convert() lobby = %+v", lobby)
+	}
+	if out.Spec.Defaults == nil || out.Spec.Defaults.Image != in.Spec.Defaults.Image {
+		t.Errorf("Convert() defaults = %+v", out.Spec.Defaults)
+	}
+}
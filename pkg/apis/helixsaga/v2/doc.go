@@ -0,0 +1,8 @@
+// +k8s:deepcopy-gen=package
+// +k8s:conversion-gen=github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1
+
+// Package v2 is the v2 version of the HelixSaga API.
+// The apps are a list keyed by the name, and their statuses were moved into the top-level status.
+// It's converted from and to the storage version v1 by the conversion webhook.
+// +groupName=nevercase.io
+package v2
@@ -0,0 +1,937 @@
+package v2
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	_ "k8s.io/apimachinery/pkg/apis/testapigroup/v1"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=hs
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.ready`,description="The ready apps out of all the apps"
+// +kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.status.image`,description="The image of the apps"
+// +kubebuilder:printcolumn:name="Maintenance",type=string,JSONPath=`.status.maintenance`,description="The message of the maintenance",priority=1
+// +kubebuilder:printcolumn:name="Paused",type=boolean,JSONPath=`.spec.paused`,description="Whether the reconciliation was paused",priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// HelixSaga describes a HelixSaga resource
+type HelixSaga struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec is the desired state of the apps
+	Spec HelixSagaSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+	// Status is the most recently observed status of the apps
+	// +optional
+	Status HelixSagaStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// HelixSagaSpec is the spec for a HelixSaga resource
+type HelixSagaSpec struct {
+	// ConfigMap is the volume which would be mounted into all the apps
+	// +optional
+	ConfigMap HelixSagaConfigMap `json:"configMap,omitempty" protobuf:"bytes,1,opt,name=configMap"`
+	// UpdateWindow is the default UpdateWindow of the apps
+	// +optional
+	UpdateWindow *UpdateWindow `json:"updateWindow,omitempty" protobuf:"bytes,2,opt,name=updateWindow"`
+	// Defaults are the fields shared by all the apps
+	// +optional
+	Defaults *HelixSagaAppDefaults `json:"defaults,omitempty" protobuf:"bytes,3,opt,name=defaults"`
+	// Apps are the applications of the HelixSaga keyed by the name
+	// +listType=map
+	// +listMapKey=name
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Apps []HelixSagaAppSpec `json:"apps" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,4,rep,name=apps"`
+	// Maintenance stops the apps during the maintenance of the servers
+	// +optional
+	Maintenance *Maintenance `json:"maintenance,omitempty" protobuf:"bytes,5,opt,name=maintenance"`
+	// Paused stops correcting the drift of all the apps and applying the automatic image updates,
+	// the statuses and the drift of the apps would still be reported
+	// +optional
+	Paused bool `json:"paused,omitempty" protobuf:"varint,6,opt,name=paused"`
+}
+
+// Maintenance stops all the apps of the HelixSaga except the ones which were kept running
+type Maintenance struct {
+	// Enabled scales the apps to zero, and they would be scaled back to their previous replicas after it has been disabled
+	// +optional
+	Enabled bool `json:"enabled,omitempty" protobuf:"varint,1,opt,name=enabled"`
+	// KeepRunning are the names of the apps which would not be stopped, like the version or the notice server
+	// +optional
+	// +listType=set
+	KeepRunning []string `json:"keepRunning,omitempty" protobuf:"bytes,2,rep,name=keepRunning"`
+	// Message is the reason of the maintenance which was shown in the status
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,3,opt,name=message"`
+}
+
+// HelixSagaStatus is the status for a HelixSaga resource
+type HelixSagaStatus struct {
+	// Apps are the statuses of the applications keyed by the name
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Apps []HelixSagaAppStatus `json:"apps,omitempty" protobuf:"bytes,1,rep,name=apps"`
+	// Ready is the number of the ready apps out of all the apps like 2/3.
+	// An app was ready if all the desired replicas of it were ready.
+	// +optional
+	Ready string `json:"ready,omitempty" protobuf:"bytes,2,opt,name=ready"`
+	// Image is the image of the apps, the different images were joined by the comma
+	// +optional
+	Image string `json:"image,omitempty" protobuf:"bytes,3,opt,name=image"`
+	// Maintenance is the Message of the Maintenance while any app was stopped by it
+	// +optional
+	Maintenance string `json:"maintenance,omitempty" protobuf:"bytes,4,opt,name=maintenance"`
+	// Conditions are the latest observations of the HelixSaga, like the Paused
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,5,rep,name=conditions"`
+}
+
+// UpdateWindow is the recurring time window in which the automatic image updates could be applied.
+// The updates detected out of the window would be pending until the window opens.
+type UpdateWindow struct {
+	// Schedule is the cron expression of the start of the window in the form of
+	// "minute hour day-of-month month day-of-week", or a descriptor like "@daily".
+	Schedule string `json:"schedule" protobuf:"bytes,1,opt,name=schedule"`
+	// Duration is how long the window keeps open after the start.
+	Duration metav1.Duration `json:"duration" protobuf:"bytes,2,opt,name=duration"`
+	// TimeZone is the IANA name of the time zone of the Schedule like "Asia/Shanghai".
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty" protobuf:"bytes,3,opt,name=timeZone"`
+}
+
+// HelixSagaConfigMap is a volume and the mount of it
+type HelixSagaConfigMap struct {
+	Volume      corev1.Volume      `json:"volume" protobuf:"bytes,1,opt,name=volume"`
+	VolumeMount corev1.VolumeMount `json:"volumeMount" protobuf:"bytes,2,opt,name=volumeMount"`
+}
+
+// The empty value was accepted for the HelixSagas which have been stored before the field was optional.
+// +kubebuilder:validation:Enum="";auto;manual
+type WatchPolicy string
+
+const (
+	WatchPolicyAuto   WatchPolicy = "auto"
+	WatchPolicyManual WatchPolicy = "manual"
+)
+
+// +kubebuilder:validation:Enum=scaleToZero;restart;digestPin
+type UpdateTrigger string
+
+const (
+	// UpdateTriggerScaleToZero scales the app down to zero and back up again, all the pods would pull the image again
+	UpdateTriggerScaleToZero UpdateTrigger = "scaleToZero"
+	// UpdateTriggerRestart records the new digest in the pod template annotations, then the pods would be rolled
+	UpdateTriggerRestart UpdateTrigger = "restart"
+	// UpdateTriggerDigestPin pins the app to the new digest, then the pods would be rolled with image@sha256:...
+	UpdateTriggerDigestPin UpdateTrigger = "digestPin"
+)
+
+// +kubebuilder:validation:Enum=semver;regex;newest
+type ImagePolicyType string
+
+const (
+	// ImagePolicyTypeSemver selects the highest semantic version in the Range
+	ImagePolicyTypeSemver ImagePolicyType = "semver"
+	// ImagePolicyTypeRegex selects the newest pushed tag which matches the Pattern
+	ImagePolicyTypeRegex ImagePolicyType = "regex"
+	// ImagePolicyTypeNewest selects the newest pushed tag
+	ImagePolicyTypeNewest ImagePolicyType = "newest"
+)
+
+// +kubebuilder:validation:Enum=PreUpdate;PostUpdate
+type HookType string
+
+const (
+	// HookTypePreUpdate runs before the new image was rolled out
+	HookTypePreUpdate HookType = "PreUpdate"
+	// HookTypePostUpdate runs after the new image has been rolled out
+	HookTypePostUpdate HookType = "PostUpdate"
+)
+
+// +kubebuilder:validation:Enum=Running;Succeeded;Failed
+type HookPhase string
+
+const (
+	HookPhaseRunning   HookPhase = "Running"
+	HookPhaseSucceeded HookPhase = "Succeeded"
+	HookPhaseFailed    HookPhase = "Failed"
+)
+
+// +kubebuilder:validation:Enum=ScalingDown;ScaledDown
+type ImageUpdatePhase string
+
+const (
+	// ImageUpdatePhaseScalingDown was recorded before the app was scaled down
+	ImageUpdatePhaseScalingDown ImageUpdatePhase = "ScalingDown"
+	// ImageUpdatePhaseScaledDown was recorded together with the replicas of zero
+	ImageUpdatePhaseScaledDown ImageUpdatePhase = "ScaledDown"
+)
+
+// +kubebuilder:validation:Enum=Creating;Switched
+type TemplateMigrationPhase string
+
+const (
+	// TemplateMigrationPhaseCreating was recorded while the new workload was not ready, the old one was still serving
+	TemplateMigrationPhaseCreating TemplateMigrationPhase = "Creating"
+	// TemplateMigrationPhaseSwitched was recorded after the Service has been moved to the new workload,
+	// and the old one would be deleted
+	TemplateMigrationPhaseSwitched TemplateMigrationPhase = "Switched"
+)
+
+// The empty value was accepted for the HelixSagas which have been stored before the field was optional.
+// +kubebuilder:validation:Enum="";Deployment;StatefulSet
+type TemplateType string
+
+const (
+	TemplateTypeDeployment  TemplateType = "Deployment"
+	TemplateTypeStatefulSet TemplateType = "StatefulSet"
+)
+
+// HelixSagaAppSpec is the spec of an application of a HelixSaga
+type HelixSagaAppSpec struct {
+	// Name of the container specified as a DNS_LABEL.
+	// Each container in a pod must have a unique name (DNS_LABEL).
+	// Cannot be updated.
+	// It's the key of the apps.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Replicas is the number of desired replicas.
+	// This is a pointer to distinguish between explicit zero and unspecified.
+	// Defaults to 1.
+	// More info: https://kubernetes.io/docs/concepts/workloads/controllers/replicationcontroller#what-is-a-replicationcontroller
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty" protobuf:"varint,2,opt,name=replicas"`
+	// Docker image name.
+	// More info: https://kubernetes.io/docs/concepts/containers/images
+	// This field is optional to allow higher level config management to default or override
+	// container images in workload controllers like Deployments and StatefulSets.
+	// +optional
+	Image string `json:"image,omitempty" protobuf:"bytes,3,opt,name=image"`
+	// ImagePullSecrets is an optional list of references to secrets in the same namespace to use for pulling any of the images used by this PodSpec.
+	// If specified, these secrets will be passed to individual puller implementations for them to use. For example,
+	// in the case of docker, only DockerConfig type secrets are honored.
+	// More info: https://kubernetes.io/docs/concepts/containers/images#specifying-imagepullsecrets-on-a-pod
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,4,rep,name=imagePullSecrets"`
+	// List of environment variables to set in the container.
+	// Cannot be updated.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Env []corev1.EnvVar `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,5,rep,name=env"`
+	// Resources represents the minimum resources the volume should have.
+	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#resources
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,6,opt,name=resources"`
+	// Pod volumes to mount into the container's filesystem.
+	// Cannot be updated.
+	// +optional
+	// +patchMergeKey=mountPath
+	// +patchStrategy=merge
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty" patchStrategy:"merge" patchMergeKey:"mountPath" protobuf:"bytes,7,rep,name=volumeMounts"`
+	// Entrypoint array. Not executed within a shell.
+	// The docker image's ENTRYPOINT is used if this is not provided.
+	// Variable references $(VAR_NAME) are expanded using the container's environment. If a variable
+	// cannot be resolved, the reference in the input string will be unchanged. The $(VAR_NAME) syntax
+	// can be escaped with a double $$, ie: $$(VAR_NAME). Escaped references will never be expanded,
+	// regardless of whether the variable exists or not.
+	// Cannot be updated.
+	// More info: https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#running-a-command-in-a-shell
+	// +optional
+	Command []string `json:"command,omitempty" protobuf:"bytes,8,rep,name=command"`
+	// Arguments to the entrypoint.
+	// The docker image's CMD is used if this is not provided.
+	// Variable references $(VAR_NAME) are expanded using the container's environment. If a variable
+	// cannot be resolved, the reference in the input string will be unchanged. The $(VAR_NAME) syntax
+	// can be escaped with a double $$, ie: $$(VAR_NAME). Escaped references will never be expanded,
+	// regardless of whether the variable exists or not.
+	// Cannot be updated.
+	// More info: https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#running-a-command-in-a-shell
+	// +optional
+	Args []string `json:"args,omitempty" protobuf:"bytes,9,rep,name=args"`
+	// List of ports to expose from the container. Exposing a port here gives
+	// the system additional information about the network connections a
+	// container uses, but is primarily informational. Not specifying a port here
+	// DOES NOT prevent that port from being exposed. Any port which is
+	// listening on the default "0.0.0.0" address inside a container will be
+	// accessible from the network.
+	// Cannot be updated.
+	// +optional
+	// +patchMergeKey=containerPort
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=containerPort
+	// +listMapKey=protocol
+	ContainerPorts []corev1.ContainerPort `json:"containerPorts,omitempty" patchStrategy:"merge" patchMergeKey:"containerPort" protobuf:"bytes,10,rep,name=containerPorts"`
+	// The list of ports that are exposed by this service.
+	// More info: https://kubernetes.io/docs/concepts/services-networking/service/#virtual-ips-and-service-proxies
+	// +patchMergeKey=port
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=port
+	// +listMapKey=protocol
+	ServicePorts []corev1.ServicePort `json:"servicePorts,omitempty" patchStrategy:"merge" patchMergeKey:"port" protobuf:"bytes,11,rep,name=servicePorts"`
+	// The type of services.
+	// The empty value was accepted for the HelixSagas which have been stored before the field was optional.
+	// +optional
+	// +kubebuilder:validation:Enum="";ClusterIP;NodePort;LoadBalancer;ExternalName
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty" protobuf:"bytes,12,opt,name=serviceType,casttype=k8s.io/api/core/v1.ServiceType"`
+	// The path of the nas disk which was mounted on the machine
+	// +optional
+	VolumePath string `json:"volumePath,omitempty" protobuf:"bytes,13,opt,name=volumePath"`
+	// Watch policy for the present app.
+	// One of Auto, Manual.
+	// Default to Manual.
+	// +optional
+	WatchPolicy WatchPolicy `json:"watchPolicy,omitempty" protobuf:"bytes,14,opt,name=watchPolicy,casttype=WatchPolicy"`
+	// NodeSelector is a selector which must be true for the pod to fit on a node.
+	// Selector which must match a node's labels for the pod to be scheduled on that node.
+	// More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" protobuf:"bytes,15,rep,name=nodeSelector"`
+	// ServiceAccountName is the name of the ServiceAccount to use to run this pod.
+	// More info: https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty" protobuf:"bytes,16,opt,name=serviceAccountName"`
+	// If specified, the pod's scheduling constraints
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty" protobuf:"bytes,17,opt,name=affinity"`
+	// If specified, the pod's tolerations.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty" protobuf:"bytes,18,rep,name=tolerations"`
+	// Template was the type of the resource which would be created by the custom operator.
+	// Defaults to StatefulSet. After it has been switched, the old workload would be kept serving
+	// until the new one has been ready, see the status.templateMigration.
+	// +optional
+	Template TemplateType `json:"template,omitempty" protobuf:"bytes,19,opt,name=template,casttype=TemplateType"`
+	// ServiceWhiteList
+	// +optional
+	ServiceWhiteList bool `json:"serviceWhiteList,omitempty" protobuf:"varint,20,opt,name=serviceWhiteList"`
+	// ServiceSourceRanges is the allowlist of the CIDRs which were allowed to access the LoadBalancer Service.
+	// The allowlist takes precedence over the ServiceWhiteList once it's not empty.
+	// +optional
+	ServiceSourceRanges []string `json:"serviceSourceRanges,omitempty" protobuf:"bytes,21,rep,name=serviceSourceRanges"`
+	// ServiceSourceRangeRefs are the names of the shared allowlists which were defined in the serviceloadbalancer config.
+	// The CIDRs of them would be merged into the ServiceSourceRanges.
+	// +optional
+	ServiceSourceRangeRefs []string `json:"serviceSourceRangeRefs,omitempty" protobuf:"bytes,22,rep,name=serviceSourceRangeRefs"`
+	// PodService describes the Services which would be created for every pod of a StatefulSet app,
+	// so that the clients could connect to a specific pod directly.
+	// It only works with the StatefulSet template. The Services were named <name>-<ordinal>,
+	// and the HelixSaga would not be synced if another app was named in the form.
+	// +optional
+	PodService *PodServiceSpec `json:"podService,omitempty" protobuf:"bytes,23,opt,name=podService"`
+	// PinDigest resolves the tag of the Image to a digest and deploys the image in the form of image@sha256:...,
+	// so that the pods would never run different contents of a mutable tag.
+	// The digest would be moved forward once a new push of the tag has been observed.
+	// +optional
+	PinDigest bool `json:"pinDigest,omitempty" protobuf:"varint,24,opt,name=pinDigest"`
+	// RollbackTo is a digest like sha256:... which would be redeployed instead of the one of the tag,
+	// and the app would be held on it until the RollbackTo has been cleared.
+	// It's usually one of the digests in the status.imageHistory. After the RollbackTo has been cleared,
+	// the app keeps running the digest until a new push of the tag has been observed.
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	// +optional
+	RollbackTo string `json:"rollbackTo,omitempty" protobuf:"bytes,25,opt,name=rollbackTo"`
+	// ImageHistoryLimit is the number of the previous digests which were kept in the status.imageHistory.
+	// Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ImageHistoryLimit *int32 `json:"imageHistoryLimit,omitempty" protobuf:"varint,26,opt,name=imageHistoryLimit"`
+	// Image pull policy.
+	// One of Always, Never, IfNotPresent.
+	// Defaults to Always.
+	// IfNotPresent was only safe with the UpdateTrigger digestPin, which deploys a new image reference for every push.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty" protobuf:"bytes,27,opt,name=imagePullPolicy,casttype=k8s.io/api/core/v1.PullPolicy"`
+	// UpdateTrigger is how a new digest of the image would be rolled out for the app with WatchPolicy auto.
+	// One of scaleToZero, restart, digestPin.
+	// Defaults to scaleToZero.
+	// +optional
+	UpdateTrigger UpdateTrigger `json:"updateTrigger,omitempty" protobuf:"bytes,28,opt,name=updateTrigger,casttype=UpdateTrigger"`
+	// ImagePolicy selects the tag of the Image from the tags of the repository in the registry.
+	// The tag of the Image would be replaced by the selected one.
+	// +optional
+	ImagePolicy *ImagePolicy `json:"imagePolicy,omitempty" protobuf:"bytes,29,opt,name=imagePolicy"`
+	// UpdateWindow overrides the UpdateWindow of the HelixSaga for the app
+	// +optional
+	UpdateWindow *UpdateWindow `json:"updateWindow,omitempty" protobuf:"bytes,30,opt,name=updateWindow"`
+	// UpdateHooks are the Jobs which were run around the rollout of a new image
+	// +optional
+	UpdateHooks *UpdateHooks `json:"updateHooks,omitempty" protobuf:"bytes,31,opt,name=updateHooks"`
+	// DependsOn are the names of the apps which would be ready before the app was created or scaled up.
+	// The app would be scaled down before the apps it depends on, and it couldn't be set in the Defaults.
+	// +optional
+	// +listType=set
+	DependsOn []string `json:"dependsOn,omitempty" protobuf:"bytes,32,rep,name=dependsOn"`
+	// Schedules scale the app to their Replicas at every start of their Schedule.
+	// The replicas which have been edited after a start would be kept until the next start of any Schedule.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=name
+	Schedules []ReplicaSchedule `json:"schedules,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,33,rep,name=schedules"`
+	// Paused stops correcting the drift of the app and applying the automatic image updates of it,
+	// e.g. the manual hotfix of the workload would be kept during an incident.
+	// The Paused of the Defaults couldn't be overridden by the app.
+	// +optional
+	Paused bool `json:"paused,omitempty" protobuf:"varint,34,opt,name=paused"`
+}
+
+// HelixSagaAppDefaults are the fields of the HelixSagaAppSpec which were shared by all the apps.
+// The Name and the DependsOn were left out, since they were the keys of every app.
+type HelixSagaAppDefaults struct {
+	// Replicas is the number of desired replicas.
+	// This is a pointer to distinguish between explicit zero and unspecified.
+	// Defaults to 1.
+	// More info: https://kubernetes.io/docs/concepts/workloads/controllers/replicationcontroller#what-is-a-replicationcontroller
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty" protobuf:"varint,2,opt,name=replicas"`
+	// Docker image name.
+	// More info: https://kubernetes.io/docs/concepts/containers/images
+	// This field is optional to allow higher level config management to default or override
+	// container images in workload controllers like Deployments and StatefulSets.
+	// +optional
+	Image string `json:"image,omitempty" protobuf:"bytes,3,opt,name=image"`
+	// ImagePullSecrets is an optional list of references to secrets in the same namespace to use for pulling any of the images used by this PodSpec.
+	// If specified, these secrets will be passed to individual puller implementations for them to use. For example,
+	// in the case of docker, only DockerConfig type secrets are honored.
+	// More info: https://kubernetes.io/docs/concepts/containers/images#specifying-imagepullsecrets-on-a-pod
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,4,rep,name=imagePullSecrets"`
+	// List of environment variables to set in the container.
+	// Cannot be updated.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Env []corev1.EnvVar `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,5,rep,name=env"`
+	// Resources represents the minimum resources the volume should have.
+	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#resources
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,6,opt,name=resources"`
+	// Pod volumes to mount into the container's filesystem.
+	// Cannot be updated.
+	// +optional
+	// +patchMergeKey=mountPath
+	// +patchStrategy=merge
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty" patchStrategy:"merge" patchMergeKey:"mountPath" protobuf:"bytes,7,rep,name=volumeMounts"`
+	// Entrypoint array. Not executed within a shell.
+	// The docker image's ENTRYPOINT is used if this is not provided.
+	// Variable references $(VAR_NAME) are expanded using the container's environment. If a variable
+	// cannot be resolved, the reference in the input string will be unchanged. The $(VAR_NAME) syntax
+	// can be escaped with a double $$, ie: $$(VAR_NAME). Escaped references will never be expanded,
+	// regardless of whether the variable exists or not.
+	// Cannot be updated.
+	// More info: https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#running-a-command-in-a-shell
+	// +optional
+	Command []string `json:"command,omitempty" protobuf:"bytes,8,rep,name=command"`
+	// Arguments to the entrypoint.
+	// The docker image's CMD is used if this is not provided.
+	// Variable references $(VAR_NAME) are expanded using the container's environment. If a variable
+	// cannot be resolved, the reference in the input string will be unchanged. The $(VAR_NAME) syntax
+	// can be escaped with a double $$, ie: $$(VAR_NAME). Escaped references will never be expanded,
+	// regardless of whether the variable exists or not.
+	// Cannot be updated.
+	// More info: https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#running-a-command-in-a-shell
+	// +optional
+	Args []string `json:"args,omitempty" protobuf:"bytes,9,rep,name=args"`
+	// List of ports to expose from the container. Exposing a port here gives
+	// the system additional information about the network connections a
+	// container uses, but is primarily informational. Not specifying a port here
+	// DOES NOT prevent that port from being exposed. Any port which is
+	// listening on the default "0.0.0.0" address inside a container will be
+	// accessible from the network.
+	// Cannot be updated.
+	// +optional
+	// +patchMergeKey=containerPort
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=containerPort
+	// +listMapKey=protocol
+	ContainerPorts []corev1.ContainerPort `json:"containerPorts,omitempty" patchStrategy:"merge" patchMergeKey:"containerPort" protobuf:"bytes,10,rep,name=containerPorts"`
+	// The list of ports that are exposed by this service.
+	// More info: https://kubernetes.io/docs/concepts/services-networking/service/#virtual-ips-and-service-proxies
+	// +patchMergeKey=port
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=port
+	// +listMapKey=protocol
+	ServicePorts []corev1.ServicePort `json:"servicePorts,omitempty" patchStrategy:"merge" patchMergeKey:"port" protobuf:"bytes,11,rep,name=servicePorts"`
+	// The type of services.
+	// The empty value was accepted for the HelixSagas which have been stored before the field was optional.
+	// +optional
+	// +kubebuilder:validation:Enum="";ClusterIP;NodePort;LoadBalancer;ExternalName
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty" protobuf:"bytes,12,opt,name=serviceType,casttype=k8s.io/api/core/v1.ServiceType"`
+	// The path of the nas disk which was mounted on the machine
+	// +optional
+	VolumePath string `json:"volumePath,omitempty" protobuf:"bytes,13,opt,name=volumePath"`
+	// Watch policy for the present app.
+	// One of Auto, Manual.
+	// Default to Manual.
+	// +optional
+	WatchPolicy WatchPolicy `json:"watchPolicy,omitempty" protobuf:"bytes,14,opt,name=watchPolicy,casttype=WatchPolicy"`
+	// NodeSelector is a selector which must be true for the pod to fit on a node.
+	// Selector which must match a node's labels for the pod to be scheduled on that node.
+	// More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" protobuf:"bytes,15,rep,name=nodeSelector"`
+	// ServiceAccountName is the name of the ServiceAccount to use to run this pod.
+	// More info: https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty" protobuf:"bytes,16,opt,name=serviceAccountName"`
+	// If specified, the pod's scheduling constraints
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty" protobuf:"bytes,17,opt,name=affinity"`
+	// If specified, the pod's tolerations.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty" protobuf:"bytes,18,rep,name=tolerations"`
+	// Template was the type of the resource which would be created by the custom operator.
+	// Defaults to StatefulSet. After it has been switched, the old workload would be kept serving
+	// until the new one has been ready, see the status.templateMigration.
+	// +optional
+	Template TemplateType `json:"template,omitempty" protobuf:"bytes,19,opt,name=template,casttype=TemplateType"`
+	// ServiceWhiteList
+	// +optional
+	ServiceWhiteList bool `json:"serviceWhiteList,omitempty" protobuf:"varint,20,opt,name=serviceWhiteList"`
+	// ServiceSourceRanges is the allowlist of the CIDRs which were allowed to access the LoadBalancer Service.
+	// The allowlist takes precedence over the ServiceWhiteList once it's not empty.
+	// +optional
+	ServiceSourceRanges []string `json:"serviceSourceRanges,omitempty" protobuf:"bytes,21,rep,name=serviceSourceRanges"`
+	// ServiceSourceRangeRefs are the names of the shared allowlists which were defined in the serviceloadbalancer config.
+	// The CIDRs of them would be merged into the ServiceSourceRanges.
+	// +optional
+	ServiceSourceRangeRefs []string `json:"serviceSourceRangeRefs,omitempty" protobuf:"bytes,22,rep,name=serviceSourceRangeRefs"`
+	// PodService describes the Services which would be created for every pod of a StatefulSet app,
+	// so that the clients could connect to a specific pod directly.
+	// It only works with the StatefulSet template. The Services were named <name>-<ordinal>,
+	// and the HelixSaga would not be synced if another app was named in the form.
+	// +optional
+	PodService *PodServiceSpec `json:"podService,omitempty" protobuf:"bytes,23,opt,name=podService"`
+	// PinDigest resolves the tag of the Image to a digest and deploys the image in the form of image@sha256:...,
+	// so that the pods would never run different contents of a mutable tag.
+	// The digest would be moved forward once a new push of the tag has been observed.
+	// +optional
+	PinDigest bool `json:"pinDigest,omitempty" protobuf:"varint,24,opt,name=pinDigest"`
+	// RollbackTo is a digest like sha256:... which would be redeployed instead of the one of the tag,
+	// and the app would be held on it until the RollbackTo has been cleared.
+	// It's usually one of the digests in the status.imageHistory. After the RollbackTo has been cleared,
+	// the app keeps running the digest until a new push of the tag has been observed.
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	// +optional
+	RollbackTo string `json:"rollbackTo,omitempty" protobuf:"bytes,25,opt,name=rollbackTo"`
+	// ImageHistoryLimit is the number of the previous digests which were kept in the status.imageHistory.
+	// Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ImageHistoryLimit *int32 `json:"imageHistoryLimit,omitempty" protobuf:"varint,26,opt,name=imageHistoryLimit"`
+	// Image pull policy.
+	// One of Always, Never, IfNotPresent.
+	// Defaults to Always.
+	// IfNotPresent was only safe with the UpdateTrigger digestPin, which deploys a new image reference for every push.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty" protobuf:"bytes,27,opt,name=imagePullPolicy,casttype=k8s.io/api/core/v1.PullPolicy"`
+	// UpdateTrigger is how a new digest of the image would be rolled out for the app with WatchPolicy auto.
+	// One of scaleToZero, restart, digestPin.
+	// Defaults to scaleToZero.
+	// +optional
+	UpdateTrigger UpdateTrigger `json:"updateTrigger,omitempty" protobuf:"bytes,28,opt,name=updateTrigger,casttype=UpdateTrigger"`
+	// ImagePolicy selects the tag of the Image from the tags of the repository in the registry.
+	// The tag of the Image would be replaced by the selected one.
+	// +optional
+	ImagePolicy *ImagePolicy `json:"imagePolicy,omitempty" protobuf:"bytes,29,opt,name=imagePolicy"`
+	// UpdateWindow overrides the UpdateWindow of the HelixSaga for the app
+	// +optional
+	UpdateWindow *UpdateWindow `json:"updateWindow,omitempty" protobuf:"bytes,30,opt,name=updateWindow"`
+	// UpdateHooks are the Jobs which were run around the rollout of a new image
+	// +optional
+	UpdateHooks *UpdateHooks `json:"updateHooks,omitempty" protobuf:"bytes,31,opt,name=updateHooks"`
+	// Schedules scale the app to their Replicas at every start of their Schedule.
+	// The replicas which have been edited after a start would be kept until the next start of any Schedule.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=name
+	Schedules []ReplicaSchedule `json:"schedules,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,33,rep,name=schedules"`
+	// Paused stops correcting the drift of the app and applying the automatic image updates of it,
+	// e.g. the manual hotfix of the workload would be kept during an incident.
+	// The Paused of the Defaults couldn't be overridden by the app.
+	// +optional
+	Paused bool `json:"paused,omitempty" protobuf:"varint,34,opt,name=paused"`
+}
+
+// ReplicaSchedule is the recurring time at which the app would be scaled to the Replicas
+type ReplicaSchedule struct {
+	// Name of the schedule, it's the key of the Schedules
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Schedule is the cron expression of the start in the form of
+	// "minute hour day-of-month month day-of-week", or a descriptor like "@daily".
+	Schedule string `json:"schedule" protobuf:"bytes,2,opt,name=schedule"`
+	// TimeZone is the IANA name of the time zone of the Schedule like "Asia/Shanghai".
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty" protobuf:"bytes,3,opt,name=timeZone"`
+	// Replicas is the number of the desired replicas from the start.
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas" protobuf:"varint,4,opt,name=replicas"`
+}
+
+// UpdateHooks are the Jobs which were run around the rollout of a new image of the app.
+// The containers without an image would run the new image.
+type UpdateHooks struct {
+	// PreUpdate runs before the rollout, the rollout would be aborted if the Job failed
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PreUpdate *batchv1.JobSpec `json:"preUpdate,omitempty" protobuf:"bytes,1,opt,name=preUpdate"`
+	// PostUpdate runs after the pods of the new image have been ready
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PostUpdate *batchv1.JobSpec `json:"postUpdate,omitempty" protobuf:"bytes,2,opt,name=postUpdate"`
+	// Timeout is the duration of waiting for a hook Job, the Job would be failed after it.
+	// Defaults to 10m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,3,opt,name=timeout"`
+}
+
+// ImagePolicy is the policy of selecting the tag of an image
+type ImagePolicy struct {
+	// One of semver, regex, newest.
+	Type ImagePolicyType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=ImagePolicyType"`
+	// Range is the semver range like ">=1.4.0 <2", it's required by the semver.
+	// +optional
+	Range string `json:"range,omitempty" protobuf:"bytes,2,opt,name=range"`
+	// Pattern is the regular expression of the tags like "^release-\d+$".
+	// It's required by the regex, and the others would use it as a filter of the tags.
+	// +optional
+	Pattern string `json:"pattern,omitempty" protobuf:"bytes,3,opt,name=pattern"`
+}
+
+// PodServiceSpec is the spec of the Services which were created for every ordinal of a StatefulSet
+type PodServiceSpec struct {
+	// The type of the pod services.
+	// Defaults to ClusterIP.
+	// +optional
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty" protobuf:"bytes,1,opt,name=serviceType,casttype=k8s.io/api/core/v1.ServiceType"`
+	// The list of ports that are exposed by every pod service.
+	// +patchMergeKey=port
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=port
+	// +listMapKey=protocol
+	ServicePorts []corev1.ServicePort `json:"servicePorts,omitempty" patchStrategy:"merge" patchMergeKey:"port" protobuf:"bytes,2,rep,name=servicePorts"`
+}
+
+// HelixSagaAppStatus is the status of an application of a HelixSaga
+type HelixSagaAppStatus struct {
+	// Name of the app
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// +optional
+	Deployment DeploymentStatus `json:"deployment,omitempty" protobuf:"bytes,2,opt,name=deployment"`
+	// +optional
+	StatefulSet StatefulSetStatus `json:"statefulSet,omitempty" protobuf:"bytes,3,opt,name=statefulSet"`
+	// PodEndpoints are the addresses of the pods which were exposed by the pod Services, ordered by the ordinal
+	// +optional
+	PodEndpoints []PodEndpoint `json:"podEndpoints,omitempty" protobuf:"bytes,4,rep,name=podEndpoints"`
+	// ImageWatch is the status of the registry watch of the app image
+	// +optional
+	ImageWatch ImageWatchStatus `json:"imageWatch,omitempty" protobuf:"bytes,5,opt,name=imageWatch"`
+	// CurrentImage is the digest which the app was pinned to
+	// +optional
+	CurrentImage ImageRecord `json:"currentImage,omitempty" protobuf:"bytes,6,opt,name=currentImage"`
+	// ImageHistory are the previous digests of the app, the latest first
+	// +optional
+	ImageHistory []ImageRecord `json:"imageHistory,omitempty" protobuf:"bytes,7,rep,name=imageHistory"`
+	// ImagePolicy is the tag which was selected by the spec.imagePolicy
+	// +optional
+	ImagePolicy ImagePolicyStatus `json:"imagePolicy,omitempty" protobuf:"bytes,8,opt,name=imagePolicy"`
+	// PendingUpdate is the image update which was waiting for the UpdateWindow
+	// +optional
+	PendingUpdate *PendingUpdate `json:"pendingUpdate,omitempty" protobuf:"bytes,9,opt,name=pendingUpdate"`
+	// Hook is the last hook Job of the UpdateHooks
+	// +optional
+	Hook *HookStatus `json:"hook,omitempty" protobuf:"bytes,10,opt,name=hook"`
+	// ImageUpdate is the in-flight image update of the app with the UpdateTrigger scaleToZero.
+	// It would be resumed or rolled back after the operator has been restarted.
+	// +optional
+	ImageUpdate *ImageUpdateStatus `json:"imageUpdate,omitempty" protobuf:"bytes,11,opt,name=imageUpdate"`
+	// Schedule is the schedule of the Schedules which has scaled the app most recently
+	// +optional
+	Schedule *ScheduleStatus `json:"schedule,omitempty" protobuf:"bytes,12,opt,name=schedule"`
+	// Maintenance records the replicas of the app which has been stopped by the Maintenance
+	// +optional
+	Maintenance *MaintenanceStatus `json:"maintenance,omitempty" protobuf:"bytes,13,opt,name=maintenance"`
+	// TemplateMigration is the in-flight switch of the Template of the app.
+	// The old workload would be kept serving until the new one has been ready.
+	// +optional
+	TemplateMigration *TemplateMigrationStatus `json:"templateMigration,omitempty" protobuf:"bytes,14,opt,name=templateMigration"`
+}
+
+// TemplateMigrationStatus records the progress of the switch of the Template of an app
+type TemplateMigrationStatus struct {
+	// The Template of the old workload
+	From TemplateType `json:"from" protobuf:"bytes,1,opt,name=from,casttype=TemplateType"`
+	// The Template of the new workload
+	To TemplateType `json:"to" protobuf:"bytes,2,opt,name=to,casttype=TemplateType"`
+	// The step of the switch
+	Phase TemplateMigrationPhase `json:"phase" protobuf:"bytes,3,opt,name=phase,casttype=TemplateMigrationPhase"`
+	// The time when the switch was started
+	// +optional
+	StartedAt metav1.Time `json:"startedAt,omitempty" protobuf:"bytes,4,opt,name=startedAt"`
+}
+
+// MaintenanceStatus records the replicas of an app which has been stopped by the Maintenance
+type MaintenanceStatus struct {
+	// The replicas which the app would be restored to.
+	// The replicas of the spec edited during the Maintenance would be moved here, and the app would be kept stopped.
+	Replicas int32 `json:"replicas" protobuf:"varint,1,opt,name=replicas"`
+	// The time when the app was stopped
+	// +optional
+	StartedAt metav1.Time `json:"startedAt,omitempty" protobuf:"bytes,2,opt,name=startedAt"`
+}
+
+// ScheduleStatus records the start of a ReplicaSchedule which has been applied
+type ScheduleStatus struct {
+	// The name of the schedule
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// The replicas which the app was scaled to
+	Replicas int32 `json:"replicas" protobuf:"varint,2,opt,name=replicas"`
+	// The start of the schedule which has been applied
+	LastScheduleTime metav1.Time `json:"lastScheduleTime" protobuf:"bytes,3,opt,name=lastScheduleTime"`
+}
+
+// ImageUpdateStatus is the in-flight scale-to-zero update of an app
+type ImageUpdateStatus struct {
+	// The image which was being updated
+	Image string `json:"image" protobuf:"bytes,1,opt,name=image"`
+	// The new digest of the image
+	Digest string `json:"digest" protobuf:"bytes,2,opt,name=digest"`
+	// The replicas of the app before it was scaled down
+	Replicas int32 `json:"replicas" protobuf:"varint,3,opt,name=replicas"`
+	// One of ScalingDown, ScaledDown.
+	Phase ImageUpdatePhase `json:"phase" protobuf:"bytes,4,opt,name=phase,casttype=ImageUpdatePhase"`
+	// The time when the update was started
+	// +optional
+	StartedAt metav1.Time `json:"startedAt,omitempty" protobuf:"bytes,5,opt,name=startedAt"`
+}
+
+// HookStatus is the most recently observed status of a hook Job
+type HookStatus struct {
+	// One of PreUpdate, PostUpdate.
+	Type HookType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=HookType"`
+	// The name of the Job
+	JobName string `json:"jobName" protobuf:"bytes,2,opt,name=jobName"`
+	// The image which was being rolled out
+	Image string `json:"image" protobuf:"bytes,3,opt,name=image"`
+	// One of Running, Succeeded, Failed.
+	Phase HookPhase `json:"phase" protobuf:"bytes,4,opt,name=phase,casttype=HookPhase"`
+	// The reason why the Job failed
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,5,opt,name=message"`
+	// The last time the phase transitioned
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" protobuf:"bytes,6,opt,name=lastTransitionTime"`
+}
+
+// PendingUpdate is a detected image update which has not been applied
+type PendingUpdate struct {
+	// The image which has been pushed
+	Image string `json:"image,omitempty" protobuf:"bytes,1,opt,name=image"`
+	// The new digest of the image
+	Digest string `json:"digest,omitempty" protobuf:"bytes,2,opt,name=digest"`
+	// The time when the update was detected
+	DetectedAt metav1.Time `json:"detectedAt,omitempty" protobuf:"bytes,3,opt,name=detectedAt"`
+	// The time when the next UpdateWindow opens
+	// +optional
+	NextWindow metav1.Time `json:"nextWindow,omitempty" protobuf:"bytes,4,opt,name=nextWindow"`
+	// The reason why the update was pending
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,5,opt,name=reason"`
+}
+
+// ImagePolicyStatus records the tag selected by the ImagePolicy and why
+type ImagePolicyStatus struct {
+	// The selected tag
+	Tag string `json:"tag,omitempty" protobuf:"bytes,1,opt,name=tag"`
+	// The reason why the tag was selected, or why no tag was selected
+	Reason string `json:"reason,omitempty" protobuf:"bytes,2,opt,name=reason"`
+	// The time when the selection has been changed
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" protobuf:"bytes,3,opt,name=lastTransitionTime"`
+}
+
+// ImageRecord is a digest which has been deployed
+type ImageRecord struct {
+	// The image with the tag which the digest was resolved from
+	Image string `json:"image,omitempty" protobuf:"bytes,1,opt,name=image"`
+	// The digest like sha256:...
+	Digest string `json:"digest,omitempty" protobuf:"bytes,2,opt,name=digest"`
+	// The time when the digest was deployed
+	// +optional
+	DeployedAt metav1.Time `json:"deployedAt,omitempty" protobuf:"bytes,3,opt,name=deployedAt"`
+}
+
+// +kubebuilder:validation:Enum=Connecting;Watching;Backoff;Failed
+type ImageWatchState string
+
+const (
+	ImageWatchStateConnecting ImageWatchState = "Connecting"
+	ImageWatchStateWatching   ImageWatchState = "Watching"
+	ImageWatchStateBackoff    ImageWatchState = "Backoff"
+	ImageWatchStateFailed     ImageWatchState = "Failed"
+)
+
+// ImageWatchStatus is the most recently observed status of the registry watch
+type ImageWatchStatus struct {
+	// One of Connecting, Watching, Backoff, Failed.
+	// +optional
+	State ImageWatchState `json:"state,omitempty" protobuf:"bytes,1,opt,name=state,casttype=ImageWatchState"`
+	// The number of the retries which have been used since the last stable watch
+	// +optional
+	Retries int32 `json:"retries,omitempty" protobuf:"varint,2,opt,name=retries"`
+	// The number of the retries which were allowed before the watch failed
+	// +optional
+	RetryBudget int32 `json:"retryBudget,omitempty" protobuf:"varint,3,opt,name=retryBudget"`
+	// The message of the last error
+	// +optional
+	LastError string `json:"lastError,omitempty" protobuf:"bytes,4,opt,name=lastError"`
+	// The last time the state transitioned
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" protobuf:"bytes,5,opt,name=lastTransitionTime"`
+}
+
+// PodEndpoint is the address which a client could connect to a specific pod directly
+type PodEndpoint struct {
+	// The name of the pod, which was the same as the name of its pod Service
+	PodName string `json:"podName" protobuf:"bytes,1,opt,name=podName"`
+	// The ordinal of the pod in the StatefulSet
+	Ordinal int32 `json:"ordinal" protobuf:"varint,2,opt,name=ordinal"`
+	// The IP or the hostname of the endpoint.
+	// It was the ingress of the LoadBalancer, the node address of the NodePort or the cluster IP of the ClusterIP.
+	// It would be empty until the address has been allocated.
+	// +optional
+	Address string `json:"address,omitempty" protobuf:"bytes,3,opt,name=address"`
+	// The ports of the endpoint
+	// +optional
+	Ports []PodEndpointPort `json:"ports,omitempty" protobuf:"bytes,4,rep,name=ports"`
+	// Ready was true if the pod was ready to serve
+	Ready bool `json:"ready" protobuf:"varint,5,opt,name=ready"`
+}
+
+// PodEndpointPort is an external port of a PodEndpoint
+type PodEndpointPort struct {
+	// The name of the ServicePort
+	// +optional
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// The port which was exposed to the clients
+	Port int32 `json:"port" protobuf:"varint,2,opt,name=port"`
+	// The protocol of the port
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty" protobuf:"bytes,3,opt,name=protocol"`
+}
+
+// DeploymentStatus is the most recently observed status of the Deployment.
+type DeploymentStatus struct {
+	// The generation observed by the deployment controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,1,opt,name=observedGeneration"`
+
+	// Total number of non-terminated pods targeted by this deployment (their labels match the selector).
+	// +optional
+	Replicas int32 `json:"replicas,omitempty" protobuf:"varint,2,opt,name=replicas"`
+
+	// Total number of non-terminated pods targeted by this deployment that have the desired template spec.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty" protobuf:"varint,3,opt,name=updatedReplicas"`
+
+	// Total number of ready pods targeted by this deployment.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty" protobuf:"varint,7,opt,name=readyReplicas"`
+
+	// Total number of available pods (ready for at least minReadySeconds) targeted by this deployment.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty" protobuf:"varint,4,opt,name=availableReplicas"`
+
+	// Total number of unavailable pods targeted by this deployment. This is the total number of
+	// pods that are still required for the deployment to have 100% available capacity. They may
+	// either be pods that are running but not yet available or pods that still have not been created.
+	// +optional
+	UnavailableReplicas int32 `json:"unavailableReplicas,omitempty" protobuf:"varint,5,opt,name=unavailableReplicas"`
+
+	// Count of hash collisions for the Deployment. The Deployment controller uses this
+	// field as a collision avoidance mechanism when it needs to create the name for the
+	// newest ReplicaSet.
+	// +optional
+	CollisionCount *int32 `json:"collisionCount,omitempty" protobuf:"varint,8,opt,name=collisionCount"`
+}
+
+// StatefulSetStatus represents the current state of a StatefulSet.
+type StatefulSetStatus struct {
+	// observedGeneration is the most recent generation observed for this StatefulSet. It corresponds to the
+	// StatefulSet's generation, which is updated on mutation by the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,1,opt,name=observedGeneration"`
+
+	// replicas is the number of Pods created by the StatefulSet controller.
+	Replicas int32 `json:"replicas" protobuf:"varint,2,opt,name=replicas"`
+
+	// readyReplicas is the number of Pods created by the StatefulSet controller that have a Ready Condition.
+	ReadyReplicas int32 `json:"readyReplicas,omitempty" protobuf:"varint,3,opt,name=readyReplicas"`
+
+	// currentReplicas is the number of Pods created by the StatefulSet controller from the StatefulSet version
+	// indicated by currentRevision.
+	CurrentReplicas int32 `json:"currentReplicas,omitempty" protobuf:"varint,4,opt,name=currentReplicas"`
+
+	// updatedReplicas is the number of Pods created by the StatefulSet controller from the StatefulSet version
+	// indicated by updateRevision.
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty" protobuf:"varint,5,opt,name=updatedReplicas"`
+
+	// currentRevision, if not empty, indicates the version of the StatefulSet used to generate Pods in the
+	// sequence [0,currentReplicas).
+	CurrentRevision string `json:"currentRevision,omitempty" protobuf:"bytes,6,opt,name=currentRevision"`
+
+	// updateRevision, if not empty, indicates the version of the StatefulSet used to generate Pods in the sequence
+	// [replicas-updatedReplicas,replicas)
+	UpdateRevision string `json:"updateRevision,omitempty" protobuf:"bytes,7,opt,name=updateRevision"`
+
+	// collisionCount is the count of hash collisions for the StatefulSet. The StatefulSet controller
+	// uses this field as a collision avoidance mechanism when it needs to create the name for the
+	// newest ControllerRevision.
+	// +optional
+	CollisionCount *int32 `json:"collisionCount,omitempty" protobuf:"varint,9,opt,name=collisionCount"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// HelixSagaList is a list of HelixSaga resources
+type HelixSagaList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata" protobuf:"bytes,1,opt,name=metadata"`
+	Items           []HelixSaga `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
@@ -0,0 +1,1168 @@
+// +build !ignore_autogenerated
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	unsafe "unsafe"
+
+	v1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*DeploymentStatus)(nil), (*v1.DeploymentStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_DeploymentStatus_To_v1_DeploymentStatus(a.(*DeploymentStatus), b.(*v1.DeploymentStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.DeploymentStatus)(nil), (*DeploymentStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeploymentStatus_To_v2_DeploymentStatus(a.(*v1.DeploymentStatus), b.(*DeploymentStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HelixSagaAppDefaults)(nil), (*v1.HelixSagaAppDefaults)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_HelixSagaAppDefaults_To_v1_HelixSagaAppDefaults(a.(*HelixSagaAppDefaults), b.(*v1.HelixSagaAppDefaults), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.HelixSagaAppDefaults)(nil), (*HelixSagaAppDefaults)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_HelixSagaAppDefaults_To_v2_HelixSagaAppDefaults(a.(*v1.HelixSagaAppDefaults), b.(*HelixSagaAppDefaults), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HelixSagaAppSpec)(nil), (*v1.HelixSagaAppSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_HelixSagaAppSpec_To_v1_HelixSagaAppSpec(a.(*HelixSagaAppSpec), b.(*v1.HelixSagaAppSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.HelixSagaAppSpec)(nil), (*HelixSagaAppSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_HelixSagaAppSpec_To_v2_HelixSagaAppSpec(a.(*v1.HelixSagaAppSpec), b.(*HelixSagaAppSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.HelixSagaAppStatus)(nil), (*HelixSagaAppStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_HelixSagaAppStatus_To_v2_HelixSagaAppStatus(a.(*v1.HelixSagaAppStatus), b.(*HelixSagaAppStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HelixSagaConfigMap)(nil), (*v1.HelixSagaConfigMap)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_HelixSagaConfigMap_To_v1_HelixSagaConfigMap(a.(*HelixSagaConfigMap), b.(*v1.HelixSagaConfigMap), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.HelixSagaConfigMap)(nil), (*HelixSagaConfigMap)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_HelixSagaConfigMap_To_v2_HelixSagaConfigMap(a.(*v1.HelixSagaConfigMap), b.(*HelixSagaConfigMap), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HelixSagaList)(nil), (*v1.HelixSagaList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_HelixSagaList_To_v1_HelixSagaList(a.(*HelixSagaList), b.(*v1.HelixSagaList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.HelixSagaList)(nil), (*HelixSagaList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_HelixSagaList_To_v2_HelixSagaList(a.(*v1.HelixSagaList), b.(*HelixSagaList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.HelixSagaStatus)(nil), (*HelixSagaStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_HelixSagaStatus_To_v2_HelixSagaStatus(a.(*v1.HelixSagaStatus), b.(*HelixSagaStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HookStatus)(nil), (*v1.HookStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_HookStatus_To_v1_HookStatus(a.(*HookStatus), b.(*v1.HookStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.HookStatus)(nil), (*HookStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_HookStatus_To_v2_HookStatus(a.(*v1.HookStatus), b.(*HookStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImagePolicy)(nil), (*v1.ImagePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ImagePolicy_To_v1_ImagePolicy(a.(*ImagePolicy), b.(*v1.ImagePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ImagePolicy)(nil), (*ImagePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ImagePolicy_To_v2_ImagePolicy(a.(*v1.ImagePolicy), b.(*ImagePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImagePolicyStatus)(nil), (*v1.ImagePolicyStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ImagePolicyStatus_To_v1_ImagePolicyStatus(a.(*ImagePolicyStatus), b.(*v1.ImagePolicyStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ImagePolicyStatus)(nil), (*ImagePolicyStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ImagePolicyStatus_To_v2_ImagePolicyStatus(a.(*v1.ImagePolicyStatus), b.(*ImagePolicyStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImageRecord)(nil), (*v1.ImageRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ImageRecord_To_v1_ImageRecord(a.(*ImageRecord), b.(*v1.ImageRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ImageRecord)(nil), (*ImageRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ImageRecord_To_v2_ImageRecord(a.(*v1.ImageRecord), b.(*ImageRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImageUpdateStatus)(nil), (*v1.ImageUpdateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ImageUpdateStatus_To_v1_ImageUpdateStatus(a.(*ImageUpdateStatus), b.(*v1.ImageUpdateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ImageUpdateStatus)(nil), (*ImageUpdateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ImageUpdateStatus_To_v2_ImageUpdateStatus(a.(*v1.ImageUpdateStatus), b.(*ImageUpdateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImageWatchStatus)(nil), (*v1.ImageWatchStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ImageWatchStatus_To_v1_ImageWatchStatus(a.(*ImageWatchStatus), b.(*v1.ImageWatchStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ImageWatchStatus)(nil), (*ImageWatchStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ImageWatchStatus_To_v2_ImageWatchStatus(a.(*v1.ImageWatchStatus), b.(*ImageWatchStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Maintenance)(nil), (*v1.Maintenance)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_Maintenance_To_v1_Maintenance(a.(*Maintenance), b.(*v1.Maintenance), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.Maintenance)(nil), (*Maintenance)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_Maintenance_To_v2_Maintenance(a.(*v1.Maintenance), b.(*Maintenance), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MaintenanceStatus)(nil), (*v1.MaintenanceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_MaintenanceStatus_To_v1_MaintenanceStatus(a.(*MaintenanceStatus), b.(*v1.MaintenanceStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.MaintenanceStatus)(nil), (*MaintenanceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_MaintenanceStatus_To_v2_MaintenanceStatus(a.(*v1.MaintenanceStatus), b.(*MaintenanceStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PendingUpdate)(nil), (*v1.PendingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_PendingUpdate_To_v1_PendingUpdate(a.(*PendingUpdate), b.(*v1.PendingUpdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.PendingUpdate)(nil), (*PendingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_PendingUpdate_To_v2_PendingUpdate(a.(*v1.PendingUpdate), b.(*PendingUpdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PodEndpoint)(nil), (*v1.PodEndpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_PodEndpoint_To_v1_PodEndpoint(a.(*PodEndpoint), b.(*v1.PodEndpoint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.PodEndpoint)(nil), (*PodEndpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_PodEndpoint_To_v2_PodEndpoint(a.(*v1.PodEndpoint), b.(*PodEndpoint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PodEndpointPort)(nil), (*v1.PodEndpointPort)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_PodEndpointPort_To_v1_PodEndpointPort(a.(*PodEndpointPort), b.(*v1.PodEndpointPort), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.PodEndpointPort)(nil), (*PodEndpointPort)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_PodEndpointPort_To_v2_PodEndpointPort(a.(*v1.PodEndpointPort), b.(*PodEndpointPort), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PodServiceSpec)(nil), (*v1.PodServiceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_PodServiceSpec_To_v1_PodServiceSpec(a.(*PodServiceSpec), b.(*v1.PodServiceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.PodServiceSpec)(nil), (*PodServiceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_PodServiceSpec_To_v2_PodServiceSpec(a.(*v1.PodServiceSpec), b.(*PodServiceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ReplicaSchedule)(nil), (*v1.ReplicaSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ReplicaSchedule_To_v1_ReplicaSchedule(a.(*ReplicaSchedule), b.(*v1.ReplicaSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ReplicaSchedule)(nil), (*ReplicaSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ReplicaSchedule_To_v2_ReplicaSchedule(a.(*v1.ReplicaSchedule), b.(*ReplicaSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ScheduleStatus)(nil), (*v1.ScheduleStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ScheduleStatus_To_v1_ScheduleStatus(a.(*ScheduleStatus), b.(*v1.ScheduleStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ScheduleStatus)(nil), (*ScheduleStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ScheduleStatus_To_v2_ScheduleStatus(a.(*v1.ScheduleStatus), b.(*ScheduleStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StatefulSetStatus)(nil), (*v1.StatefulSetStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_StatefulSetStatus_To_v1_StatefulSetStatus(a.(*StatefulSetStatus), b.(*v1.StatefulSetStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.StatefulSetStatus)(nil), (*StatefulSetStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_StatefulSetStatus_To_v2_StatefulSetStatus(a.(*v1.StatefulSetStatus), b.(*StatefulSetStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TemplateMigrationStatus)(nil), (*v1.TemplateMigrationStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_TemplateMigrationStatus_To_v1_TemplateMigrationStatus(a.(*TemplateMigrationStatus), b.(*v1.TemplateMigrationStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.TemplateMigrationStatus)(nil), (*TemplateMigrationStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_TemplateMigrationStatus_To_v2_TemplateMigrationStatus(a.(*v1.TemplateMigrationStatus), b.(*TemplateMigrationStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*UpdateHooks)(nil), (*v1.UpdateHooks)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_UpdateHooks_To_v1_UpdateHooks(a.(*UpdateHooks), b.(*v1.UpdateHooks), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.UpdateHooks)(nil), (*UpdateHooks)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_UpdateHooks_To_v2_UpdateHooks(a.(*v1.UpdateHooks), b.(*UpdateHooks), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*UpdateWindow)(nil), (*v1.UpdateWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_UpdateWindow_To_v1_UpdateWindow(a.(*UpdateWindow), b.(*v1.UpdateWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.UpdateWindow)(nil), (*UpdateWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_UpdateWindow_To_v2_UpdateWindow(a.(*v1.UpdateWindow), b.(*UpdateWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1.HelixSagaSpec)(nil), (*HelixSagaSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_HelixSagaSpec_To_v2_HelixSagaSpec(a.(*v1.HelixSagaSpec), b.(*HelixSagaSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1.HelixSaga)(nil), (*HelixSaga)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_HelixSaga_To_v2_HelixSaga(a.(*v1.HelixSaga), b.(*HelixSaga), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*HelixSagaAppStatus)(nil), (*v1.HelixSagaAppStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_HelixSagaAppStatus_To_v1_HelixSagaAppStatus(a.(*HelixSagaAppStatus), b.(*v1.HelixSagaAppStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*HelixSagaSpec)(nil), (*v1.HelixSagaSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_HelixSagaSpec_To_v1_HelixSagaSpec(a.(*HelixSagaSpec), b.(*v1.HelixSagaSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*HelixSagaStatus)(nil), (*v1.HelixSagaStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_HelixSagaStatus_To_v1_HelixSagaStatus(a.(*HelixSagaStatus), b.(*v1.HelixSagaStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*HelixSaga)(nil), (*v1.HelixSaga)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_HelixSaga_To_v1_HelixSaga(a.(*HelixSaga), b.(*v1.HelixSaga), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v2_DeploymentStatus_To_v1_DeploymentStatus(in *DeploymentStatus, out *v1.DeploymentStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.Replicas = in.Replicas
+	out.UpdatedReplicas = in.UpdatedReplicas
+	out.ReadyReplicas = in.ReadyReplicas
+	out.AvailableReplicas = in.AvailableReplicas
+	out.UnavailableReplicas = in.UnavailableReplicas
+	out.CollisionCount = (*int32)(unsafe.Pointer(in.CollisionCount))
+	return nil
+}
+
+// Convert_v2_DeploymentStatus_To_v1_DeploymentStatus is an autogenerated conversion function.
+func Convert_v2_DeploymentStatus_To_v1_DeploymentStatus(in *DeploymentStatus, out *v1.DeploymentStatus, s conversion.Scope) error {
+	return autoConvert_v2_DeploymentStatus_To_v1_DeploymentStatus(in, out, s)
+}
+
+func autoConvert_v1_DeploymentStatus_To_v2_DeploymentStatus(in *v1.DeploymentStatus, out *DeploymentStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.Replicas = in.Replicas
+	out.UpdatedReplicas = in.UpdatedReplicas
+	out.ReadyReplicas = in.ReadyReplicas
+	out.AvailableReplicas = in.AvailableReplicas
+	out.UnavailableReplicas = in.UnavailableReplicas
+	out.CollisionCount = (*int32)(unsafe.Pointer(in.CollisionCount))
+	return nil
+}
+
+// Convert_v1_DeploymentStatus_To_v2_DeploymentStatus is an autogenerated conversion function.
+func Convert_v1_DeploymentStatus_To_v2_DeploymentStatus(in *v1.DeploymentStatus, out *DeploymentStatus, s conversion.Scope) error {
+	return autoConvert_v1_DeploymentStatus_To_v2_DeploymentStatus(in, out, s)
+}
+
+func autoConvert_v2_HelixSaga_To_v1_HelixSaga(in *HelixSaga, out *v1.HelixSaga, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v2_HelixSagaSpec_To_v1_HelixSagaSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v2_HelixSagaStatus_To_v1_HelixSagaStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1_HelixSaga_To_v2_HelixSaga(in *v1.HelixSaga, out *HelixSaga, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1_HelixSagaSpec_To_v2_HelixSagaSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1_HelixSagaStatus_To_v2_HelixSagaStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v2_HelixSagaAppDefaults_To_v1_HelixSagaAppDefaults(in *HelixSagaAppDefaults, out *v1.HelixSagaAppDefaults, s conversion.Scope) error {
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.Image = in.Image
+	out.ImagePullSecrets = *(*[]corev1.LocalObjectReference)(unsafe.Pointer(&in.ImagePullSecrets))
+	out.Env = *(*[]corev1.EnvVar)(unsafe.Pointer(&in.Env))
+	out.Resources = in.Resources
+	out.VolumeMounts = *(*[]corev1.VolumeMount)(unsafe.Pointer(&in.VolumeMounts))
+	out.Command = *(*[]string)(unsafe.Pointer(&in.Command))
+	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
+	out.ContainerPorts = *(*[]corev1.ContainerPort)(unsafe.Pointer(&in.ContainerPorts))
+	out.ServicePorts = *(*[]corev1.ServicePort)(unsafe.Pointer(&in.ServicePorts))
+	out.ServiceType = corev1.ServiceType(in.ServiceType)
+	out.VolumePath = in.VolumePath
+	out.WatchPolicy = v1.WatchPolicy(in.WatchPolicy)
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.ServiceAccountName = in.ServiceAccountName
+	out.Affinity = (*corev1.Affinity)(unsafe.Pointer(in.Affinity))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.Template = v1.TemplateType(in.Template)
+	out.ServiceWhiteList = in.ServiceWhiteList
+	out.ServiceSourceRanges = *(*[]string)(unsafe.Pointer(&in.ServiceSourceRanges))
+	out.ServiceSourceRangeRefs = *(*[]string)(unsafe.Pointer(&in.ServiceSourceRangeRefs))
+	out.PodService = (*v1.PodServiceSpec)(unsafe.Pointer(in.PodService))
+	out.PinDigest = in.PinDigest
+	out.RollbackTo = in.RollbackTo
+	out.ImageHistoryLimit = (*int32)(unsafe.Pointer(in.ImageHistoryLimit))
+	out.ImagePullPolicy = corev1.PullPolicy(in.ImagePullPolicy)
+	out.UpdateTrigger = v1.UpdateTrigger(in.UpdateTrigger)
+	out.ImagePolicy = (*v1.ImagePolicy)(unsafe.Pointer(in.ImagePolicy))
+	out.UpdateWindow = (*v1.UpdateWindow)(unsafe.Pointer(in.UpdateWindow))
+	out.UpdateHooks = (*v1.UpdateHooks)(unsafe.Pointer(in.UpdateHooks))
+	out.Schedules = *(*[]v1.ReplicaSchedule)(unsafe.Pointer(&in.Schedules))
+	out.Paused = in.Paused
+	return nil
+}
+
+// Convert_v2_HelixSagaAppDefaults_To_v1_HelixSagaAppDefaults is an autogenerated conversion function.
+func Convert_v2_HelixSagaAppDefaults_To_v1_HelixSagaAppDefaults(in *HelixSagaAppDefaults, out *v1.HelixSagaAppDefaults, s conversion.Scope) error {
+	return autoConvert_v2_HelixSagaAppDefaults_To_v1_HelixSagaAppDefaults(in, out, s)
+}
+
+func autoConvert_v1_HelixSagaAppDefaults_To_v2_HelixSagaAppDefaults(in *v1.HelixSagaAppDefaults, out *HelixSagaAppDefaults, s conversion.Scope) error {
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.Image = in.Image
+	out.ImagePullSecrets = *(*[]corev1.LocalObjectReference)(unsafe.Pointer(&in.ImagePullSecrets))
+	out.Env = *(*[]corev1.EnvVar)(unsafe.Pointer(&in.Env))
+	out.Resources = in.Resources
+	out.VolumeMounts = *(*[]corev1.VolumeMount)(unsafe.Pointer(&in.VolumeMounts))
+	out.Command = *(*[]string)(unsafe.Pointer(&in.Command))
+	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
+	out.ContainerPorts = *(*[]corev1.ContainerPort)(unsafe.Pointer(&in.ContainerPorts))
+	out.ServicePorts = *(*[]corev1.ServicePort)(unsafe.Pointer(&in.ServicePorts))
+	out.ServiceType = corev1.ServiceType(in.ServiceType)
+	out.VolumePath = in.VolumePath
+	out.WatchPolicy = WatchPolicy(in.WatchPolicy)
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.ServiceAccountName = in.ServiceAccountName
+	out.Affinity = (*corev1.Affinity)(unsafe.Pointer(in.Affinity))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.Template = TemplateType(in.Template)
+	out.ServiceWhiteList = in.ServiceWhiteList
+	out.ServiceSourceRanges = *(*[]string)(unsafe.Pointer(&in.ServiceSourceRanges))
+	out.ServiceSourceRangeRefs = *(*[]string)(unsafe.Pointer(&in.ServiceSourceRangeRefs))
+	out.PodService = (*PodServiceSpec)(unsafe.Pointer(in.PodService))
+	out.PinDigest = in.PinDigest
+	out.RollbackTo = in.RollbackTo
+	out.ImageHistoryLimit = (*int32)(unsafe.Pointer(in.ImageHistoryLimit))
+	out.ImagePullPolicy = corev1.PullPolicy(in.ImagePullPolicy)
+	out.UpdateTrigger = UpdateTrigger(in.UpdateTrigger)
+	out.ImagePolicy = (*ImagePolicy)(unsafe.Pointer(in.ImagePolicy))
+	out.UpdateWindow = (*UpdateWindow)(unsafe.Pointer(in.UpdateWindow))
+	out.UpdateHooks = (*UpdateHooks)(unsafe.Pointer(in.UpdateHooks))
+	out.Schedules = *(*[]ReplicaSchedule)(unsafe.Pointer(&in.Schedules))
+	out.Paused = in.Paused
+	return nil
+}
+
+// Convert_v1_HelixSagaAppDefaults_To_v2_HelixSagaAppDefaults is an autogenerated conversion function.
+func Convert_v1_HelixSagaAppDefaults_To_v2_HelixSagaAppDefaults(in *v1.HelixSagaAppDefaults, out *HelixSagaAppDefaults, s conversion.Scope) error {
+	return autoConvert_v1_HelixSagaAppDefaults_To_v2_HelixSagaAppDefaults(in, out, s)
+}
+
+func autoConvert_v2_HelixSagaAppSpec_To_v1_HelixSagaAppSpec(in *HelixSagaAppSpec, out *v1.HelixSagaAppSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.Image = in.Image
+	out.ImagePullSecrets = *(*[]corev1.LocalObjectReference)(unsafe.Pointer(&in.ImagePullSecrets))
+	out.Env = *(*[]corev1.EnvVar)(unsafe.Pointer(&in.Env))
+	out.Resources = in.Resources
+	out.VolumeMounts = *(*[]corev1.VolumeMount)(unsafe.Pointer(&in.VolumeMounts))
+	out.Command = *(*[]string)(unsafe.Pointer(&in.Command))
+	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
+	out.ContainerPorts = *(*[]corev1.ContainerPort)(unsafe.Pointer(&in.ContainerPorts))
+	out.ServicePorts = *(*[]corev1.ServicePort)(unsafe.Pointer(&in.ServicePorts))
+	out.ServiceType = corev1.ServiceType(in.ServiceType)
+	out.VolumePath = in.VolumePath
+	out.WatchPolicy = v1.WatchPolicy(in.WatchPolicy)
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.ServiceAccountName = in.ServiceAccountName
+	out.Affinity = (*corev1.Affinity)(unsafe.Pointer(in.Affinity))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.Template = v1.TemplateType(in.Template)
+	out.ServiceWhiteList = in.ServiceWhiteList
+	out.ServiceSourceRanges = *(*[]string)(unsafe.Pointer(&in.ServiceSourceRanges))
+	out.ServiceSourceRangeRefs = *(*[]string)(unsafe.Pointer(&in.ServiceSourceRangeRefs))
+	out.PodService = (*v1.PodServiceSpec)(unsafe.Pointer(in.PodService))
+	out.PinDigest = in.PinDigest
+	out.RollbackTo = in.RollbackTo
+	out.ImageHistoryLimit = (*int32)(unsafe.Pointer(in.ImageHistoryLimit))
+	out.ImagePullPolicy = corev1.PullPolicy(in.ImagePullPolicy)
+	out.UpdateTrigger = v1.UpdateTrigger(in.UpdateTrigger)
+	out.ImagePolicy = (*v1.ImagePolicy)(unsafe.Pointer(in.ImagePolicy))
+	out.UpdateWindow = (*v1.UpdateWindow)(unsafe.Pointer(in.UpdateWindow))
+	out.UpdateHooks = (*v1.UpdateHooks)(unsafe.Pointer(in.UpdateHooks))
+	out.DependsOn = *(*[]string)(unsafe.Pointer(&in.DependsOn))
+	out.Schedules = *(*[]v1.ReplicaSchedule)(unsafe.Pointer(&in.Schedules))
+	out.Paused = in.Paused
+	return nil
+}
+
+// Convert_v2_HelixSagaAppSpec_To_v1_HelixSagaAppSpec is an autogenerated conversion function.
+func Convert_v2_HelixSagaAppSpec_To_v1_HelixSagaAppSpec(in *HelixSagaAppSpec, out *v1.HelixSagaAppSpec, s conversion.Scope) error {
+	return autoConvert_v2_HelixSagaAppSpec_To_v1_HelixSagaAppSpec(in, out, s)
+}
+
+func autoConvert_v1_HelixSagaAppSpec_To_v2_HelixSagaAppSpec(in *v1.HelixSagaAppSpec, out *HelixSagaAppSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.Image = in.Image
+	out.ImagePullSecrets = *(*[]corev1.LocalObjectReference)(unsafe.Pointer(&in.ImagePullSecrets))
+	out.Env = *(*[]corev1.EnvVar)(unsafe.Pointer(&in.Env))
+	out.Resources = in.Resources
+	out.VolumeMounts = *(*[]corev1.VolumeMount)(unsafe.Pointer(&in.VolumeMounts))
+	out.Command = *(*[]string)(unsafe.Pointer(&in.Command))
+	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
+	out.ContainerPorts = *(*[]corev1.ContainerPort)(unsafe.Pointer(&in.ContainerPorts))
+	out.ServicePorts = *(*[]corev1.ServicePort)(unsafe.Pointer(&in.ServicePorts))
+	out.ServiceType = corev1.ServiceType(in.ServiceType)
+	out.VolumePath = in.VolumePath
+	out.WatchPolicy = WatchPolicy(in.WatchPolicy)
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.ServiceAccountName = in.ServiceAccountName
+	out.Affinity = (*corev1.Affinity)(unsafe.Pointer(in.Affinity))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.Template = TemplateType(in.Template)
+	out.ServiceWhiteList = in.ServiceWhiteList
+	out.ServiceSourceRanges = *(*[]string)(unsafe.Pointer(&in.ServiceSourceRanges))
+	out.ServiceSourceRangeRefs = *(*[]string)(unsafe.Pointer(&in.ServiceSourceRangeRefs))
+	out.PodService = (*PodServiceSpec)(unsafe.Pointer(in.PodService))
+	out.PinDigest = in.PinDigest
+	out.RollbackTo = in.RollbackTo
+	out.ImageHistoryLimit = (*int32)(unsafe.Pointer(in.ImageHistoryLimit))
+	out.ImagePullPolicy = corev1.PullPolicy(in.ImagePullPolicy)
+	out.UpdateTrigger = UpdateTrigger(in.UpdateTrigger)
+	out.ImagePolicy = (*ImagePolicy)(unsafe.Pointer(in.ImagePolicy))
+	out.UpdateWindow = (*UpdateWindow)(unsafe.Pointer(in.UpdateWindow))
+	out.UpdateHooks = (*UpdateHooks)(unsafe.Pointer(in.UpdateHooks))
+	out.DependsOn = *(*[]string)(unsafe.Pointer(&in.DependsOn))
+	out.Schedules = *(*[]ReplicaSchedule)(unsafe.Pointer(&in.Schedules))
+	out.Paused = in.Paused
+	return nil
+}
+
+// Convert_v1_HelixSagaAppSpec_To_v2_HelixSagaAppSpec is an autogenerated conversion function.
+func Convert_v1_HelixSagaAppSpec_To_v2_HelixSagaAppSpec(in *v1.HelixSagaAppSpec, out *HelixSagaAppSpec, s conversion.Scope) error {
+	return autoConvert_v1_HelixSagaAppSpec_To_v2_HelixSagaAppSpec(in, out, s)
+}
+
+func autoConvert_v2_HelixSagaAppStatus_To_v1_HelixSagaAppStatus(in *HelixSagaAppStatus, out *v1.HelixSagaAppStatus, s conversion.Scope) error {
+	// WARNING: in.Name requires manual conversion: does not exist in peer-type
+	if err := Convert_v2_DeploymentStatus_To_v1_DeploymentStatus(&in.Deployment, &out.Deployment, s); err != nil {
+		return err
+	}
+	if err := Convert_v2_StatefulSetStatus_To_v1_StatefulSetStatus(&in.StatefulSet, &out.StatefulSet, s); err != nil {
+		return err
+	}
+	out.PodEndpoints = *(*[]v1.PodEndpoint)(unsafe.Pointer(&in.PodEndpoints))
+	if err := Convert_v2_ImageWatchStatus_To_v1_ImageWatchStatus(&in.ImageWatch, &out.ImageWatch, s); err != nil {
+		return err
+	}
+	if err := Convert_v2_ImageRecord_To_v1_ImageRecord(&in.CurrentImage, &out.CurrentImage, s); err != nil {
+		return err
+	}
+	out.ImageHistory = *(*[]v1.ImageRecord)(unsafe.Pointer(&in.ImageHistory))
+	if err := Convert_v2_ImagePolicyStatus_To_v1_ImagePolicyStatus(&in.ImagePolicy, &out.ImagePolicy, s); err != nil {
+		return err
+	}
+	out.PendingUpdate = (*v1.PendingUpdate)(unsafe.Pointer(in.PendingUpdate))
+	out.Hook = (*v1.HookStatus)(unsafe.Pointer(in.Hook))
+	out.ImageUpdate = (*v1.ImageUpdateStatus)(unsafe.Pointer(in.ImageUpdate))
+	out.Schedule = (*v1.ScheduleStatus)(unsafe.Pointer(in.Schedule))
+	out.Maintenance = (*v1.MaintenanceStatus)(unsafe.Pointer(in.Maintenance))
+	out.TemplateMigration = (*v1.TemplateMigrationStatus)(unsafe.Pointer(in.TemplateMigration))
+	return nil
+}
+
+func autoConvert_v1_HelixSagaAppStatus_To_v2_HelixSagaAppStatus(in *v1.HelixSagaAppStatus, out *HelixSagaAppStatus, s conversion.Scope) error {
+	if err := Convert_v1_DeploymentStatus_To_v2_DeploymentStatus(&in.Deployment, &out.Deployment, s); err != nil {
+		return err
+	}
+	if err := Convert_v1_StatefulSetStatus_To_v2_StatefulSetStatus(&in.StatefulSet, &out.StatefulSet, s); err != nil {
+		return err
+	}
+	out.PodEndpoints = *(*[]PodEndpoint)(unsafe.Pointer(&in.PodEndpoints))
+	if err := Convert_v1_ImageWatchStatus_To_v2_ImageWatchStatus(&in.ImageWatch, &out.ImageWatch, s); err != nil {
+		return err
+	}
+	if err := Convert_v1_ImageRecord_To_v2_ImageRecord(&in.CurrentImage, &out.CurrentImage, s); err != nil {
+		return err
+	}
+	out.ImageHistory = *(*[]ImageRecord)(unsafe.Pointer(&in.ImageHistory))
+	if err := Convert_v1_ImagePolicyStatus_To_v2_ImagePolicyStatus(&in.ImagePolicy, &out.ImagePolicy, s); err != nil {
+		return err
+	}
+	out.PendingUpdate = (*PendingUpdate)(unsafe.Pointer(in.PendingUpdate))
+	out.Hook = (*HookStatus)(unsafe.Pointer(in.Hook))
+	out.ImageUpdate = (*ImageUpdateStatus)(unsafe.Pointer(in.ImageUpdate))
+	out.Schedule = (*ScheduleStatus)(unsafe.Pointer(in.Schedule))
+	out.Maintenance = (*MaintenanceStatus)(unsafe.Pointer(in.Maintenance))
+	out.TemplateMigration = (*TemplateMigrationStatus)(unsafe.Pointer(in.TemplateMigration))
+	return nil
+}
+
+// Convert_v1_HelixSagaAppStatus_To_v2_HelixSagaAppStatus is an autogenerated conversion function.
+func Convert_v1_HelixSagaAppStatus_To_v2_HelixSagaAppStatus(in *v1.HelixSagaAppStatus, out *HelixSagaAppStatus, s conversion.Scope) error {
+	return autoConvert_v1_HelixSagaAppStatus_To_v2_HelixSagaAppStatus(in, out, s)
+}
+
+func autoConvert_v2_HelixSagaConfigMap_To_v1_HelixSagaConfigMap(in *HelixSagaConfigMap, out *v1.HelixSagaConfigMap, s conversion.Scope) error {
+	out.Volume = in.Volume
+	out.VolumeMount = in.VolumeMount
+	return nil
+}
+
+// Convert_v2_HelixSagaConfigMap_To_v1_HelixSagaConfigMap is an autogenerated conversion function.
+func Convert_v2_HelixSagaConfigMap_To_v1_HelixSagaConfigMap(in *HelixSagaConfigMap, out *v1.HelixSagaConfigMap, s conversion.Scope) error {
+	return autoConvert_v2_HelixSagaConfigMap_To_v1_HelixSagaConfigMap(in, out, s)
+}
+
+func autoConvert_v1_HelixSagaConfigMap_To_v2_HelixSagaConfigMap(in *v1.HelixSagaConfigMap, out *HelixSagaConfigMap, s conversion.Scope) error {
+	out.Volume = in.Volume
+	out.VolumeMount = in.VolumeMount
+	return nil
+}
+
+// Convert_v1_HelixSagaConfigMap_To_v2_HelixSagaConfigMap is an autogenerated conversion function.
+func Convert_v1_HelixSagaConfigMap_To_v2_HelixSagaConfigMap(in *v1.HelixSagaConfigMap, out *HelixSagaConfigMap, s conversion.Scope) error {
+	return autoConvert_v1_HelixSagaConfigMap_To_v2_HelixSagaConfigMap(in, out, s)
+}
+
+func autoConvert_v2_HelixSagaList_To_v1_HelixSagaList(in *HelixSagaList, out *v1.HelixSagaList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1.HelixSaga, len(*in))
+		for i := range *in {
+			if err := Convert_v2_HelixSaga_To_v1_HelixSaga(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v2_HelixSagaList_To_v1_HelixSagaList is an autogenerated conversion function.
+func Convert_v2_HelixSagaList_To_v1_HelixSagaList(in *HelixSagaList, out *v1.HelixSagaList, s conversion.Scope) error {
+	return autoConvert_v2_HelixSagaList_To_v1_HelixSagaList(in, out, s)
+}
+
+func autoConvert_v1_HelixSagaList_To_v2_HelixSagaList(in *v1.HelixSagaList, out *HelixSagaList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HelixSaga, len(*in))
+		for i := range *in {
+			if err := Convert_v1_HelixSaga_To_v2_HelixSaga(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1_HelixSagaList_To_v2_HelixSagaList is an autogenerated conversion function.
+func Convert_v1_HelixSagaList_To_v2_HelixSagaList(in *v1.HelixSagaList, out *HelixSagaList, s conversion.Scope) error {
+	return autoConvert_v1_HelixSagaList_To_v2_HelixSagaList(in, out, s)
+}
+
+func autoConvert_v2_HelixSagaSpec_To_v1_HelixSagaSpec(in *HelixSagaSpec, out *v1.HelixSagaSpec, s conversion.Scope) error {
+	if err := Convert_v2_HelixSagaConfigMap_To_v1_HelixSagaConfigMap(&in.ConfigMap, &out.ConfigMap, s); err != nil {
+		return err
+	}
+	out.UpdateWindow = (*v1.UpdateWindow)(unsafe.Pointer(in.UpdateWindow))
+	out.Defaults = (*v1.HelixSagaAppDefaults)(unsafe.Pointer(in.Defaults))
+	// WARNING: in.Apps requires manual conversion: does not exist in peer-type
+	out.Maintenance = (*v1.Maintenance)(unsafe.Pointer(in.Maintenance))
+	out.Paused = in.Paused
+	return nil
+}
+
+func autoConvert_v1_HelixSagaSpec_To_v2_HelixSagaSpec(in *v1.HelixSagaSpec, out *HelixSagaSpec, s conversion.Scope) error {
+	if err := Convert_v1_HelixSagaConfigMap_To_v2_HelixSagaConfigMap(&in.ConfigMap, &out.ConfigMap, s); err != nil {
+		return err
+	}
+	// WARNING: in.Applications requires manual conversion: does not exist in peer-type
+	out.UpdateWindow = (*UpdateWindow)(unsafe.Pointer(in.UpdateWindow))
+	out.Defaults = (*HelixSagaAppDefaults)(unsafe.Pointer(in.Defaults))
+	out.Maintenance = (*Maintenance)(unsafe.Pointer(in.Maintenance))
+	out.Paused = in.Paused
+	return nil
+}
+
+func autoConvert_v2_HelixSagaStatus_To_v1_HelixSagaStatus(in *HelixSagaStatus, out *v1.HelixSagaStatus, s conversion.Scope) error {
+	// WARNING: in.Apps requires manual conversion: does not exist in peer-type
+	out.Ready = in.Ready
+	out.Image = in.Image
+	out.Maintenance = in.Maintenance
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+func autoConvert_v1_HelixSagaStatus_To_v2_HelixSagaStatus(in *v1.HelixSagaStatus, out *HelixSagaStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.Image = in.Image
+	out.Maintenance = in.Maintenance
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+// Convert_v1_HelixSagaStatus_To_v2_HelixSagaStatus is an autogenerated conversion function.
+func Convert_v1_HelixSagaStatus_To_v2_HelixSagaStatus(in *v1.HelixSagaStatus, out *HelixSagaStatus, s conversion.Scope) error {
+	return autoConvert_v1_HelixSagaStatus_To_v2_HelixSagaStatus(in, out, s)
+}
+
+func autoConvert_v2_HookStatus_To_v1_HookStatus(in *HookStatus, out *v1.HookStatus, s conversion.Scope) error {
+	out.Type = v1.HookType(in.Type)
+	out.JobName = in.JobName
+	out.Image = in.Image
+	out.Phase = v1.HookPhase(in.Phase)
+	out.Message = in.Message
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_v2_HookStatus_To_v1_HookStatus is an autogenerated conversion function.
+func Convert_v2_HookStatus_To_v1_HookStatus(in *HookStatus, out *v1.HookStatus, s conversion.Scope) error {
+	return autoConvert_v2_HookStatus_To_v1_HookStatus(in, out, s)
+}
+
+func autoConvert_v1_HookStatus_To_v2_HookStatus(in *v1.HookStatus, out *HookStatus, s conversion.Scope) error {
+	out.Type = HookType(in.Type)
+	out.JobName = in.JobName
+	out.Image = in.Image
+	out.Phase = HookPhase(in.Phase)
+	out.Message = in.Message
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_v1_HookStatus_To_v2_HookStatus is an autogenerated conversion function.
+func Convert_v1_HookStatus_To_v2_HookStatus(in *v1.HookStatus, out *HookStatus, s conversion.Scope) error {
+	return autoConvert_v1_HookStatus_To_v2_HookStatus(in, out, s)
+}
+
+func autoConvert_v2_ImagePolicy_To_v1_ImagePolicy(in *ImagePolicy, out *v1.ImagePolicy, s conversion.Scope) error {
+	out.Type = v1.ImagePolicyType(in.Type)
+	out.Range = in.Range
+	out.Pattern = in.Pattern
+	return nil
+}
+
+// Convert_v2_ImagePolicy_To_v1_ImagePolicy is an autogenerated conversion function.
+func Convert_v2_ImagePolicy_To_v1_ImagePolicy(in *ImagePolicy, out *v1.ImagePolicy, s conversion.Scope) error {
+	return autoConvert_v2_ImagePolicy_To_v1_ImagePolicy(in, out, s)
+}
+
+func autoConvert_v1_ImagePolicy_To_v2_ImagePolicy(in *v1.ImagePolicy, out *ImagePolicy, s conversion.Scope) error {
+	out.Type = ImagePolicyType(in.Type)
+	out.Range = in.Range
+	out.Pattern = in.Pattern
+	return nil
+}
+
+// Convert_v1_ImagePolicy_To_v2_ImagePolicy is an autogenerated conversion function.
+func Convert_v1_ImagePolicy_To_v2_ImagePolicy(in *v1.ImagePolicy, out *ImagePolicy, s conversion.Scope) error {
+	return autoConvert_v1_ImagePolicy_To_v2_ImagePolicy(in, out, s)
+}
+
+func autoConvert_v2_ImagePolicyStatus_To_v1_ImagePolicyStatus(in *ImagePolicyStatus, out *v1.ImagePolicyStatus, s conversion.Scope) error {
+	out.Tag = in.Tag
+	out.Reason = in.Reason
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_v2_ImagePolicyStatus_To_v1_ImagePolicyStatus is an autogenerated conversion function.
+func Convert_v2_ImagePolicyStatus_To_v1_ImagePolicyStatus(in *ImagePolicyStatus, out *v1.ImagePolicyStatus, s conversion.Scope) error {
+	return autoConvert_v2_ImagePolicyStatus_To_v1_ImagePolicyStatus(in, out, s)
+}
+
+func autoConvert_v1_ImagePolicyStatus_To_v2_ImagePolicyStatus(in *v1.ImagePolicyStatus, out *ImagePolicyStatus, s conversion.Scope) error {
+	out.Tag = in.Tag
+	out.Reason = in.Reason
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_v1_ImagePolicyStatus_To_v2_ImagePolicyStatus is an autogenerated conversion function.
+func Convert_v1_ImagePolicyStatus_To_v2_ImagePolicyStatus(in *v1.ImagePolicyStatus, out *ImagePolicyStatus, s conversion.Scope) error {
+	return autoConvert_v1_ImagePolicyStatus_To_v2_ImagePolicyStatus(in, out, s)
+}
+
+func autoConvert_v2_ImageRecord_To_v1_ImageRecord(in *ImageRecord, out *v1.ImageRecord, s conversion.Scope) error {
+	out.Image = in.Image
+	out.Digest = in.Digest
+	out.DeployedAt = in.DeployedAt
+	return nil
+}
+
+// Convert_v2_ImageRecord_To_v1_ImageRecord is an autogenerated conversion function.
+func Convert_v2_ImageRecord_To_v1_ImageRecord(in *ImageRecord, out *v1.ImageRecord, s conversion.Scope) error {
+	return autoConvert_v2_ImageRecord_To_v1_ImageRecord(in, out, s)
+}
+
+func autoConvert_v1_ImageRecord_To_v2_ImageRecord(in *v1.ImageRecord, out *ImageRecord, s conversion.Scope) error {
+	out.Image = in.Image
+	out.Digest = in.Digest
+	out.DeployedAt = in.DeployedAt
+	return nil
+}
+
+// Convert_v1_ImageRecord_To_v2_ImageRecord is an autogenerated conversion function.
+func Convert_v1_ImageRecord_To_v2_ImageRecord(in *v1.ImageRecord, out *ImageRecord, s conversion.Scope) error {
+	return autoConvert_v1_ImageRecord_To_v2_ImageRecord(in, out, s)
+}
+
+func autoConvert_v2_ImageUpdateStatus_To_v1_ImageUpdateStatus(in *ImageUpdateStatus, out *v1.ImageUpdateStatus, s conversion.Scope) error {
+	out.Image = in.Image
+	out.Digest = in.Digest
+	out.Replicas = in.Replicas
+	out.Phase = v1.ImageUpdatePhase(in.Phase)
+	out.StartedAt = in.StartedAt
+	return nil
+}
+
+// Convert_v2_ImageUpdateStatus_To_v1_ImageUpdateStatus is an autogenerated conversion function.
+func Convert_v2_ImageUpdateStatus_To_v1_ImageUpdateStatus(in *ImageUpdateStatus, out *v1.ImageUpdateStatus, s conversion.Scope) error {
+	return autoConvert_v2_ImageUpdateStatus_To_v1_ImageUpdateStatus(in, out, s)
+}
+
+func autoConvert_v1_ImageUpdateStatus_To_v2_ImageUpdateStatus(in *v1.ImageUpdateStatus, out *ImageUpdateStatus, s conversion.Scope) error {
+	out.Image = in.Image
+	out.Digest = in.Digest
+	out.Replicas = in.Replicas
+	out.Phase = ImageUpdatePhase(in.Phase)
+	out.StartedAt = in.StartedAt
+	return nil
+}
+
+// Convert_v1_ImageUpdateStatus_To_v2_ImageUpdateStatus is an autogenerated conversion function.
+func Convert_v1_ImageUpdateStatus_To_v2_ImageUpdateStatus(in *v1.ImageUpdateStatus, out *ImageUpdateStatus, s conversion.Scope) error {
+	return autoConvert_v1_ImageUpdateStatus_To_v2_ImageUpdateStatus(in, out, s)
+}
+
+func autoConvert_v2_ImageWatchStatus_To_v1_ImageWatchStatus(in *ImageWatchStatus, out *v1.ImageWatchStatus, s conversion.Scope) error {
+	out.State = v1.ImageWatchState(in.State)
+	out.Retries = in.Retries
+	out.RetryBudget = in.RetryBudget
+	out.LastError = in.LastError
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_v2_ImageWatchStatus_To_v1_ImageWatchStatus is an autogenerated conversion function.
+func Convert_v2_ImageWatchStatus_To_v1_ImageWatchStatus(in *ImageWatchStatus, out *v1.ImageWatchStatus, s conversion.Scope) error {
+	return autoConvert_v2_ImageWatchStatus_To_v1_ImageWatchStatus(in, out, s)
+}
+
+func autoConvert_v1_ImageWatchStatus_To_v2_ImageWatchStatus(in *v1.ImageWatchStatus, out *ImageWatchStatus, s conversion.Scope) error {
+	out.State = ImageWatchState(in.State)
+	out.Retries = in.Retries
+	out.RetryBudget = in.RetryBudget
+	out.LastError = in.LastError
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_v1_ImageWatchStatus_To_v2_ImageWatchStatus is an autogenerated conversion function.
+func Convert_v1_ImageWatchStatus_To_v2_ImageWatchStatus(in *v1.ImageWatchStatus, out *ImageWatchStatus, s conversion.Scope) error {
+	return autoConvert_v1_ImageWatchStatus_To_v2_ImageWatchStatus(in, out, s)
+}
+
+func autoConvert_v2_Maintenance_To_v1_Maintenance(in *Maintenance, out *v1.Maintenance, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.KeepRunning = *(*[]string)(unsafe.Pointer(&in.KeepRunning))
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v2_Maintenance_To_v1_Maintenance is an autogenerated conversion function.
+func Convert_v2_Maintenance_To_v1_Maintenance(in *Maintenance, out *v1.Maintenance, s conversion.Scope) error {
+	return autoConvert_v2_Maintenance_To_v1_Maintenance(in, out, s)
+}
+
+func autoConvert_v1_Maintenance_To_v2_Maintenance(in *v1.Maintenance, out *Maintenance, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.KeepRunning = *(*[]string)(unsafe.Pointer(&in.KeepRunning))
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1_Maintenance_To_v2_Maintenance is an autogenerated conversion function.
+func Convert_v1_Maintenance_To_v2_Maintenance(in *v1.Maintenance, out *Maintenance, s conversion.Scope) error {
+	return autoConvert_v1_Maintenance_To_v2_Maintenance(in, out, s)
+}
+
+func autoConvert_v2_MaintenanceStatus_To_v1_MaintenanceStatus(in *MaintenanceStatus, out *v1.MaintenanceStatus, s conversion.Scope) error {
+	out.Replicas = in.Replicas
+	out.StartedAt = in.StartedAt
+	return nil
+}
+
+// Convert_v2_MaintenanceStatus_To_v1_MaintenanceStatus is an autogenerated conversion function.
+func Convert_v2_MaintenanceStatus_To_v1_MaintenanceStatus(in *MaintenanceStatus, out *v1.MaintenanceStatus, s conversion.Scope) error {
+	return autoConvert_v2_MaintenanceStatus_To_v1_MaintenanceStatus(in, out, s)
+}
+
+func autoConvert_v1_MaintenanceStatus_To_v2_MaintenanceStatus(in *v1.MaintenanceStatus, out *MaintenanceStatus, s conversion.Scope) error {
+	out.Replicas = in.Replicas
+	out.StartedAt = in.StartedAt
+	return nil
+}
+
+// Convert_v1_MaintenanceStatus_To_v2_MaintenanceStatus is an autogenerated conversion function.
+func Convert_v1_MaintenanceStatus_To_v2_MaintenanceStatus(in *v1.MaintenanceStatus, out *MaintenanceStatus, s conversion.Scope) error {
+	return autoConvert_v1_MaintenanceStatus_To_v2_MaintenanceStatus(in, out, s)
+}
+
+func autoConvert_v2_PendingUpdate_To_v1_PendingUpdate(in *PendingUpdate, out *v1.PendingUpdate, s conversion.Scope) error {
+	out.Image = in.Image
+	out.Digest = in.Digest
+	out.DetectedAt = in.DetectedAt
+	out.NextWindow = in.NextWindow
+	out.Reason = in.Reason
+	return nil
+}
+
+// Convert_v2_PendingUpdate_To_v1_PendingUpdate is an autogenerated conversion function.
+func Convert_v2_PendingUpdate_To_v1_PendingUpdate(in *PendingUpdate, out *v1.PendingUpdate, s conversion.Scope) error {
+	return autoConvert_v2_PendingUpdate_To_v1_PendingUpdate(in, out, s)
+}
+
+func autoConvert_v1_PendingUpdate_To_v2_PendingUpdate(in *v1.PendingUpdate, out *PendingUpdate, s conversion.Scope) error {
+	out.Image = in.Image
+	out.Digest = in.Digest
+	out.DetectedAt = in.DetectedAt
+	out.NextWindow = in.NextWindow
+	out.Reason = in.Reason
+	return nil
+}
+
+// Convert_v1_PendingUpdate_To_v2_PendingUpdate is an autogenerated conversion function.
+func Convert_v1_PendingUpdate_To_v2_PendingUpdate(in *v1.PendingUpdate, out *PendingUpdate, s conversion.Scope) error {
+	return autoConvert_v1_PendingUpdate_To_v2_PendingUpdate(in, out, s)
+}
+
+func autoConvert_v2_PodEndpoint_To_v1_PodEndpoint(in *PodEndpoint, out *v1.PodEndpoint, s conversion.Scope) error {
+	out.PodName = in.PodName
+	out.Ordinal = in.Ordinal
+	out.Address = in.Address
+	out.Ports = *(*[]v1.PodEndpointPort)(unsafe.Pointer(&in.Ports))
+	out.Ready = in.Ready
+	return nil
+}
+
+// Convert_v2_PodEndpoint_To_v1_PodEndpoint is an autogenerated conversion function.
+func Convert_v2_PodEndpoint_To_v1_PodEndpoint(in *PodEndpoint, out *v1.PodEndpoint, s conversion.Scope) error {
+	return autoConvert_v2_PodEndpoint_To_v1_PodEndpoint(in, out, s)
+}
+
+func autoConvert_v1_PodEndpoint_To_v2_PodEndpoint(in *v1.PodEndpoint, out *PodEndpoint, s conversion.Scope) error {
+	out.PodName = in.PodName
+	out.Ordinal = in.Ordinal
+	out.Address = in.Address
+	out.Ports = *(*[]PodEndpointPort)(unsafe.Pointer(&in.Ports))
+	out.Ready = in.Ready
+	return nil
+}
+
+// Convert_v1_PodEndpoint_To_v2_PodEndpoint is an autogenerated conversion function.
+func Convert_v1_PodEndpoint_To_v2_PodEndpoint(in *v1.PodEndpoint, out *PodEndpoint, s conversion.Scope) error {
+	return autoConvert_v1_PodEndpoint_To_v2_PodEndpoint(in, out, s)
+}
+
+func autoConvert_v2_PodEndpointPort_To_v1_PodEndpointPort(in *PodEndpointPort, out *v1.PodEndpointPort, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Port = in.Port
+	out.Protocol = corev1.Protocol(in.Protocol)
+	return nil
+}
+
+// Convert_v2_PodEndpointPort_To_v1_PodEndpointPort is an autogenerated conversion function.
+func Convert_v2_PodEndpointPort_To_v1_PodEndpointPort(in *PodEndpointPort, out *v1.PodEndpointPort, s conversion.Scope) error {
+	return autoConvert_v2_PodEndpointPort_To_v1_PodEndpointPort(in, out, s)
+}
+
+func autoConvert_v1_PodEndpointPort_To_v2_PodEndpointPort(in *v1.PodEndpointPort, out *PodEndpointPort, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Port = in.Port
+	out.Protocol = corev1.Protocol(in.Protocol)
+	return nil
+}
+
+// Convert_v1_PodEndpointPort_To_v2_PodEndpointPort is an autogenerated conversion function.
+func Convert_v1_PodEndpointPort_To_v2_PodEndpointPort(in *v1.PodEndpointPort, out *PodEndpointPort, s conversion.Scope) error {
+	return autoConvert_v1_PodEndpointPort_To_v2_PodEndpointPort(in, out, s)
+}
+
+func autoConvert_v2_PodServiceSpec_To_v1_PodServiceSpec(in *PodServiceSpec, out *v1.PodServiceSpec, s conversion.Scope) error {
+	out.ServiceType = corev1.ServiceType(in.ServiceType)
+	out.ServicePorts = *(*[]corev1.ServicePort)(unsafe.Pointer(&in.ServicePorts))
+	return nil
+}
+
+// Convert_v2_PodServiceSpec_To_v1_PodServiceSpec is an autogenerated conversion function.
+func Convert_v2_PodServiceSpec_To_v1_PodServiceSpec(in *PodServiceSpec, out *v1.PodServiceSpec, s conversion.Scope) error {
+	return autoConvert_v2_PodServiceSpec_To_v1_PodServiceSpec(in, out, s)
+}
+
+func autoConvert_v1_PodServiceSpec_To_v2_PodServiceSpec(in *v1.PodServiceSpec, out *PodServiceSpec, s conversion.Scope) error {
+	out.ServiceType = corev1.ServiceType(in.ServiceType)
+	out.ServicePorts = *(*[]corev1.ServicePort)(unsafe.Pointer(&in.ServicePorts))
+	return nil
+}
+
+// Convert_v1_PodServiceSpec_To_v2_PodServiceSpec is an autogenerated conversion function.
+func Convert_v1_PodServiceSpec_To_v2_PodServiceSpec(in *v1.PodServiceSpec, out *PodServiceSpec, s conversion.Scope) error {
+	return autoConvert_v1_PodServiceSpec_To_v2_PodServiceSpec(in, out, s)
+}
+
+func autoConvert_v2_ReplicaSchedule_To_v1_ReplicaSchedule(in *ReplicaSchedule, out *v1.ReplicaSchedule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Schedule = in.Schedule
+	out.TimeZone = in.TimeZone
+	out.Replicas = in.Replicas
+	return nil
+}
+
+// Convert_v2_ReplicaSchedule_To_v1_ReplicaSchedule is an autogenerated conversion function.
+func Convert_v2_ReplicaSchedule_To_v1_ReplicaSchedule(in *ReplicaSchedule, out *v1.ReplicaSchedule, s conversion.Scope) error {
+	return autoConvert_v2_ReplicaSchedule_To_v1_ReplicaSchedule(in, out, s)
+}
+
+func autoConvert_v1_ReplicaSchedule_To_v2_ReplicaSchedule(in *v1.ReplicaSchedule, out *ReplicaSchedule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Schedule = in.Schedule
+	out.TimeZone = in.TimeZone
+	out.Replicas = in.Replicas
+	return nil
+}
+
+// Convert_v1_ReplicaSchedule_To_v2_ReplicaSchedule is an autogenerated conversion function.
+func Convert_v1_ReplicaSchedule_To_v2_ReplicaSchedule(in *v1.ReplicaSchedule, out *ReplicaSchedule, s conversion.Scope) error {
+	return autoConvert_v1_ReplicaSchedule_To_v2_ReplicaSchedule(in, out, s)
+}
+
+func autoConvert_v2_ScheduleStatus_To_v1_ScheduleStatus(in *ScheduleStatus, out *v1.ScheduleStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Replicas = in.Replicas
+	out.LastScheduleTime = in.LastScheduleTime
+	return nil
+}
+
+// Convert_v2_ScheduleStatus_To_v1_ScheduleStatus is an autogenerated conversion function.
+func Convert_v2_ScheduleStatus_To_v1_ScheduleStatus(in *ScheduleStatus, out *v1.ScheduleStatus, s conversion.Scope) error {
+	return autoConvert_v2_ScheduleStatus_To_v1_ScheduleStatus(in, out, s)
+}
+
+func autoConvert_v1_ScheduleStatus_To_v2_ScheduleStatus(in *v1.ScheduleStatus, out *ScheduleStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Replicas = in.Replicas
+	out.LastScheduleTime = in.LastScheduleTime
+	return nil
+}
+
+// Convert_v1_ScheduleStatus_To_v2_ScheduleStatus is an autogenerated conversion function.
+func Convert_v1_ScheduleStatus_To_v2_ScheduleStatus(in *v1.ScheduleStatus, out *ScheduleStatus, s conversion.Scope) error {
+	return autoConvert_v1_ScheduleStatus_To_v2_ScheduleStatus(in, out, s)
+}
+
+func autoConvert_v2_StatefulSetStatus_To_v1_StatefulSetStatus(in *StatefulSetStatus, out *v1.StatefulSetStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.Replicas = in.Replicas
+	out.ReadyReplicas = in.ReadyReplicas
+	out.CurrentReplicas = in.CurrentReplicas
+	out.UpdatedReplicas = in.UpdatedReplicas
+	out.CurrentRevision = in.CurrentRevision
+	out.UpdateRevision = in.UpdateRevision
+	out.CollisionCount = (*int32)(unsafe.Pointer(in.CollisionCount))
+	return nil
+}
+
+// Convert_v2_StatefulSetStatus_To_v1_StatefulSetStatus is an autogenerated conversion function.
+func Convert_v2_StatefulSetStatus_To_v1_StatefulSetStatus(in *StatefulSetStatus, out *v1.StatefulSetStatus, s conversion.Scope) error {
+	return autoConvert_v2_StatefulSetStatus_To_v1_StatefulSetStatus(in, out, s)
+}
+
+func autoConvert_v1_StatefulSetStatus_To_v2_StatefulSetStatus(in *v1.StatefulSetStatus, out *StatefulSetStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.Replicas = in.Replicas
+	out.ReadyReplicas = in.ReadyReplicas
+	out.CurrentReplicas = in.CurrentReplicas
+	out.UpdatedReplicas = in.UpdatedReplicas
+	out.CurrentRevision = in.CurrentRevision
+	out.UpdateRevision = in.UpdateRevision
+	out.CollisionCount = (*int32)(unsafe.Pointer(in.CollisionCount))
+	return nil
+}
+
+// Convert_v1_StatefulSetStatus_To_v2_StatefulSetStatus is an autogenerated conversion function.
+func Convert_v1_StatefulSetStatus_To_v2_StatefulSetStatus(in *v1.StatefulSetStatus, out *StatefulSetStatus, s conversion.Scope) error {
+	return autoConvert_v1_StatefulSetStatus_To_v2_StatefulSetStatus(in, out, s)
+}
+
+func autoConvert_v2_TemplateMigrationStatus_To_v1_TemplateMigrationStatus(in *TemplateMigrationStatus, out *v1.TemplateMigrationStatus, s conversion.Scope) error {
+	out.From = v1.TemplateType(in.From)
+	out.To = v1.TemplateType(in.To)
+	out.Phase = v1.TemplateMigrationPhase(in.Phase)
+	out.StartedAt = in.StartedAt
+	return nil
+}
+
+// Convert_v2_TemplateMigrationStatus_To_v1_TemplateMigrationStatus is an autogenerated conversion function.
+func Convert_v2_TemplateMigrationStatus_To_v1_TemplateMigrationStatus(in *TemplateMigrationStatus, out *v1.TemplateMigrationStatus, s conversion.Scope) error {
+	return autoConvert_v2_TemplateMigrationStatus_To_v1_TemplateMigrationStatus(in, out, s)
+}
+
+func autoConvert_v1_TemplateMigrationStatus_To_v2_TemplateMigrationStatus(in *v1.TemplateMigrationStatus, out *TemplateMigrationStatus, s conversion.Scope) error {
+	out.From = TemplateType(in.From)
+	out.To = TemplateType(in.To)
+	out.Phase = TemplateMigrationPhase(in.Phase)
+	out.StartedAt = in.StartedAt
+	return nil
+}
+
+// Convert_v1_TemplateMigrationStatus_To_v2_TemplateMigrationStatus is an autogenerated conversion function.
+func Convert_v1_TemplateMigrationStatus_To_v2_TemplateMigrationStatus(in *v1.TemplateMigrationStatus, out *TemplateMigrationStatus, s conversion.Scope) error {
+	return autoConvert_v1_TemplateMigrationStatus_To_v2_TemplateMigrationStatus(in, out, s)
+}
+
+func autoConvert_v2_UpdateHooks_To_v1_UpdateHooks(in *UpdateHooks, out *v1.UpdateHooks, s conversion.Scope) error {
+	out.PreUpdate = (*batchv1.JobSpec)(unsafe.Pointer(in.PreUpdate))
+	out.PostUpdate = (*batchv1.JobSpec)(unsafe.Pointer(in.PostUpdate))
+	out.Timeout = (*metav1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_v2_UpdateHooks_To_v1_UpdateHooks is an autogenerated conversion function.
+func Convert_v2_UpdateHooks_To_v1_UpdateHooks(in *UpdateHooks, out *v1.UpdateHooks, s conversion.Scope) error {
+	return autoConvert_v2_UpdateHooks_To_v1_UpdateHooks(in, out, s)
+}
+
+func autoConvert_v1_UpdateHooks_To_v2_UpdateHooks(in *v1.UpdateHooks, out *UpdateHooks, s conversion.Scope) error {
+	out.PreUpdate = (*batchv1.JobSpec)(unsafe.Pointer(in.PreUpdate))
+	out.PostUpdate = (*batchv1.JobSpec)(unsafe.Pointer(in.PostUpdate))
+	out.Timeout = (*metav1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_v1_UpdateHooks_To_v2_UpdateHooks is an autogenerated conversion function.
+func Convert_v1_UpdateHooks_To_v2_UpdateHooks(in *v1.UpdateHooks, out *UpdateHooks, s conversion.Scope) error {
+	return autoConvert_v1_UpdateHooks_To_v2_UpdateHooks(in, out, s)
+}
+
+func autoConvert_v2_UpdateWindow_To_v1_UpdateWindow(in *UpdateWindow, out *v1.UpdateWindow, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Duration = in.Duration
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_v2_UpdateWindow_To_v1_UpdateWindow is an autogenerated conversion function.
+func Convert_v2_UpdateWindow_To_v1_UpdateWindow(in *UpdateWindow, out *v1.UpdateWindow, s conversion.Scope) error {
+	return autoConvert_v2_UpdateWindow_To_v1_UpdateWindow(in, out, s)
+}
+
+func autoConvert_v1_UpdateWindow_To_v2_UpdateWindow(in *v1.UpdateWindow, out *UpdateWindow, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Duration = in.Duration
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_v1_UpdateWindow_To_v2_UpdateWindow is an autogenerated conversion function.
+func Convert_v1_UpdateWindow_To_v2_UpdateWindow(in *v1.UpdateWindow, out *UpdateWindow, s conversion.Scope) error {
+	return autoConvert_v1_UpdateWindow_To_v2_UpdateWindow(in, out, s)
+}
@@ -0,0 +1,847 @@
+// +build !ignore_autogenerated
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentStatus) DeepCopyInto(out *DeploymentStatus) {
+	*out = *in
+	if in.CollisionCount != nil {
+		in, out := &in.CollisionCount, &out.CollisionCount
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentStatus.
+func (in *DeploymentStatus) DeepCopy() *DeploymentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelixSaga) DeepCopyInto(out *HelixSaga) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelixSaga.
+func (in *HelixSaga) DeepCopy() *HelixSaga {
+	if in == nil {
+		return nil
+	}
+	out := new(HelixSaga)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HelixSaga) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelixSagaAppDefaults) DeepCopyInto(out *HelixSagaAppDefaults) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContainerPorts != nil {
+		in, out := &in.ContainerPorts, &out.ContainerPorts
+		*out = make([]v1.ContainerPort, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServicePorts != nil {
+		in, out := &in.ServicePorts, &out.ServicePorts
+		*out = make([]v1.ServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceSourceRanges != nil {
+		in, out := &in.ServiceSourceRanges, &out.ServiceSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceSourceRangeRefs != nil {
+		in, out := &in.ServiceSourceRangeRefs, &out.ServiceSourceRangeRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodService != nil {
+		in, out := &in.PodService, &out.PodService
+		*out = new(PodServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageHistoryLimit != nil {
+		in, out := &in.ImageHistoryLimit, &out.ImageHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImagePolicy != nil {
+		in, out := &in.ImagePolicy, &out.ImagePolicy
+		*out = new(ImagePolicy)
+		**out = **in
+	}
+	if in.UpdateWindow != nil {
+		in, out := &in.UpdateWindow, &out.UpdateWindow
+		*out = new(UpdateWindow)
+		**out = **in
+	}
+	if in.UpdateHooks != nil {
+		in, out := &in.UpdateHooks, &out.UpdateHooks
+		*out = new(UpdateHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]ReplicaSchedule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelixSagaAppDefaults.
+func (in *HelixSagaAppDefaults) DeepCopy() *HelixSagaAppDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(HelixSagaAppDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelixSagaAppSpec) DeepCopyInto(out *HelixSagaAppSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContainerPorts != nil {
+		in, out := &in.ContainerPorts, &out.ContainerPorts
+		*out = make([]v1.ContainerPort, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServicePorts != nil {
+		in, out := &in.ServicePorts, &out.ServicePorts
+		*out = make([]v1.ServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceSourceRanges != nil {
+		in, out := &in.ServiceSourceRanges, &out.ServiceSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceSourceRangeRefs != nil {
+		in, out := &in.ServiceSourceRangeRefs, &out.ServiceSourceRangeRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodService != nil {
+		in, out := &in.PodService, &out.PodService
+		*out = new(PodServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageHistoryLimit != nil {
+		in, out := &in.ImageHistoryLimit, &out.ImageHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImagePolicy != nil {
+		in, out := &in.ImagePolicy, &out.ImagePolicy
+		*out = new(ImagePolicy)
+		**out = **in
+	}
+	if in.UpdateWindow != nil {
+		in, out := &in.UpdateWindow, &out.UpdateWindow
+		*out = new(UpdateWindow)
+		**out = **in
+	}
+	if in.UpdateHooks != nil {
+		in, out := &in.UpdateHooks, &out.UpdateHooks
+		*out = new(UpdateHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]ReplicaSchedule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelixSagaAppSpec.
+func (in *HelixSagaAppSpec) DeepCopy() *HelixSagaAppSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HelixSagaAppSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelixSagaAppStatus) DeepCopyInto(out *HelixSagaAppStatus) {
+	*out = *in
+	in.Deployment.DeepCopyInto(&out.Deployment)
+	in.StatefulSet.DeepCopyInto(&out.StatefulSet)
+	if in.PodEndpoints != nil {
+		in, out := &in.PodEndpoints, &out.PodEndpoints
+		*out = make([]PodEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.ImageWatch.DeepCopyInto(&out.ImageWatch)
+	in.CurrentImage.DeepCopyInto(&out.CurrentImage)
+	if in.ImageHistory != nil {
+		in, out := &in.ImageHistory, &out.ImageHistory
+		*out = make([]ImageRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.ImagePolicy.DeepCopyInto(&out.ImagePolicy)
+	if in.PendingUpdate != nil {
+		in, out := &in.PendingUpdate, &out.PendingUpdate
+		*out = new(PendingUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hook != nil {
+		in, out := &in.Hook, &out.Hook
+		*out = new(HookStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageUpdate != nil {
+		in, out := &in.ImageUpdate, &out.ImageUpdate
+		*out = new(ImageUpdateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(ScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(MaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TemplateMigration != nil {
+		in, out := &in.TemplateMigration, &out.TemplateMigration
+		*out = new(TemplateMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelixSagaAppStatus.
+func (in *HelixSagaAppStatus) DeepCopy() *HelixSagaAppStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HelixSagaAppStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelixSagaConfigMap) DeepCopyInto(out *HelixSagaConfigMap) {
+	*out = *in
+	in.Volume.DeepCopyInto(&out.Volume)
+	in.VolumeMount.DeepCopyInto(&out.VolumeMount)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelixSagaConfigMap.
+func (in *HelixSagaConfigMap) DeepCopy() *HelixSagaConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(HelixSagaConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelixSagaList) DeepCopyInto(out *HelixSagaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HelixSaga, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelixSagaList.
+func (in *HelixSagaList) DeepCopy() *HelixSagaList {
+	if in == nil {
+		return nil
+	}
+	out := new(HelixSagaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HelixSagaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelixSagaSpec) DeepCopyInto(out *HelixSagaSpec) {
+	*out = *in
+	in.ConfigMap.DeepCopyInto(&out.ConfigMap)
+	if in.UpdateWindow != nil {
+		in, out := &in.UpdateWindow, &out.UpdateWindow
+		*out = new(UpdateWindow)
+		**out = **in
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(HelixSagaAppDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Apps != nil {
+		in, out := &in.Apps, &out.Apps
+		*out = make([]HelixSagaAppSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(Maintenance)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelixSagaSpec.
+func (in *HelixSagaSpec) DeepCopy() *HelixSagaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HelixSagaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelixSagaStatus) DeepCopyInto(out *HelixSagaStatus) {
+	*out = *in
+	if in.Apps != nil {
+		in, out := &in.Apps, &out.Apps
+		*out = make([]HelixSagaAppStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelixSagaStatus.
+func (in *HelixSagaStatus) DeepCopy() *HelixSagaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HelixSagaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HookStatus) DeepCopyInto(out *HookStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookStatus.
+func (in *HookStatus) DeepCopy() *HookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePolicy) DeepCopyInto(out *ImagePolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePolicy.
+func (in *ImagePolicy) DeepCopy() *ImagePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePolicyStatus) DeepCopyInto(out *ImagePolicyStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePolicyStatus.
+func (in *ImagePolicyStatus) DeepCopy() *ImagePolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRecord) DeepCopyInto(out *ImageRecord) {
+	*out = *in
+	in.DeployedAt.DeepCopyInto(&out.DeployedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRecord.
+func (in *ImageRecord) DeepCopy() *ImageRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageUpdateStatus) DeepCopyInto(out *ImageUpdateStatus) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageUpdateStatus.
+func (in *ImageUpdateStatus) DeepCopy() *ImageUpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageUpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageWatchStatus) DeepCopyInto(out *ImageWatchStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageWatchStatus.
+func (in *ImageWatchStatus) DeepCopy() *ImageWatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageWatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Maintenance) DeepCopyInto(out *Maintenance) {
+	*out = *in
+	if in.KeepRunning != nil {
+		in, out := &in.KeepRunning, &out.KeepRunning
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Maintenance.
+func (in *Maintenance) DeepCopy() *Maintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(Maintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceStatus) DeepCopyInto(out *MaintenanceStatus) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceStatus.
+func (in *MaintenanceStatus) DeepCopy() *MaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingUpdate) DeepCopyInto(out *PendingUpdate) {
+	*out = *in
+	in.DetectedAt.DeepCopyInto(&out.DetectedAt)
+	in.NextWindow.DeepCopyInto(&out.NextWindow)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingUpdate.
+func (in *PendingUpdate) DeepCopy() *PendingUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodEndpoint) DeepCopyInto(out *PodEndpoint) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]PodEndpointPort, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodEndpoint.
+func (in *PodEndpoint) DeepCopy() *PodEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(PodEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodEndpointPort) DeepCopyInto(out *PodEndpointPort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodEndpointPort.
+func (in *PodEndpointPort) DeepCopy() *PodEndpointPort {
+	if in == nil {
+		return nil
+	}
+	out := new(PodEndpointPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodServiceSpec) DeepCopyInto(out *PodServiceSpec) {
+	*out = *in
+	if in.ServicePorts != nil {
+		in, out := &in.ServicePorts, &out.ServicePorts
+		*out = make([]v1.ServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodServiceSpec.
+func (in *PodServiceSpec) DeepCopy() *PodServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaSchedule) DeepCopyInto(out *ReplicaSchedule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaSchedule.
+func (in *ReplicaSchedule) DeepCopy() *ReplicaSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleStatus) DeepCopyInto(out *ScheduleStatus) {
+	*out = *in
+	in.LastScheduleTime.DeepCopyInto(&out.LastScheduleTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleStatus.
+func (in *ScheduleStatus) DeepCopy() *ScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulSetStatus) DeepCopyInto(out *StatefulSetStatus) {
+	*out = *in
+	if in.CollisionCount != nil {
+		in, out := &in.CollisionCount, &out.CollisionCount
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetStatus.
+func (in *StatefulSetStatus) DeepCopy() *StatefulSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateMigrationStatus) DeepCopyInto(out *TemplateMigrationStatus) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateMigrationStatus.
+func (in *TemplateMigrationStatus) DeepCopy() *TemplateMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateHooks) DeepCopyInto(out *UpdateHooks) {
+	*out = *in
+	if in.PreUpdate != nil {
+		in, out := &in.PreUpdate, &out.PreUpdate
+		*out = new(batchv1.JobSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostUpdate != nil {
+		in, out := &in.PostUpdate, &out.PostUpdate
+		*out = new(batchv1.JobSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateHooks.
+func (in *UpdateHooks) DeepCopy() *UpdateHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateWindow) DeepCopyInto(out *UpdateWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateWindow.
+func (in *UpdateWindow) DeepCopy() *UpdateWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateWindow)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -2,6 +2,7 @@ package helixsaga
 
 const controllerAgentName = "helix-saga-controller"
 const OperatorKindName = "HelixSaga"
+const HelixSagaSetKindName = "HelixSagaSet"
 
 const (
 	// SuccessSynced is used as part of the Event 'reason' when a Foo is synced
@@ -27,4 +28,151 @@ const (
 	PodNamespace      = "POD_NAMESPACE"
 	PodIP             = "POD_IP"
 	PodServiceAccount = "POD_SERVICE_ACCOUNT"
-)
\ No newline at end of file
+)
+
+const (
+	// ManagedAnnotations records the annotation keys of a Service which were owned by the operator
+	ManagedAnnotations = "helixsaga.nevercase.io/managed-annotations"
+	// ImageDigestAnnotation is the pod template annotation of the digest recorded by the UpdateTrigger restart
+	ImageDigestAnnotation = "helixsaga.nevercase.io/image-digest"
+	// ServiceNameAnnotation is the pod template annotation of the serviceName which the StatefulSet has been recreated for.
+	// It changes the revision of the pod template, so the adopted pods would be rolled to get the new subdomain.
+	ServiceNameAnnotation = "helixsaga.nevercase.io/service-name"
+	// ApplyNowAnnotation applies the pending updates of the HelixSaga regardless of the UpdateWindow.
+	// The value was "true", "*" or the comma separated names of the apps, and it would be removed after being applied.
+	ApplyNowAnnotation = "helixsaga.nevercase.io/apply-now"
+	// HookImageAnnotation is the annotation of a hook Job which records the image being rolled out
+	HookImageAnnotation = "helixsaga.nevercase.io/hook-image"
+	// HookTypeLabel is the label of a hook Job which records the HookType
+	HookTypeLabel = "helixsaga.nevercase.io/hook"
+	// HelixSagaSetLabel is the label of a HelixSaga which records the name of the HelixSagaSet generating it
+	HelixSagaSetLabel = "helixsaga.nevercase.io/set"
+	// TemplateLabel is the pod label which records the Template of the workload. It was not a part of the selectors,
+	// and the Service would only select it while the Template of the app was being switched.
+	TemplateLabel = "helixsaga.nevercase.io/template"
+)
+
+const (
+	// HeadlessServiceNameTemplate is the name template of the governing Service of a StatefulSet app
+	HeadlessServiceNameTemplate = "%s-headless"
+	// RolePodService is the value of the role label of the Services which were created for every pod
+	RolePodService = "pod-service"
+)
+
+const (
+	// ServiceNameConflict is used as part of the Event 'reason' when a Service of a StatefulSet app was named after another app
+	ServiceNameConflict = "ServiceNameConflict"
+	// StatefulSetRecreated is used as part of the Event 'reason' when a StatefulSet has been recreated for its serviceName
+	StatefulSetRecreated = "StatefulSetRecreated"
+
+	MessageServiceNameConflict  = "HelixSaga would not be synced until the apps have been renamed: %v"
+	MessageStatefulSetRecreated = "StatefulSet %s was recreated with the serviceName %s, its pods were orphaned and would be adopted by the new one"
+)
+
+const (
+	// ImageWatchStarted is used as part of the Event 'reason' when the registry watch of an image has been started
+	ImageWatchStarted = "ImageWatchStarted"
+	// ImageWatchBackoff is used as part of the Event 'reason' when the registry watch of an image would be retried
+	ImageWatchBackoff = "ImageWatchBackoff"
+	// ImageWatchFailed is used as part of the Event 'reason' when the retry budget of the registry watch has been used up
+	ImageWatchFailed = "ImageWatchFailed"
+
+	MessageImageWatchStarted = "Registry watch of image %s started"
+	MessageImageWatchBackoff = "Registry watch of image %s would be retried (%d/%d): %s"
+	MessageImageWatchFailed  = "Registry watch of image %s failed after %d retries: %s"
+)
+
+const (
+	// ImagePullUnsafe is used as part of the Event 'reason' when an auto updated app might keep running a stale image
+	ImagePullUnsafe = "ImagePullUnsafe"
+
+	MessageImagePullUnsafe = "App %s uses imagePullPolicy %s with updateTrigger %s, the nodes which have cached image %s would not pull the new digest"
+)
+
+const (
+	// RollbackInvalid is used as part of the Event 'reason' when the RollbackTo of an app was not a digest
+	RollbackInvalid = "RollbackInvalid"
+
+	MessageRollbackInvalid = "%v"
+)
+
+const (
+	// ImageUpdatePending is used as part of the Event 'reason' when an image update has been queued for the UpdateWindow
+	ImageUpdatePending = "ImageUpdatePending"
+
+	MessageImageUpdatePending = "Update of image %s to %s is pending: %s"
+)
+
+const (
+	// ImageUpdateFailed is used as part of the Event 'reason' when an image update has given up
+	ImageUpdateFailed = "ImageUpdateFailed"
+
+	MessageImageUpdateFailed     = "Update of image %s to %s gave up: %v"
+	MessageImageUpdateScaledDown = "Apps of image %s were left scaled down, the replicas %v could not be restored: %v"
+)
+
+const (
+	// UpdateHookFailed is used as part of the Event 'reason' when a hook Job of the UpdateHooks has failed
+	UpdateHookFailed = "UpdateHookFailed"
+
+	MessageUpdateHookFailed = "%s hook %s of app %s for image %s failed: %v"
+)
+
+const (
+	// HelixSagaSetFailed is used as part of the Event 'reason' when some HelixSagas of a HelixSagaSet couldn't be synced
+	HelixSagaSetFailed = "HelixSagaSetFailed"
+
+	MessageHelixSagaSetFailed = "HelixSagaSet %s couldn't be synced: %s"
+)
+
+const (
+	// DependencyWaiting is used as part of the Event 'reason' when an app was held by its DependsOn
+	DependencyWaiting = "DependencyWaiting"
+	// DependencyInvalid is used as part of the Event 'reason' when the DependsOn of the apps couldn't be sorted
+	DependencyInvalid = "DependencyInvalid"
+
+	MessageDependencyWaiting = "App %s was waiting: %s"
+	MessageDependencyInvalid = "DependsOn of the apps would be ignored: %v"
+)
+
+const (
+	// ScheduleApplied is used as part of the Event 'reason' when an app has been scaled by its Schedules
+	ScheduleApplied = "ScheduleApplied"
+	// ScheduleInvalid is used as part of the Event 'reason' when the Schedules of an app couldn't be parsed
+	ScheduleInvalid = "ScheduleInvalid"
+
+	MessageScheduleApplied = "App %s was scaled to %d by schedule %s"
+	MessageScheduleInvalid = "Schedules of app %s would be ignored: %v"
+)
+
+const (
+	// MaintenanceStarted is used as part of the Event 'reason' when the apps have been stopped by the Maintenance
+	MaintenanceStarted = "MaintenanceStarted"
+	// MaintenanceFinished is used as part of the Event 'reason' when the apps have been restored after the Maintenance
+	MaintenanceFinished = "MaintenanceFinished"
+
+	MessageMaintenanceStarted  = "Maintenance stopped %d apps: %s"
+	MessageMaintenanceFinished = "Maintenance finished, %d apps were restored"
+)
+
+const (
+	// ConditionPaused is the type of the condition which reports whether the reconciliation was paused
+	ConditionPaused = "Paused"
+
+	// ReasonHelixSagaPaused is the reason of the Paused condition when the HelixSaga was paused
+	ReasonHelixSagaPaused = "HelixSagaPaused"
+	// ReasonAppsPaused is the reason of the Paused condition when some apps were paused
+	ReasonAppsPaused = "AppsPaused"
+	// ReasonReconciling is the reason of the Paused condition when nothing was paused
+	ReasonReconciling = "Reconciling"
+)
+
+const (
+	// TemplateMigrating is used as part of the Event 'reason' when the Template of an app has been started switching
+	TemplateMigrating = "TemplateMigrating"
+	// TemplateMigrated is used as part of the Event 'reason' when the old workload of an app has been deleted after the switch
+	TemplateMigrated = "TemplateMigrated"
+
+	MessageTemplateMigrating = "App %s was switching from %s to %s, the old workload would be kept until the new one has been ready"
+	MessageTemplateMigrated  = "App %s was switched from %s to %s"
+)
@@ -11,9 +11,11 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
@@ -22,6 +24,8 @@ import (
 	helixsagascheme "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/scheme"
 	informersext "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/informers/externalversions"
 	informers "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/informers/externalversions/helixsaga/v1"
+	listers "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/listers/helixsaga/v1"
+	"github.com/Shanghai-Lunara/helixsaga-operator/pkg/serviceloadbalancer"
 	harbor "github.com/nevercase/harbor-api"
 	k8scorev1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
 )
@@ -30,12 +34,23 @@ func NewController(
 	controllerName string,
 	kubeclientset kubernetes.Interface,
 	sampleclientset helixsagaclientset.Interface,
+	watchers *Watchers,
 	stopCh <-chan struct{}) k8scorev1.KubernetesControllerV1 {
 
-	controller := &controller{}
-
 	exampleInformerFactory := informersext.NewSharedInformerFactory(sampleclientset, time.Second*30)
 	fooInformer := exampleInformerFactory.Nevercase().V1().HelixSagas()
+	setInformer := exampleInformerFactory.Nevercase().V1().HelixSagaSets()
+	templateInformer := exampleInformerFactory.Nevercase().V1().HelixSagaTemplates()
+	controller := &controller{
+		watchers:       watchers,
+		lastCache:      make(map[string]*helixsagav1.HelixSaga, 0),
+		kubeClientSet:  kubeclientset,
+		clientSet:      sampleclientset,
+		lister:         fooInformer.Lister(),
+		setLister:      setInformer.Lister(),
+		templateLister: templateInformer.Lister(),
+	}
+	serviceloadbalancer.OnChange(controller.SyncLoadBalancerServices)
 	//roInformerFactory := informersv2.NewSharedInformerFactory(sampleclientset, time.Second*30)
 
 	opt := k8scorev1.NewOption(&helixsagav1.HelixSaga{},
@@ -49,14 +64,32 @@ func NewController(
 		controller.Get,
 		controller.Sync,
 		controller.SyncStatus)
+	setOpt := k8scorev1.NewOption(&helixsagav1.HelixSagaSet{},
+		controllerName,
+		HelixSagaSetKindName,
+		nil,
+		sampleclientset,
+		setInformer,
+		setInformer.Informer(),
+		controller.CompareHelixSagaSetVersion,
+		controller.GetHelixSagaSet,
+		controller.SyncHelixSagaSet,
+		controller.SyncHelixSagaSetStatus)
 	opts := k8scorev1.NewOptions()
-	if err := opts.Add(opt); err != nil {
+	if err := opts.Add(opt, setOpt); err != nil {
 		klog.Fatal(err)
 	}
 	op := k8scorev1.NewKubernetesOperator(kubeclientset, stopCh, controllerName, opts)
 	kc := k8scorev1.NewKubernetesController(op)
+	templateHandler, helixSagaHandler := controller.helixSagaSetHandlers(kc.EnqueueFoo)
+	templateInformer.Informer().AddEventHandler(templateHandler)
+	fooInformer.Informer().AddEventHandler(helixSagaHandler)
 	//roInformerFactory.Start(stopCh)
 	exampleInformerFactory.Start(stopCh)
+	go controller.resumeAfterSync(stopCh, fooInformer.Informer().HasSynced)
+	go wait.Until(controller.SyncImagePolicies, ImagePolicyInterval, stopCh)
+	go wait.Until(controller.SyncPendingUpdates, PendingUpdateInterval, stopCh)
+	go wait.Until(controller.SyncSchedules, ScheduleInterval, stopCh)
 	return kc
 }
 
@@ -65,12 +98,20 @@ func NewOption(controllerName string, cfg *rest.Config, stopCh <-chan struct{},
 	if err != nil {
 		klog.Fatalf("Error building clientSet: %s", err.Error())
 	}
-	controller := &controller{
-		watchers:  NewWatchers(harborConfig),
-		lastCache: make(map[string]*helixsagav1.HelixSaga, 0),
+	kubeClientSet, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building kubernetes clientset: %s", err.Error())
 	}
 	informerFactory := informersext.NewSharedInformerFactory(c, time.Second*30)
 	fooInformer := informerFactory.Nevercase().V1().HelixSagas()
+	controller := &controller{
+		watchers:      NewWatchers(harborConfig),
+		lastCache:     make(map[string]*helixsagav1.HelixSaga, 0),
+		kubeClientSet: kubeClientSet,
+		clientSet:     c,
+		lister:        fooInformer.Lister(),
+	}
+	serviceloadbalancer.OnChange(controller.SyncLoadBalancerServices)
 	opt := k8scorev1.NewOption(&helixsagav1.HelixSaga{},
 		controllerName,
 		OperatorKindName,
@@ -83,6 +124,10 @@ func NewOption(controllerName string, cfg *rest.Config, stopCh <-chan struct{},
 		controller.Sync,
 		controller.SyncStatus)
 	informerFactory.Start(stopCh)
+	go controller.resumeAfterSync(stopCh, fooInformer.Informer().HasSynced)
+	go wait.Until(controller.SyncImagePolicies, ImagePolicyInterval, stopCh)
+	go wait.Until(controller.SyncPendingUpdates, PendingUpdateInterval, stopCh)
+	go wait.Until(controller.SyncSchedules, ScheduleInterval, stopCh)
 	return opt
 }
 
@@ -90,6 +135,15 @@ type controller struct {
 	mu        sync.Mutex
 	watchers  *Watchers
 	lastCache map[string]*helixsagav1.HelixSaga
+
+	kubeClientSet kubernetes.Interface
+	clientSet     helixsagaclientset.Interface
+	// recorder is the EventRecorder of the last Sync, which was used by the background updates
+	recorder record.EventRecorder
+	lister   listers.HelixSagaLister
+	// setLister and templateLister were only set by the NewController which syncs the HelixSagaSets
+	setLister      listers.HelixSagaSetLister
+	templateLister listers.HelixSagaTemplateLister
 }
 
 func (c *controller) CompareResourceVersion(old, new interface{}) bool {
@@ -115,51 +169,46 @@ func (c *controller) Sync(obj interface{}, clientObj interface{}, ks k8scorev1.K
 	hs := obj.(*helixsagav1.HelixSaga)
 	clientSet := clientObj.(helixsagaclientset.Interface)
 	name := fmt.Sprintf("%s/%s", hs.Namespace, hs.Name)
+	c.mu.Lock()
+	c.recorder = recorder
+	c.mu.Unlock()
 	var lastCache *helixsagav1.HelixSaga
 	if len(c.lastCache) > 0 {
 		if t, ok := c.lastCache[name]; ok {
 			lastCache = t
 			if len(t.Spec.Applications) > 0 {
 				names := make(map[string]bool, len(hs.Spec.Applications))
-				whiteLists := make(map[string]bool, len(hs.Spec.Applications))
 				images := make(map[string]int, 0)
-				for _, v := range hs.Spec.Applications {
-					names[v.Spec.Name] = true
-					if _, ok := images[v.Spec.Name]; ok {
-						images[v.Spec.Name] += 1
-					} else {
-						images[v.Spec.Name] = 1
-					}
-					whiteLists[v.Spec.Name] = v.Spec.ServiceWhiteList
+				for _, v := range GetAppSpecs(hs) {
+					names[v.Name] = true
+					images[v.Image] += 1
 				}
-				for _, v := range lastCache.Spec.Applications {
-					if _, ok := names[v.Spec.Name]; !ok {
-						// stop watching before removing apps
-						wo := &WatchOption{
+				for i, v := range GetAppSpecs(lastCache) {
+					// stop watching the images which were no longer used, e.g. replaced by the ImagePolicy
+					if _, ok := images[v.Image]; !ok {
+						klog.Infof("HelixSaga crdName:%s image:%s has been removed", hs.Name, v.Image)
+						c.watchers.UnSubscribe(&WatchOption{
 							Namespace:    hs.Namespace,
 							OperatorName: hs.Name,
-							Image:        v.Spec.Image,
-						}
-						if _, ok := images[v.Spec.Image]; !ok {
-							klog.Infof("HelixSaga crdName:%s image:%s has been removed", hs.Name, v.Spec.Image)
-							c.watchers.UnSubscribe(wo)
-						}
-						klog.Info("remove app-name:", v.Spec.Name)
-						if v.Spec.Template == "" {
-							v.Spec.Template = helixsagav1.TemplateTypeStatefulSet
+							Image:        v.Image,
+						})
+					}
+					if _, ok := names[v.Name]; !ok {
+						klog.Info("remove app-name:", v.Name)
+						if v.Template == "" {
+							v.Template = helixsagav1.TemplateTypeStatefulSet
 						}
-						if err := DeleteAppResource(ks, hs.Namespace, v.Spec.Name, v.Spec.Template); err != nil {
+						if err := DeleteAppResource(ks, hs.Namespace, hs.Name, v.Name, v.Template); err != nil {
 							klog.V(2).Info(err)
 							return err
 						}
-						if err := DeleteService(ks, hs.Namespace, v.Spec.Name); err != nil {
+						if err := DeleteService(ks, hs.Namespace, v.Name); err != nil {
 							klog.V(2).Info(err)
 							return err
 						}
-					}
-					if whiteList, ok := whiteLists[v.Spec.Name]; ok {
-						if whiteList != v.Spec.ServiceWhiteList {
-							if err := DeleteService(ks, hs.Namespace, v.Spec.Name); err != nil {
+						// the old workload of the app which was removed during the switch of the Template
+						if t := lastCache.Spec.Applications[i].Status.TemplateMigration; t != nil && t.From != v.Template {
+							if err := DeleteAppResource(ks, hs.Namespace, hs.Name, v.Name, t.From); err != nil {
 								klog.V(2).Info(err)
 								return err
 							}
@@ -169,48 +218,150 @@ func (c *controller) Sync(obj interface{}, clientObj interface{}, ks k8scorev1.K
 			}
 		}
 	}
-	for _, v := range hs.Spec.Applications {
+	// the HelixSaga would be synced again after the apps have been stopped or restored by the Maintenance
+	if updated, err := syncMaintenance(clientSet, recorder, hs); err != nil || updated {
+		return err
+	}
+	// NEVER modify objects from the store
+	hs = hs.DeepCopy()
+	// the Defaults would be merged into the specs, which were never written back into the HelixSaga
+	specs := GetAppSpecs(hs)
+	for i := range hs.Spec.Applications {
+		app, spec := &hs.Spec.Applications[i], &specs[i]
+		// the replicas edited during the Maintenance have been recorded by the syncMaintenance,
+		// and they would be applied by the restoreApp after it has been finished
+		if app.Status.Maintenance != nil {
+			var zero int32
+			spec.Replicas = &zero
+		}
+		// the pending update of the replaced image was stale
+		if app.Status.PendingUpdate != nil && app.Status.PendingUpdate.Image != spec.Image {
+			app.Status.PendingUpdate = nil
+		}
+		// the digest of a paused app would not be updated
+		if !IsPaused(hs, spec) {
+			if err := c.watchers.PinAppImage(ks.ClientSet(), hs, app); err != nil {
+				klog.V(2).Info(err)
+				recorder.Eventf(hs, corev1.EventTypeWarning, RollbackInvalid, MessageRollbackInvalid, err)
+			}
+		}
+		if IsImagePullUnsafe(spec) {
+			recorder.Eventf(hs, corev1.EventTypeWarning, ImagePullUnsafe, MessageImagePullUnsafe,
+				spec.Name, GetImagePullPolicy(spec), GetUpdateTrigger(spec), spec.Image)
+		}
+	}
+	// the Services of the apps which were named after each other would be overwritten by each other
+	if err := ValidateServiceNames(specs); err != nil {
+		klog.V(2).Info(err)
+		recorder.Eventf(hs, corev1.EventTypeWarning, ServiceNameConflict, MessageServiceNameConflict, err)
+		return nil
+	}
+	// the apps would be scaled down in the reverse order of the DependsOn, and created or scaled up in the order
+	order, err := SyncOrder(ks, hs.Namespace, specs)
+	sorted := err == nil
+	if !sorted {
+		klog.V(2).Info(err)
+		recorder.Eventf(hs, corev1.EventTypeWarning, DependencyInvalid, MessageDependencyInvalid, err)
+	}
+	paused, drifted := make([]string, 0), make([]string, 0)
+	migrations := make(map[string]*helixsagav1.TemplateMigrationStatus, 0)
+	for _, i := range order {
+		v := specs[i]
 		// starting watching the harbor before creating apps
-		wo := NewWatchOption(context.Background(), ks.ClientSet(), clientSet, hs, v.Spec.Image)
-		if *v.Spec.Replicas > 0 {
+		wo := NewWatchOption(context.Background(), ks.ClientSet(), clientSet, hs, v.Image)
+		wo.Recorder = recorder
+		if appReplicas(&v) > 0 {
 			if err := c.watchers.Subscribe(wo); err != nil {
 				klog.V(2).Info(err)
 				return err
 			}
 		} else {
-			klog.V(4).Infof("HelixSaga crdName:%s image:%s UnSubscribe due to replicas 0", hs.Name, v.Spec.Image)
+			klog.V(4).Infof("HelixSaga crdName:%s image:%s UnSubscribe due to replicas 0", hs.Name, v.Image)
 			c.watchers.UnSubscribe(wo)
 		}
-		// remove old resource if the Template has been changed
-		if lastCache != nil && len(lastCache.Spec.Applications) > 0 {
-			for _, v2 := range lastCache.Spec.Applications {
-				if v2.Spec.Name == v.Spec.Name {
-					if v2.Spec.Template == "" {
-						v2.Spec.Template = helixsagav1.TemplateTypeStatefulSet
-					}
-					if v.Spec.Template == "" {
-						v.Spec.Template = helixsagav1.TemplateTypeStatefulSet
-					}
-					klog.Infof("old template:%v new template:%v", v2.Spec.Template, v.Spec.Template)
-					if v2.Spec.Template != v.Spec.Template {
-						klog.Infof("remove old resource if the Template has been changed old-type:%v new-type:%s", v2.Spec.Template, v.Spec.Template)
-						if err := DeleteAppResource(ks, hs.Namespace, v2.Spec.Name, v2.Spec.Template); err != nil {
-							klog.V(2).Info(err)
-							return err
-						}
-					}
+		// the drift of a paused app would only be reported, e.g. a manual hotfix of the workload would be kept
+		if IsPaused(hs, &v) {
+			paused = append(paused, v.Name)
+			drift, obj, err := GetAppDrift(ks, hs, &v)
+			if err != nil {
+				klog.V(2).Info(err)
+				return err
+			}
+			if drift {
+				drifted = append(drifted, v.Name)
+			}
+			if obj != nil {
+				if err = updateStatus(hs, ks, clientSet, obj, v.Name); err != nil {
+					klog.V(2).Info(err)
+					return err
 				}
 			}
+			continue
 		}
-		if err := NewAppResources(ks, clientSet, hs, &v.Spec, wo); err != nil {
+		// hold the app until the apps it depends on have been ready, or its dependents have been scaled down
+		if sorted {
+			if reason := GetDependencyHold(ks, hs, specs, i); reason != "" {
+				klog.V(4).Infof("HelixSaga crdName:%s app:%s was held: %s", hs.Name, v.Name, reason)
+				recorder.Eventf(hs, corev1.EventTypeNormal, DependencyWaiting, MessageDependencyWaiting, v.Name, reason)
+				continue
+			}
+		}
+		// hold the manual image edit until the PreUpdate hook has been succeeded
+		if c.syncUpdateHooks(ks, clientSet, recorder, hs, &hs.Spec.Applications[i], &v) {
+			continue
+		}
+		if err := NewAppResources(ks, clientSet, hs, &v, wo); err != nil {
+			klog.V(2).Info(err)
+			return err
+		}
+		// the old workload would be deleted after the new one has been ready if the Template has been switched
+		app := &hs.Spec.Applications[i]
+		migration, changed, err := syncTemplateMigration(ks, hs, app, &v, time.Now())
+		if err != nil {
 			klog.V(2).Info(err)
 			return err
 		}
+		if changed {
+			if t := app.Status.TemplateMigration; migration == nil && t != nil && t.Phase == helixsagav1.TemplateMigrationPhaseSwitched {
+				klog.Infof("HelixSaga crdName:%s app:%s was switched from %s to %s", hs.Name, v.Name, t.From, t.To)
+				recorder.Eventf(hs, corev1.EventTypeNormal, TemplateMigrated, MessageTemplateMigrated, v.Name, t.From, t.To)
+			} else if migration != nil && (t == nil || t.From != migration.From) {
+				klog.Infof("HelixSaga crdName:%s app:%s was switching from %s to %s", hs.Name, v.Name, migration.From, migration.To)
+				recorder.Eventf(hs, corev1.EventTypeNormal, TemplateMigrating, MessageTemplateMigrating, v.Name, migration.From, migration.To)
+			}
+			migrations[v.Name] = migration
+		}
+	}
+	// the statuses were recorded after the apps have been synced, since the HelixSaga was updated by them
+	if err = updateTemplateMigrations(clientSet, hs, migrations); err != nil {
+		klog.V(2).Info(err)
+		return err
+	}
+	if err = updatePausedCondition(clientSet, hs, paused, drifted); err != nil {
+		klog.V(2).Info(err)
+		return err
+	}
+	if _, ok := hs.Annotations[ApplyNowAnnotation]; ok {
+		go c.syncPendingUpdates(hs)
 	}
 	recorder.Event(hs, corev1.EventTypeNormal, SuccessSynced, MessageResourceSynced)
 	return nil
 }
 
+// resumeAfterSync resumes the interrupted image updates once the HelixSagas have been listed
+func (c *controller) resumeAfterSync(stopCh <-chan struct{}, hasSynced cache.InformerSynced) {
+	if !cache.WaitForCacheSync(stopCh, hasSynced) {
+		return
+	}
+	c.ResumeImageUpdates()
+}
+
+func (c *controller) getRecorder() record.EventRecorder {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recorder
+}
+
 func (c *controller) SyncStatus(obj interface{}, clientObj interface{}, ks k8scorev1.KubernetesResource, recorder record.EventRecorder) (err error) {
 	var hs *helixsagav1.HelixSaga
 	var appName string
@@ -255,7 +406,7 @@ func (c *controller) SyncStatus(obj interface{}, clientObj interface{}, ks k8sco
 			return fmt.Errorf(ErrResourceNotMatch, "no appName")
 		}
 	}
-	if err := updateStatus(hs, clientSet, obj, appName); err != nil {
+	if err := updateStatus(hs, ks, clientSet, obj, appName); err != nil {
 		return err
 	}
 	recorder.Event(hs, corev1.EventTypeNormal, SuccessSynced, MessageResourceSynced)
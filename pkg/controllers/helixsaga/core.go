@@ -4,17 +4,17 @@ import (
 	"context"
 	"fmt"
 	"github.com/nevercase/k8s-controller-custom-resource/pkg/env"
+	"math"
 	"reflect"
+	"strings"
 	"time"
 
 	appsV1 "k8s.io/api/apps/v1"
 	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
 	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
@@ -38,10 +38,15 @@ func NewAppResources(ks k8sCoreV1.KubernetesResource, client helixSagaClientSet.
 				return err
 			}
 		} else {
-			klog.Info("rds:", *spec.Replicas)
+			klog.Info("rds:", appReplicas(spec))
 			klog.Info("deployment:", *wo.Deployment.Spec.Replicas)
-			if ok := compareDeployment(wo.Deployment, spec); ok {
-				if wo.Deployment, err = ks.Deployment().Update(hs.Namespace, NewDeployment(hs, spec)); err != nil {
+			dp := NewDeployment(hs, spec)
+			if ok := compareDeployment(wo.Deployment, dp); ok {
+				// the pods would not be rolled by the TemplateLabel alone, e.g. the ones created before it
+				if !comparePodTemplate(&wo.Deployment.Spec.Template, &dp.Spec.Template) {
+					dp.Spec.Template.Labels = wo.Deployment.Spec.Template.Labels
+				}
+				if wo.Deployment, err = ks.Deployment().Update(hs.Namespace, dp); err != nil {
 					klog.V(2).Info(err)
 					return err
 				}
@@ -49,6 +54,9 @@ func NewAppResources(ks k8sCoreV1.KubernetesResource, client helixSagaClientSet.
 		}
 		obj = wo.Deployment
 	case helixSagaV1.TemplateTypeStatefulSet:
+		if err = SyncStatefulSetServices(ks, hs, spec); err != nil {
+			return err
+		}
 		wo.StatefulSet, err = ks.StatefulSet().Get(hs.Namespace, spec.Name)
 		if err != nil {
 			klog.Info("statefulSet err:", err)
@@ -60,10 +68,27 @@ func NewAppResources(ks k8sCoreV1.KubernetesResource, client helixSagaClientSet.
 				return err
 			}
 		} else {
-			klog.Info("rds:", *spec.Replicas)
+			klog.Info("rds:", appReplicas(spec))
 			klog.Info("statefulSet:", *wo.StatefulSet.Spec.Replicas)
-			if ok := compareStatefulSet(wo.StatefulSet, spec); ok {
-				if wo.StatefulSet, err = ks.StatefulSet().Update(hs.Namespace, NewStatefulSet(hs, spec)); err != nil {
+			sts := NewStatefulSet(hs, spec)
+			// the pods would not be rolled by the TemplateLabel alone, e.g. the ones created before it
+			if !comparePodTemplate(&wo.StatefulSet.Spec.Template, &sts.Spec.Template) {
+				sts.Spec.Template.Labels = wo.StatefulSet.Spec.Template.Labels
+			}
+			// the pods would be rolled again if the ServiceNameAnnotation of the recreated StatefulSet was dropped
+			if v, ok := wo.StatefulSet.Spec.Template.Annotations[ServiceNameAnnotation]; ok {
+				setPodTemplateAnnotation(&sts.Spec.Template, ServiceNameAnnotation, v)
+			}
+			if wo.StatefulSet.Spec.ServiceName != sts.Spec.ServiceName {
+				// the serviceName of a StatefulSet was immutable, the one created before the headless Service
+				// would be recreated without its pods, and the pods would be rolled to get the stable DNS names
+				if wo.StatefulSet, err = recreateStatefulSet(ks, wo.StatefulSet, sts); err != nil {
+					klog.V(2).Info(err)
+					return err
+				}
+				wo.Eventf(coreV1.EventTypeNormal, StatefulSetRecreated, MessageStatefulSetRecreated, sts.Name, sts.Spec.ServiceName)
+			} else if ok := compareStatefulSet(wo.StatefulSet, sts); ok {
+				if wo.StatefulSet, err = ks.StatefulSet().Update(hs.Namespace, sts); err != nil {
 					klog.V(2).Info(err)
 					return err
 				}
@@ -83,12 +108,25 @@ func NewAppResources(ks k8sCoreV1.KubernetesResource, client helixSagaClientSet.
 				return err
 			}
 			klog.Info("new service check")
-			if _, err = ks.Service().Create(hs.Namespace, NewService(hs, spec)); err != nil {
+			tmpSvc, err := NewService(hs, spec)
+			if err != nil {
+				return err
+			}
+			if _, err = ks.Service().Create(hs.Namespace, tmpSvc); err != nil {
 				return err
 			}
 		} else {
-			tmpSvc := NewService(hs, spec)
-			if ok := compareService(svc, tmpSvc); ok {
+			tmpSvc, err := NewService(hs, spec)
+			if err != nil {
+				return err
+			}
+			// NEVER modify objects from the store
+			svc = svc.DeepCopy()
+			lbChanged, err := reconcileServiceLoadBalancer(svc, spec)
+			if err != nil {
+				return err
+			}
+			if ok := compareService(svc, tmpSvc); ok || lbChanged {
 				svc.Labels = tmpSvc.Labels
 				svc.Spec.Type = tmpSvc.Spec.Type
 				svc.Spec.Ports = tmpSvc.Spec.Ports
@@ -99,12 +137,44 @@ func NewAppResources(ks k8sCoreV1.KubernetesResource, client helixSagaClientSet.
 			}
 		}
 	}
-	if err = updateStatus(hs, client, obj, spec.Name); err != nil {
+	if err = updateStatus(hs, ks, client, obj, spec.Name); err != nil {
 		return err
 	}
 	return nil
 }
 
+// recreateStatefulSet deletes the StatefulSet with its pods orphaned and creates the new one, which would adopt
+// the pods by the same selector. The ServiceNameAnnotation changes the revision of the pod template, so the adopted
+// pods would be rolled in the order of the StatefulSet instead of being kept with the old subdomain.
+// The creation would fail with AlreadyExists until the old one has been removed by the garbage collector,
+// and it would be retried by the next sync.
+func recreateStatefulSet(ks k8sCoreV1.KubernetesResource, old, sts *appsV1.StatefulSet) (*appsV1.StatefulSet, error) {
+	sts = sts.DeepCopy()
+	setPodTemplateAnnotation(&sts.Spec.Template, ServiceNameAnnotation, sts.Spec.ServiceName)
+	if old.DeletionTimestamp == nil {
+		orphan := metav1.DeletePropagationOrphan
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+		err := ks.ClientSet().AppsV1().StatefulSets(old.Namespace).Delete(ctx, old.Name, metav1.DeleteOptions{
+			PropagationPolicy: &orphan,
+			Preconditions:     &metav1.Preconditions{UID: &old.UID},
+		})
+		cancel()
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	klog.Infof("recreate statefulSet namespace:%s name:%s serviceName:%s", old.Namespace, old.Name, sts.Spec.ServiceName)
+	return ks.StatefulSet().Create(old.Namespace, sts)
+}
+
+// setPodTemplateAnnotation sets the annotation of the pod template
+func setPodTemplateAnnotation(t *coreV1.PodTemplateSpec, key, value string) {
+	if t.Annotations == nil {
+		t.Annotations = make(map[string]string, 0)
+	}
+	t.Annotations[key] = value
+}
+
 func compareService(s1 *coreV1.Service, s2 *coreV1.Service) bool {
 	if s1.Spec.Type != s2.Spec.Type {
 		return true
@@ -112,6 +182,9 @@ func compareService(s1 *coreV1.Service, s2 *coreV1.Service) bool {
 	if len(s1.Spec.Ports) != len(s2.Spec.Ports) {
 		return true
 	}
+	if !reflect.DeepEqual(s1.Spec.Selector, s2.Spec.Selector) {
+		return true
+	}
 	for _, v := range s1.Spec.Ports {
 		exist := false
 		for _, v2 := range s2.Spec.Ports {
@@ -145,7 +218,7 @@ func compareService(s1 *coreV1.Service, s2 *coreV1.Service) bool {
 	return false
 }
 
-func updateStatus(foo *helixSagaV1.HelixSaga, clientSet helixSagaClientSet.Interface, obj interface{}, name string) error {
+func updateStatus(foo *helixSagaV1.HelixSaga, ks k8sCoreV1.KubernetesResource, clientSet helixSagaClientSet.Interface, obj interface{}, name string) error {
 	// NEVER modify objects from the store. It's a read-only, local cache.
 	// You can use DeepCopy() to make a deep copy of original object and modify this copy
 	// Or create a copy manually for better performance
@@ -163,6 +236,7 @@ func updateStatus(foo *helixSagaV1.HelixSaga, clientSet helixSagaClientSet.Inter
 				v.Status.Deployment.AvailableReplicas = dp.Status.AvailableReplicas
 				v.Status.Deployment.UnavailableReplicas = dp.Status.UnavailableReplicas
 				v.Status.Deployment.CollisionCount = dp.Status.CollisionCount
+				v.Status.PodEndpoints = nil
 			case reflect.TypeOf(&appsV1.StatefulSet{}):
 				ss := obj.(*appsV1.StatefulSet)
 				v.Status.StatefulSet.ObservedGeneration = ss.Status.ObservedGeneration
@@ -173,12 +247,19 @@ func updateStatus(foo *helixSagaV1.HelixSaga, clientSet helixSagaClientSet.Inter
 				v.Status.StatefulSet.CurrentRevision = ss.Status.CurrentRevision
 				v.Status.StatefulSet.UpdateRevision = ss.Status.UpdateRevision
 				v.Status.StatefulSet.CollisionCount = ss.Status.CollisionCount
+				podEndpoints, err := ListPodEndpoints(ks, foo, GetAppSpec(foo, &v.Spec))
+				if err != nil {
+					klog.V(2).Info(err)
+				} else {
+					v.Status.PodEndpoints = podEndpoints
+				}
 			}
 
 		}
 		t = append(t, v)
 	}
 	fooCopy.Spec.Applications = t
+	fooCopy.Status = SummarizeStatus(fooCopy)
 
 	// If the CustomResourceSubResources feature gate is not enabled,
 	// we must use Update instead of UpdateStatus to update the Status block of the RedisOperator resource.
@@ -191,12 +272,61 @@ func updateStatus(foo *helixSagaV1.HelixSaga, clientSet helixSagaClientSet.Inter
 	return err
 }
 
-func DeleteAppResource(ks k8sCoreV1.KubernetesResource, namespace string, name string, template helixSagaV1.TemplateType) error {
+// SummarizeStatus returns the summary of the statuses of the apps which was shown by the printer columns
+func SummarizeStatus(hs *helixSagaV1.HelixSaga) helixSagaV1.HelixSagaStatus {
+	ready := 0
+	images := make([]string, 0)
+	specs := GetAppSpecs(hs)
+	for i, v := range hs.Spec.Applications {
+		spec := &specs[i]
+		var replicas, readyReplicas int32 = appReplicas(spec), 0
+		if spec.Template == helixSagaV1.TemplateTypeDeployment {
+			readyReplicas = v.Status.Deployment.ReadyReplicas
+		} else {
+			readyReplicas = v.Status.StatefulSet.ReadyReplicas
+		}
+		if readyReplicas >= replicas {
+			ready++
+		}
+		found := false
+		for _, image := range images {
+			if image == spec.Image {
+				found = true
+				break
+			}
+		}
+		if !found {
+			images = append(images, spec.Image)
+		}
+	}
+	res := helixSagaV1.HelixSagaStatus{
+		Ready: fmt.Sprintf("%d/%d", ready, len(hs.Spec.Applications)),
+		Image: strings.Join(images, ","),
+		// the conditions were maintained by the Sync
+		Conditions: hs.Status.Conditions,
+	}
+	for _, v := range hs.Spec.Applications {
+		if v.Status.Maintenance == nil {
+			continue
+		}
+		res.Maintenance = "enabled"
+		if m := hs.Spec.Maintenance; m != nil && m.Message != "" {
+			res.Maintenance = m.Message
+		}
+		break
+	}
+	return res
+}
+
+func DeleteAppResource(ks k8sCoreV1.KubernetesResource, namespace, crdName, name string, template helixSagaV1.TemplateType) error {
 	switch template {
 	case helixSagaV1.TemplateTypeDeployment:
 		return ks.Deployment().Delete(namespace, name)
 	case helixSagaV1.TemplateTypeStatefulSet:
-		return ks.StatefulSet().Delete(namespace, name)
+		if err := ks.StatefulSet().Delete(namespace, name); err != nil {
+			return err
+		}
+		return DeleteStatefulSetServices(ks, namespace, crdName, name)
 	}
 	return nil
 }
@@ -209,89 +339,146 @@ const (
 	ErrorPodsHadNotBeenClosed = "namespace:%s crdName:%s image:%s error: pods hadn't been closed completed"
 )
 
-func RetryPatchHelixSaga(ki kubernetes.Interface, clientSet helixSagaClientSet.Interface, namespace, crdName, image string, replicas map[string]int32) (map[string]int32, error) {
+// PatchTimeout is the deadline of an image update, including waiting for the pods to be closed
+var PatchTimeout = time.Minute * 10
+
+// PatchRestoreTimeout is the deadline of restoring the replicas after an image update has given up
+var PatchRestoreTimeout = time.Minute
+
+// PatchBackoff is the backoff between the retries of RetryPatchHelixSaga
+var PatchBackoff = wait.Backoff{
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    math.MaxInt32,
+	Cap:      10 * time.Second,
+}
+
+var (
+	// errPodsNotClosed was returned while the pods of the image were still terminating
+	errPodsNotClosed = fmt.Errorf("pods hadn't been closed completed")
+	// errImageNotFound was returned if no app of the HelixSaga was using the image
+	errImageNotFound = fmt.Errorf("the image was not found in the HelixSaga")
+)
+
+// isRetriable reports whether RetryPatchHelixSaga would retry after the error
+func isRetriable(err error) bool {
+	switch {
+	case err == errImageNotFound:
+		return false
+	case errors.IsNotFound(err), errors.IsForbidden(err), errors.IsInvalid(err), errors.IsBadRequest(err), errors.IsUnauthorized(err):
+		return false
+	}
+	// conflicts, terminating pods, timeouts and the other transient errors
+	return true
+}
+
+// RetryPatchHelixSaga scales the apps with the UpdateTrigger scaleToZero which were using the image.
+// With the empty replicas, the apps would be scaled down to zero and their original replicas would be returned,
+// the replicas persisted in the ImageUpdate of the apps would be preferred.
+// With the non-empty replicas, it waits for the pods to be closed, then the apps would be scaled back to the replicas
+// and their ImageUpdate would be cleared. The replicas in the ImageUpdate would also be preferred, since they might
+// have been changed by the Schedules.
+// It retries with PatchBackoff until the ctx has been done or a permanent error was returned.
+func RetryPatchHelixSaga(ctx context.Context, ki kubernetes.Interface, clientSet helixSagaClientSet.Interface, namespace, crdName, image string, replicas map[string]int32) (map[string]int32, error) {
+	return retryPatchHelixSaga(ctx, ki, clientSet, namespace, crdName, image, replicas, len(replicas) > 0)
+}
+
+func retryPatchHelixSaga(ctx context.Context, ki kubernetes.Interface, clientSet helixSagaClientSet.Interface, namespace, crdName, image string, replicas map[string]int32, waitPods bool) (map[string]int32, error) {
+	backoff := PatchBackoff
+	for attempt := 1; ; attempt++ {
+		res, err := patchHelixSaga(ctx, ki, clientSet, namespace, crdName, image, replicas, waitPods)
+		if err == nil {
+			return res, nil
+		}
+		if !isRetriable(err) {
+			klog.V(2).Info(err)
+			return nil, err
+		}
+		if err == errPodsNotClosed {
+			klog.V(4).Infof(ErrorPodsHadNotBeenClosed, namespace, crdName, image)
+		} else {
+			klog.V(2).Infof("RetryPatchHelixSaga namespace:%s crdName:%s image:%s attempt:%d err:%v", namespace, crdName, image, attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("RetryPatchHelixSaga namespace:%s crdName:%s image:%s gave up after %d attempts: %v", namespace, crdName, image, attempt, err)
+			klog.V(2).Info(err)
+			return nil, err
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+// patchHelixSaga makes a single attempt of RetryPatchHelixSaga
+func patchHelixSaga(ctx context.Context, ki kubernetes.Interface, clientSet helixSagaClientSet.Interface, namespace, crdName, image string, replicas map[string]int32, waitPods bool) (map[string]int32, error) {
 	var res = make(map[string]int32, 0)
-	var defaultConfig = wait.Backoff{
-		Steps:    10000,
-		Duration: 5 * time.Millisecond,
-		Factor:   1.0,
-		Jitter:   0.1,
+	subCtx, cancel := context.WithTimeout(ctx, time.Second*time.Duration(env.DefaultExecutionDuration))
+	defer cancel()
+	hs, err := clientSet.NevercaseV1().HelixSagas(namespace).Get(subCtx, crdName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
 	}
-	err := retry.RetryOnConflict(defaultConfig, func() error {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
-		hs, err := clientSet.NevercaseV1().HelixSagas(namespace).Get(ctx, crdName, metav1.GetOptions{})
-		cancel()
+	if waitPods {
+		pl, err := ListPodByLabels(ki, namespace, crdName, "")
 		if err != nil {
-			klog.V(2).Info(err)
-			return errors.NewConflict(schema.GroupResource{Resource: "test"}, "RetryPatchHelixSaga", err)
+			return nil, err
 		}
-		if len(replicas) > 0 {
-			if pl, err := ListPodByLabels(ki, namespace, crdName, ""); err != nil {
-				klog.V(2).Info(err)
-				return errors.NewConflict(schema.GroupResource{Resource: "test"}, "RetryPatchHelixSaga", err)
-			} else {
-				klog.Infof("namespace:%s crdName:%s image:%s pods-numbers:%d", namespace, crdName, image, len(pl.Items))
-				if len(pl.Items) > 0 {
-					policyMap := make(map[string]helixSagaV1.WatchPolicy, 0)
-					for _, v := range hs.Spec.Applications {
-						policyMap[v.Spec.Name] = v.Spec.WatchPolicy
-					}
-					for _, v := range pl.Items {
-						if len(v.Spec.Containers) > 0 {
-							if v.Spec.Containers[0].Image == image {
-								if policy, ok := policyMap[v.Spec.Containers[0].Name]; ok {
-									if policy == helixSagaV1.WatchPolicyAuto {
-										klog.Infof("check namespace:%s crdName:%s image:%s container-name:%d", namespace, crdName, image, v.Spec.Containers[0].Name)
-										err = fmt.Errorf(ErrorPodsHadNotBeenClosed, namespace, crdName, image)
-										klog.V(2).Info(err)
-										return errors.NewConflict(schema.GroupResource{Resource: "test"}, "RetryPatchHelixSaga", err)
-									}
-								}
-							}
-						}
-					}
-				}
-			}
+		klog.Infof("namespace:%s crdName:%s image:%s pods-numbers:%d", namespace, crdName, image, len(pl.Items))
+		scaled := make(map[string]bool, 0)
+		for _, v := range GetAppSpecs(hs) {
+			scaled[v.Name] = IsScaledToZero(&v)
 		}
-		exist := false
-		apps := make([]helixSagaV1.HelixSagaApp, 0)
-		for _, v := range hs.Spec.Applications {
-			if v.Spec.Image == image {
-				var a int32
-				if v.Spec.WatchPolicy == helixSagaV1.WatchPolicyAuto {
-					if t, ok := replicas[v.Spec.Name]; ok {
-						a = t
-					} else {
-						res[v.Spec.Name] = *v.Spec.Replicas
-					}
-					v.Spec.Replicas = &a
-				}
-				exist = true
-				klog.Infof("Patch change crd-name:%s image:%s specName:%s replicas:%d", crdName, image, v.Spec.Name, *v.Spec.Replicas)
+		for _, v := range pl.Items {
+			if len(v.Spec.Containers) == 0 || !sameImage(v.Spec.Containers[0].Image, image) {
+				continue
+			}
+			if scaled[v.Spec.Containers[0].Name] {
+				klog.Infof("check namespace:%s crdName:%s image:%s container-name:%s", namespace, crdName, image, v.Spec.Containers[0].Name)
+				return nil, errPodsNotClosed
 			}
-			apps = append(apps, v)
 		}
-		hs.Spec.Applications = apps
-		if !exist {
-			err = fmt.Errorf("error: the crd-name:%s image:%s was not found", crdName, image)
-			klog.V(2).Info(err)
-			defaultConfig.Steps = 0
-			return err
+	}
+	hs = hs.DeepCopy()
+	exist := false
+	for i, v := range hs.Spec.Applications {
+		spec := GetAppSpec(hs, &v.Spec)
+		if spec.Image != image {
+			continue
 		}
-		opt := metav1.UpdateOptions{}
-		ctx, cancel = context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
-		_, err = clientSet.NevercaseV1().HelixSagas(namespace).Update(ctx, hs, opt)
-		cancel()
-		if err != nil {
-			klog.V(2).Info(err)
-			return errors.NewConflict(schema.GroupResource{Resource: "test"}, "RetryPatchHelixSaga", err)
+		exist = true
+		if !IsScaledToZero(spec) {
+			continue
+		}
+		var a int32
+		update := v.Status.ImageUpdate
+		if update != nil && update.Image != image {
+			update = nil
+		}
+		if t, ok := replicas[v.Spec.Name]; ok {
+			a = t
+			// the replicas might have been changed by the Schedules while the app was scaled down
+			if update != nil {
+				a = update.Replicas
+			}
+			// the update has been finished with the replicas restored
+			hs.Spec.Applications[i].Status.ImageUpdate = nil
+		} else {
+			res[v.Spec.Name] = appReplicas(spec)
+			if update != nil {
+				// the app might have been scaled down before the operator was restarted
+				res[v.Spec.Name] = update.Replicas
+				hs.Spec.Applications[i].Status.ImageUpdate.Phase = helixSagaV1.ImageUpdatePhaseScaledDown
+			}
 		}
-		return nil
-	})
-	if errors.IsConflict(err) {
-		err = fmt.Errorf("RetryUpdateHelixSaga UpdateMaxRetries(%d) has reached. The RetryUpdateHelixSaga will retry later for owner namespace:%s crdName:%s image:%s",
-			defaultConfig.Steps, namespace, crdName, image)
-		klog.V(2).Info(err)
+		hs.Spec.Applications[i].Spec.Replicas = &a
+		klog.Infof("Patch change crd-name:%s image:%s specName:%s replicas:%d", crdName, image, v.Spec.Name, a)
+	}
+	if !exist {
+		return nil, errImageNotFound
+	}
+	if _, err = clientSet.NevercaseV1().HelixSagas(namespace).Update(subCtx, hs, metav1.UpdateOptions{}); err != nil {
+		return nil, err
 	}
-	return res, err
+	return res, nil
 }
@@ -0,0 +1,225 @@
+package helixsaga
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaFake "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/fake"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeInformers "k8s.io/client-go/informers"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRetryPatchHelixSaga(t *testing.T) {
+	backoff := PatchBackoff
+	PatchBackoff = wait.Backoff{Duration: time.Millisecond * 5, Factor: 2, Jitter: 0.1, Steps: 1000, Cap: time.Millisecond * 20}
+	defer func() {
+		PatchBackoff = backoff
+	}()
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	game, lobby := int32(3), int32(2)
+	hs := &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"},
+		Spec: helixSagaV1.HelixSagaSpec{
+			Applications: []helixSagaV1.HelixSagaApp{
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, Replicas: &game, WatchPolicy: helixSagaV1.WatchPolicyAuto}},
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "lobby", Image: image, Replicas: &lobby, WatchPolicy: helixSagaV1.WatchPolicyManual}},
+			},
+		},
+	}
+	pod := &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "game-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				k8sCoreV1.LabelApp:        OperatorKindName,
+				k8sCoreV1.LabelController: "hs",
+				k8sCoreV1.LabelName:       "game",
+			},
+		},
+		Spec: coreV1.PodSpec{
+			Containers: []coreV1.Container{{Name: "game", Image: image}},
+		},
+	}
+	ki := k8sFake.NewSimpleClientset(pod)
+	clientSet := helixSagaFake.NewSimpleClientset(hs)
+	replicas := func() map[string]int32 {
+		res := make(map[string]int32, 0)
+		t, err := clientSet.NevercaseV1().HelixSagas("default").Get(context.Background(), "hs", metaV1.GetOptions{})
+		if err != nil {
+			return res
+		}
+		for _, v := range t.Spec.Applications {
+			res[v.Spec.Name] = *v.Spec.Replicas
+		}
+		return res
+	}
+
+	// scale down
+	res, err := RetryPatchHelixSaga(context.Background(), ki, clientSet, "default", "hs", image, make(map[string]int32, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["game"] != 3 || len(res) != 1 {
+		t.Errorf("RetryPatchHelixSaga() = %v, want map[game:3]", res)
+	}
+	if got := replicas(); got["game"] != 0 || got["lobby"] != 2 {
+		t.Errorf("replicas after scaling down = %v", got)
+	}
+
+	// the pod was still terminating until the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	if _, err = RetryPatchHelixSaga(ctx, ki, clientSet, "default", "hs", image, res); err == nil {
+		t.Fatal("RetryPatchHelixSaga() error = nil, want giving up while the pod was terminating")
+	}
+	if got := replicas(); got["game"] != 0 {
+		t.Errorf("replicas after giving up = %v", got)
+	}
+
+	// scale up after the pod has been closed
+	go func() {
+		time.Sleep(time.Millisecond * 50)
+		_ = ki.CoreV1().Pods("default").Delete(context.Background(), "game-0", metaV1.DeleteOptions{})
+	}()
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel2()
+	if _, err = RetryPatchHelixSaga(ctx2, ki, clientSet, "default", "hs", image, res); err != nil {
+		t.Fatal(err)
+	}
+	if got := replicas(); got["game"] != 3 || got["lobby"] != 2 {
+		t.Errorf("replicas after scaling up = %v", got)
+	}
+
+	// a permanent error would not be retried
+	start := time.Now()
+	if _, err = RetryPatchHelixSaga(ctx2, ki, clientSet, "default", "hs", "harbor.domain.com/helix-saga/other:latest", nil); err != errImageNotFound {
+		t.Errorf("RetryPatchHelixSaga() error = %v, want %v", err, errImageNotFound)
+	}
+	if _, err = RetryPatchHelixSaga(ctx2, ki, clientSet, "default", "missing", image, nil); err == nil {
+		t.Error("RetryPatchHelixSaga() error = nil, want NotFound")
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("RetryPatchHelixSaga() retried the permanent errors for %v", time.Since(start))
+	}
+}
+
+func TestRetryPatchHelixSaga_defaultReplicas(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	// neither the app nor the Defaults set the replicas
+	hs := &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"},
+		Spec: helixSagaV1.HelixSagaSpec{
+			Defaults: &helixSagaV1.HelixSagaAppDefaults{Image: image, WatchPolicy: helixSagaV1.WatchPolicyAuto},
+			Applications: []helixSagaV1.HelixSagaApp{
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game"}},
+			},
+		},
+	}
+	res, err := RetryPatchHelixSaga(context.Background(), k8sFake.NewSimpleClientset(), helixSagaFake.NewSimpleClientset(hs), "default", "hs", image, make(map[string]int32, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["game"] != 1 || len(res) != 1 {
+		t.Errorf("RetryPatchHelixSaga() = %v, want map[game:1]", res)
+	}
+}
+
+func TestWatchersLocker(t *testing.T) {
+	ws := NewWatchers(nil)
+	if ws.Locker("default", "hs") != ws.Locker("default", "hs") {
+		t.Error("Locker() returned different locks of the same HelixSaga")
+	}
+	if ws.Locker("default", "hs") == ws.Locker("other", "hs") {
+		t.Error("Locker() returned the same lock of different HelixSagas")
+	}
+}
+
+func TestSummarizeStatus(t *testing.T) {
+	zero, two := int32(0), int32(2)
+	tests := []struct {
+		name string
+		apps []helixSagaV1.HelixSagaApp
+		want helixSagaV1.HelixSagaStatus
+	}{
+		{
+			name: "TestSummarizeStatus_empty",
+			want: helixSagaV1.HelixSagaStatus{Ready: "0/0"},
+		},
+		{
+			name: "TestSummarizeStatus_ready",
+			apps: []helixSagaV1.HelixSagaApp{
+				{
+					Spec:   helixSagaV1.HelixSagaAppSpec{Name: "game", Image: "go-all:1", Replicas: &two},
+					Status: helixSagaV1.HelixSagaAppStatus{StatefulSet: helixSagaV1.StatefulSetStatus{ReadyReplicas: 2}},
+				},
+				{
+					Spec:   helixSagaV1.HelixSagaAppSpec{Name: "lobby", Image: "go-all:1", Template: helixSagaV1.TemplateTypeDeployment},
+					Status: helixSagaV1.HelixSagaAppStatus{Deployment: helixSagaV1.DeploymentStatus{ReadyReplicas: 1}},
+				},
+				{
+					Spec: helixSagaV1.HelixSagaAppSpec{Name: "chat", Image: "go-all:1", Replicas: &zero},
+				},
+			},
+			want: helixSagaV1.HelixSagaStatus{Ready: "3/3", Image: "go-all:1"},
+		},
+		{
+			name: "TestSummarizeStatus_not_ready",
+			apps: []helixSagaV1.HelixSagaApp{
+				{
+					Spec:   helixSagaV1.HelixSagaAppSpec{Name: "game", Image: "go-all:1", Replicas: &two},
+					Status: helixSagaV1.HelixSagaAppStatus{StatefulSet: helixSagaV1.StatefulSetStatus{ReadyReplicas: 1}},
+				},
+				{
+					Spec:   helixSagaV1.HelixSagaAppSpec{Name: "lobby", Image: "go-all:2", Template: helixSagaV1.TemplateTypeDeployment},
+					Status: helixSagaV1.HelixSagaAppStatus{StatefulSet: helixSagaV1.StatefulSetStatus{ReadyReplicas: 1}},
+				},
+			},
+			want: helixSagaV1.HelixSagaStatus{Ready: "0/2", Image: "go-all:1,go-all:2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &helixSagaV1.HelixSaga{Spec: helixSagaV1.HelixSagaSpec{Applications: tt.apps}}
+			if got := SummarizeStatus(hs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SummarizeStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecreateStatefulSet(t *testing.T) {
+	one := int32(1)
+	hs := &helixSagaV1.HelixSaga{ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default", UID: "hs-uid"}}
+	spec := &helixSagaV1.HelixSagaAppSpec{Name: "game", Replicas: &one}
+	// the StatefulSet was created before the headless Service
+	old := NewStatefulSet(hs, spec)
+	old.UID = "sts-uid"
+	old.Spec.ServiceName = ""
+	client := k8sFake.NewSimpleClientset(old)
+	factory := kubeInformers.NewSharedInformerFactory(client, 0)
+	ks := k8sCoreV1.NewKubernetesResource(client, factory)
+	if err := factory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recreateStatefulSet(ks, old, NewStatefulSet(hs, spec)); err != nil {
+		t.Fatal(err)
+	}
+	sts, err := client.AppsV1().StatefulSets("default").Get(context.Background(), "game", metaV1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := GetHeadlessServiceName(spec.Name); sts.Spec.ServiceName != want {
+		t.Errorf("recreateStatefulSet() serviceName = %v, want %v", sts.Spec.ServiceName, want)
+	}
+	// the adopted pods would be rolled by the new revision of the pod template
+	if got := sts.Spec.Template.Annotations[ServiceNameAnnotation]; got != sts.Spec.ServiceName {
+		t.Errorf("recreateStatefulSet() annotation %s = %q, want %q", ServiceNameAnnotation, got, sts.Spec.ServiceName)
+	}
+}
@@ -0,0 +1,59 @@
+package helixsaga
+
+import (
+	"encoding/json"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/klog/v2"
+)
+
+// MergeDefaults returns the spec of the app which has been strategically merged into the Defaults.
+// The fields of the app take precedence, and the lists with a patchMergeKey like the env would be merged by the key.
+// The zero values of the app couldn't override the Defaults since they were omitted in the json, e.g. an empty volumePath.
+func MergeDefaults(defaults *helixSagaV1.HelixSagaAppDefaults, spec *helixSagaV1.HelixSagaAppSpec) (*helixSagaV1.HelixSagaAppSpec, error) {
+	if defaults == nil {
+		return spec.DeepCopy(), nil
+	}
+	original, err := json.Marshal(defaults)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	t, err := strategicpatch.StrategicMergePatch(original, patch, helixSagaV1.HelixSagaAppSpec{})
+	if err != nil {
+		return nil, err
+	}
+	res := &helixSagaV1.HelixSagaAppSpec{}
+	if err = json.Unmarshal(t, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetAppSpec returns the spec of the app with the Defaults of the HelixSaga, which would be read only.
+// A copy of the spec of the app would be returned if it couldn't be merged.
+// The empty Template would be StatefulSet, so dropping the Template switches a Deployment back to it.
+func GetAppSpec(hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) *helixSagaV1.HelixSagaAppSpec {
+	res, err := MergeDefaults(hs.Spec.Defaults, spec)
+	if err != nil {
+		klog.V(2).Infof("HelixSaga crdName:%s app:%s merge the defaults err:%v", hs.Name, spec.Name, err)
+		res = spec.DeepCopy()
+	}
+	if res.Template == "" {
+		res.Template = helixSagaV1.TemplateTypeStatefulSet
+	}
+	return res
+}
+
+// GetAppSpecs returns the specs of all the apps with the Defaults in the same order of the Applications
+func GetAppSpecs(hs *helixSagaV1.HelixSaga) []helixSagaV1.HelixSagaAppSpec {
+	res := make([]helixSagaV1.HelixSagaAppSpec, 0, len(hs.Spec.Applications))
+	for i := range hs.Spec.Applications {
+		res = append(res, *GetAppSpec(hs, &hs.Spec.Applications[i].Spec))
+	}
+	return res
+}
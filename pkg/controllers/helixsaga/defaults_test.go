@@ -0,0 +1,131 @@
+package helixsaga
+
+import (
+	"reflect"
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	coreV1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+func TestMergeDefaults(t *testing.T) {
+	one, two := int32(1), int32(2)
+	defaults := &helixSagaV1.HelixSagaAppDefaults{
+		Replicas:         &one,
+		Image:            "harbor.domain.com/helix-saga/go-all:latest",
+		ImagePullSecrets: []coreV1.LocalObjectReference{{Name: "harbor"}},
+		Env:              []coreV1.EnvVar{{Name: "ZONE", Value: "1"}, {Name: "LOG", Value: "info"}},
+		VolumePath:       "/mnt/ssd1",
+		ContainerPorts:   []coreV1.ContainerPort{{ContainerPort: 8080, Protocol: coreV1.ProtocolTCP}},
+		Args:             []string{"--config=/etc/config.yaml"},
+	}
+	tests := []struct {
+		name     string
+		defaults *helixSagaV1.HelixSagaAppDefaults
+		spec     *helixSagaV1.HelixSagaAppSpec
+		want     *helixSagaV1.HelixSagaAppSpec
+	}{
+		{
+			name:     "TestMergeDefaults_nil",
+			defaults: nil,
+			spec:     &helixSagaV1.HelixSagaAppSpec{Name: "game", Image: "go-all:1"},
+			want:     &helixSagaV1.HelixSagaAppSpec{Name: "game", Image: "go-all:1"},
+		},
+		{
+			name:     "TestMergeDefaults_inherit",
+			defaults: defaults,
+			spec:     &helixSagaV1.HelixSagaAppSpec{Name: "game"},
+			want: &helixSagaV1.HelixSagaAppSpec{
+				Name:             "game",
+				Replicas:         &one,
+				Image:            defaults.Image,
+				ImagePullSecrets: defaults.ImagePullSecrets,
+				Env:              defaults.Env,
+				VolumePath:       defaults.VolumePath,
+				ContainerPorts:   defaults.ContainerPorts,
+				Args:             defaults.Args,
+			},
+		},
+		{
+			name:     "TestMergeDefaults_override",
+			defaults: defaults,
+			spec: &helixSagaV1.HelixSagaAppSpec{
+				Name:           "lobby",
+				Replicas:       &two,
+				Image:          "harbor.domain.com/helix-saga/go-all:1.5.0",
+				Env:            []coreV1.EnvVar{{Name: "LOG", Value: "debug"}, {Name: "LOBBY", Value: "true"}},
+				ContainerPorts: []coreV1.ContainerPort{{ContainerPort: 9090, Protocol: coreV1.ProtocolTCP}},
+				Args:           []string{"--lobby"},
+			},
+			want: &helixSagaV1.HelixSagaAppSpec{
+				Name:             "lobby",
+				Replicas:         &two,
+				Image:            "harbor.domain.com/helix-saga/go-all:1.5.0",
+				ImagePullSecrets: defaults.ImagePullSecrets,
+				// the env and ports would be merged by their keys
+				Env: []coreV1.EnvVar{{Name: "ZONE", Value: "1"}, {Name: "LOG", Value: "debug"}, {Name: "LOBBY", Value: "true"}},
+				ContainerPorts: []coreV1.ContainerPort{
+					{ContainerPort: 9090, Protocol: coreV1.ProtocolTCP},
+					{ContainerPort: 8080, Protocol: coreV1.ProtocolTCP},
+				},
+				VolumePath: defaults.VolumePath,
+				// the lists without a patchMergeKey would be replaced
+				Args: []string{"--lobby"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeDefaults(tt.defaults, tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("MergeDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHelixSagaAppDefaults(t *testing.T) {
+	// the Defaults would be merged into the specs by the json, so they must share every other field
+	defaults := reflect.TypeOf(helixSagaV1.HelixSagaAppDefaults{})
+	spec := reflect.TypeOf(helixSagaV1.HelixSagaAppSpec{})
+	for i := 0; i < spec.NumField(); i++ {
+		f := spec.Field(i)
+		if f.Name == "Name" || f.Name == "DependsOn" {
+			if _, ok := defaults.FieldByName(f.Name); ok {
+				t.Errorf("HelixSagaAppDefaults has the field %s of the app", f.Name)
+			}
+			continue
+		}
+		got, ok := defaults.FieldByName(f.Name)
+		if !ok || got.Type != f.Type || got.Tag.Get("json") != f.Tag.Get("json") {
+			t.Errorf("HelixSagaAppDefaults field %s = %v, want %v `json:%q`", f.Name, got.Type, f.Type, f.Tag.Get("json"))
+		}
+	}
+}
+
+func TestGetAppSpecs(t *testing.T) {
+	hs := &helixSagaV1.HelixSaga{
+		Spec: helixSagaV1.HelixSagaSpec{
+			Defaults: &helixSagaV1.HelixSagaAppDefaults{Image: "go-all:1"},
+			Applications: []helixSagaV1.HelixSagaApp{
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game"}},
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "lobby", Image: "go-all:2"}},
+			},
+		},
+	}
+	specs := GetAppSpecs(hs)
+	if len(specs) != 2 || specs[0].Name != "game" || specs[0].Image != "go-all:1" || specs[1].Name != "lobby" || specs[1].Image != "go-all:2" {
+		t.Errorf("GetAppSpecs() = %+v", specs)
+	}
+	// the Defaults were never written into the apps
+	if hs.Spec.Applications[0].Spec.Image != "" {
+		t.Errorf("GetAppSpecs() modified the app: %+v", hs.Spec.Applications[0].Spec)
+	}
+	if got := SummarizeStatus(hs).Image; got != "go-all:1,go-all:2" {
+		t.Errorf("SummarizeStatus() image = %v, want go-all:1,go-all:2", got)
+	}
+}
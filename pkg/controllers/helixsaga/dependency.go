@@ -0,0 +1,189 @@
+package helixsaga
+
+import (
+	"fmt"
+	"strings"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// SortAppSpecs returns the indexes of the specs in the topological order of their DependsOn.
+// The apps which didn't depend on each other would be kept in the order of the Applications.
+// An error would be returned if an app depends on an unknown app or the DependsOn were cyclic.
+func SortAppSpecs(specs []helixSagaV1.HelixSagaAppSpec) ([]int, error) {
+	index := make(map[string]int, len(specs))
+	for i, v := range specs {
+		index[v.Name] = i
+	}
+	// pending is the number of the dependencies of the app which haven't been sorted
+	pending := make([]int, len(specs))
+	for i, v := range specs {
+		for _, name := range v.DependsOn {
+			if _, ok := index[name]; !ok {
+				return nil, fmt.Errorf("app %s depends on the unknown app %s", v.Name, name)
+			}
+			pending[i]++
+		}
+	}
+	res := make([]int, 0, len(specs))
+	sorted := make([]bool, len(specs))
+	for len(res) < len(specs) {
+		next := -1
+		for i := range specs {
+			if !sorted[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			cyclic := make([]string, 0)
+			for i, v := range specs {
+				if !sorted[i] {
+					cyclic = append(cyclic, v.Name)
+				}
+			}
+			return nil, fmt.Errorf("the DependsOn of the apps %s were cyclic", strings.Join(cyclic, ","))
+		}
+		sorted[next] = true
+		res = append(res, next)
+		for i, v := range specs {
+			for _, name := range v.DependsOn {
+				if name == specs[next].Name {
+					pending[i]--
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// SyncOrder returns the indexes of the specs in the order which they would be synced. The apps which were scaling down
+// would be synced first in the reverse topological order, then the others in the topological order.
+// The order of the Applications and the error would be returned if the DependsOn were invalid.
+func SyncOrder(ks k8sCoreV1.KubernetesResource, namespace string, specs []helixSagaV1.HelixSagaAppSpec) ([]int, error) {
+	order, err := SortAppSpecs(specs)
+	if err != nil {
+		order = make([]int, 0, len(specs))
+		for i := range specs {
+			order = append(order, i)
+		}
+		return order, err
+	}
+	down, up := make([]int, 0), make([]int, 0)
+	for _, i := range order {
+		if isScalingDown(ks, namespace, &specs[i]) {
+			down = append([]int{i}, down...)
+		} else {
+			up = append(up, i)
+		}
+	}
+	return append(down, up...), nil
+}
+
+// appReplicas returns the desired replicas of the app, defaults to 1
+func appReplicas(spec *helixSagaV1.HelixSagaAppSpec) int32 {
+	if spec.Replicas == nil {
+		return 1
+	}
+	return *spec.Replicas
+}
+
+// getWorkloadReplicas returns the replicas and the ready replicas of the Deployment or the StatefulSet of the app.
+// The replicas would be the desired ones of the workload, and the ready ones were only counted after the workload
+// has observed its latest generation. It returns false if the workload didn't exist.
+func getWorkloadReplicas(ks k8sCoreV1.KubernetesResource, namespace string, spec *helixSagaV1.HelixSagaAppSpec) (int32, int32, bool) {
+	var replicas, ready int32 = 1, 0
+	switch spec.Template {
+	case helixSagaV1.TemplateTypeDeployment:
+		dp, err := ks.Deployment().Get(namespace, spec.Name)
+		if err != nil {
+			return 0, 0, false
+		}
+		if dp.Spec.Replicas != nil {
+			replicas = *dp.Spec.Replicas
+		}
+		if dp.Status.ObservedGeneration >= dp.Generation {
+			ready = dp.Status.ReadyReplicas
+		}
+	default:
+		sts, err := ks.StatefulSet().Get(namespace, spec.Name)
+		if err != nil {
+			return 0, 0, false
+		}
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		if sts.Status.ObservedGeneration >= sts.Generation {
+			ready = sts.Status.ReadyReplicas
+		}
+	}
+	return replicas, ready, true
+}
+
+// IsAppReady reports whether the workload of the app has been scaled to the replicas of the spec and all of them were ready.
+// The app with zero replicas was always ready.
+func IsAppReady(ks k8sCoreV1.KubernetesResource, namespace string, spec *helixSagaV1.HelixSagaAppSpec) bool {
+	desired := appReplicas(spec)
+	if desired == 0 {
+		return true
+	}
+	replicas, ready, ok := getWorkloadReplicas(ks, namespace, spec)
+	return ok && replicas == desired && ready >= desired
+}
+
+// isScalingDown reports whether the workload of the app has more replicas than the spec
+func isScalingDown(ks k8sCoreV1.KubernetesResource, namespace string, spec *helixSagaV1.HelixSagaAppSpec) bool {
+	replicas, _, ok := getWorkloadReplicas(ks, namespace, spec)
+	return ok && replicas > appReplicas(spec)
+}
+
+// isScaledDown reports whether the pods of the app, including the terminating ones, were no more than the replicas of the spec
+func isScaledDown(ks k8sCoreV1.KubernetesResource, namespace, crdName string, spec *helixSagaV1.HelixSagaAppSpec) bool {
+	if isScalingDown(ks, namespace, spec) {
+		return false
+	}
+	pl, err := ListPodByLabels(ks.ClientSet(), namespace, crdName, spec.Name)
+	if err != nil {
+		klog.V(2).Info(err)
+		return false
+	}
+	return int32(len(pl.Items)) <= appReplicas(spec)
+}
+
+// GetDependencyHold returns the reason why the app would be held by the DependsOn, or an empty string if it could be synced.
+// An app which was scaling down would be held until the apps depending on it have been scaled down,
+// otherwise it would be held until the apps it depends on have been ready.
+func GetDependencyHold(ks k8sCoreV1.KubernetesResource, hs *helixSagaV1.HelixSaga, specs []helixSagaV1.HelixSagaAppSpec, i int) string {
+	spec := &specs[i]
+	if isScalingDown(ks, hs.Namespace, spec) {
+		dependents := make([]string, 0)
+		for j := range specs {
+			for _, name := range specs[j].DependsOn {
+				if name == spec.Name && !isScaledDown(ks, hs.Namespace, hs.Name, &specs[j]) {
+					dependents = append(dependents, specs[j].Name)
+				}
+			}
+		}
+		if len(dependents) > 0 {
+			return fmt.Sprintf("the apps %s depending on it haven't been scaled down", strings.Join(dependents, ","))
+		}
+		return ""
+	}
+	if appReplicas(spec) == 0 || len(spec.DependsOn) == 0 {
+		return ""
+	}
+	dependencies := make([]string, 0)
+	for _, name := range spec.DependsOn {
+		for j := range specs {
+			if specs[j].Name == name && !IsAppReady(ks, hs.Namespace, &specs[j]) {
+				dependencies = append(dependencies, name)
+			}
+		}
+	}
+	if len(dependencies) > 0 {
+		return fmt.Sprintf("the apps %s it depends on haven't been ready", strings.Join(dependencies, ","))
+	}
+	return ""
+}
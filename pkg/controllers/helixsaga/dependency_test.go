@@ -0,0 +1,176 @@
+package helixsaga
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeInformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSortAppSpecs(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []helixSagaV1.HelixSagaAppSpec
+		want    []int
+		wantErr string
+	}{
+		{
+			name: "TestSortAppSpecs_none",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "game"}, {Name: "version"},
+			},
+			want: []int{0, 1},
+		},
+		{
+			name: "TestSortAppSpecs_dependsOn",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "game", DependsOn: []string{"version", "redis-proxy"}},
+				{Name: "chat", DependsOn: []string{"game"}},
+				{Name: "version"},
+				{Name: "redis-proxy"},
+			},
+			want: []int{2, 3, 0, 1},
+		},
+		{
+			name: "TestSortAppSpecs_unknown",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "game", DependsOn: []string{"version"}},
+			},
+			wantErr: "app game depends on the unknown app version",
+		},
+		{
+			name: "TestSortAppSpecs_cyclic",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "version"},
+				{Name: "game", DependsOn: []string{"chat", "version"}},
+				{Name: "chat", DependsOn: []string{"game"}},
+			},
+			wantErr: "the DependsOn of the apps game,chat were cyclic",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SortAppSpecs(tt.specs)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("SortAppSpecs() err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortAppSpecs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newDependencyStatefulSet(name string, replicas, ready int32) *appsV1.StatefulSet {
+	return &appsV1.StatefulSet{
+		ObjectMeta: metaV1.ObjectMeta{Name: fmt.Sprintf(k8sCoreV1.StatefulSetNameTemplate, name), Namespace: "default", Generation: 1},
+		Spec:       appsV1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsV1.StatefulSetStatus{ObservedGeneration: 1, Replicas: replicas, ReadyReplicas: ready},
+	}
+}
+
+func newDependencyPod(specName string, i int) *coreV1.Pod {
+	return &coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{
+		Name:      fmt.Sprintf("%s-%d", specName, i),
+		Namespace: "default",
+		Labels: map[string]string{
+			k8sCoreV1.LabelApp:        OperatorKindName,
+			k8sCoreV1.LabelController: "hs",
+			k8sCoreV1.LabelName:       specName,
+		},
+	}}
+}
+
+func TestGetDependencyHold(t *testing.T) {
+	zero, one, two := int32(0), int32(1), int32(2)
+	hs := &helixSagaV1.HelixSaga{ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"}}
+	tests := []struct {
+		name        string
+		specs       []helixSagaV1.HelixSagaAppSpec
+		statefulSet []*appsV1.StatefulSet
+		pods        []runtime.Object
+		app         int
+		want        string
+	}{
+		{
+			name: "TestGetDependencyHold_notReady",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "version", Replicas: &two},
+				{Name: "game", Replicas: &one, DependsOn: []string{"version"}},
+			},
+			statefulSet: []*appsV1.StatefulSet{newDependencyStatefulSet("version", 2, 1)},
+			app:         1,
+			want:        "the apps version it depends on haven't been ready",
+		},
+		{
+			name: "TestGetDependencyHold_ready",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "version", Replicas: &two},
+				{Name: "game", Replicas: &one, DependsOn: []string{"version"}},
+			},
+			statefulSet: []*appsV1.StatefulSet{newDependencyStatefulSet("version", 2, 2)},
+			app:         1,
+		},
+		{
+			name: "TestGetDependencyHold_dependentRunning",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "version", Replicas: &zero},
+				{Name: "game", Replicas: &zero, DependsOn: []string{"version"}},
+			},
+			statefulSet: []*appsV1.StatefulSet{newDependencyStatefulSet("version", 1, 1), newDependencyStatefulSet("game", 0, 0)},
+			// the pod of the game was still terminating
+			pods: []runtime.Object{newDependencyPod("game", 0)},
+			app:  0,
+			want: "the apps game depending on it haven't been scaled down",
+		},
+		{
+			name: "TestGetDependencyHold_dependentScaledDown",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "version", Replicas: &zero},
+				{Name: "game", Replicas: &zero, DependsOn: []string{"version"}},
+			},
+			statefulSet: []*appsV1.StatefulSet{newDependencyStatefulSet("version", 1, 1), newDependencyStatefulSet("game", 0, 0)},
+			app:         0,
+		},
+		{
+			name: "TestGetDependencyHold_scalingDown",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "version", Replicas: &zero},
+				{Name: "game", Replicas: &zero, DependsOn: []string{"version"}},
+			},
+			statefulSet: []*appsV1.StatefulSet{newDependencyStatefulSet("version", 1, 1), newDependencyStatefulSet("game", 1, 1)},
+			// the dependent would be scaled down without waiting for the apps it depends on
+			app: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tt.pods...)
+			factory := kubeInformers.NewSharedInformerFactory(client, 0)
+			ks := k8sCoreV1.NewKubernetesResource(client, factory)
+			for _, v := range tt.statefulSet {
+				if err := factory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := GetDependencyHold(ks, hs, tt.specs, tt.app)
+			if got != tt.want {
+				t.Errorf("GetDependencyHold() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
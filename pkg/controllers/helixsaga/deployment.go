@@ -9,11 +9,11 @@ import (
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func compareDeployment(original *appsV1.Deployment, updateSpec *helixSagaV1.HelixSagaAppSpec) bool {
-	if updateSpec.Replicas != nil && *updateSpec.Replicas != *original.Spec.Replicas {
+func compareDeployment(original *appsV1.Deployment, updated *appsV1.Deployment) bool {
+	if updated.Spec.Replicas != nil && *updated.Spec.Replicas != *original.Spec.Replicas {
 		return true
 	}
-	if updateSpec.Image != original.Spec.Template.Spec.Containers[0].Image {
+	if comparePodTemplate(&original.Spec.Template, &updated.Spec.Template) {
 		return true
 	}
 	// compare Affinity
@@ -43,19 +43,20 @@ func NewDeployment(hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec
 			},
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
-					Labels: labels,
+					Labels:      GetPodTemplateLabels(labels, helixSagaV1.TemplateTypeDeployment),
+					Annotations: GetPodTemplateAnnotations(hs, spec),
 				},
 				Spec: coreV1.PodSpec{
 					Containers: []coreV1.Container{
 						{
 							Name:            k8sCoreV1.GetContainerName(spec.Name),
-							Image:           spec.Image,
+							Image:           GetAppImage(hs, spec),
 							Ports:           spec.ContainerPorts,
 							Env:             ExposePodInformationByEnvs(spec.Env),
 							Command:         spec.Command,
 							Args:            spec.Args,
 							Resources:       spec.Resources,
-							ImagePullPolicy: coreV1.PullAlways,
+							ImagePullPolicy: GetImagePullPolicy(spec),
 						},
 					},
 					ImagePullSecrets:   spec.ImagePullSecrets,
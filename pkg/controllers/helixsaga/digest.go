@@ -0,0 +1,227 @@
+package helixsaga
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaClientSet "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	harbor "github.com/nevercase/harbor-api"
+	"github.com/nevercase/k8s-controller-custom-resource/pkg/env"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// DefaultImageHistoryLimit is the number of the previous digests kept in the status if the ImageHistoryLimit was nil
+const DefaultImageHistoryLimit = 10
+
+var digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ValidateDigest checks that the digest was in the form of sha256:<64 hex>, so that the image reference could be pulled
+func ValidateDigest(digest string) error {
+	if !digestRegexp.MatchString(digest) {
+		return fmt.Errorf("the digest %q was not in the form of sha256:<64 lowercase hex>", digest)
+	}
+	return nil
+}
+
+// GetAppImage returns the image which should be deployed for the app.
+// It would be image@sha256:... if the app has been pinned to a digest, otherwise the image of the spec.
+func GetAppImage(hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) string {
+	if !IsDigestPinned(spec) && spec.RollbackTo == "" {
+		return spec.Image
+	}
+	for _, v := range hs.Spec.Applications {
+		if v.Spec.Name != spec.Name {
+			continue
+		}
+		if v.Status.CurrentImage.Digest == "" {
+			return spec.Image
+		}
+		return fmt.Sprintf("%s@%s", trimDigest(spec.Image), v.Status.CurrentImage.Digest)
+	}
+	return spec.Image
+}
+
+// trimDigest removes the @sha256:... suffix of the image
+func trimDigest(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i]
+	}
+	return image
+}
+
+// trimTag removes the :tag suffix of the image, the port of the registry domain would be kept
+func trimTag(image string) string {
+	i := strings.LastIndex(image, ":")
+	if i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+	return image
+}
+
+// sameImage reports whether the two images were the same one regardless of the pinned digests.
+// An image without a tag like domain/project/repo@sha256:... would match all the tags of the repository.
+func sameImage(a, b string) bool {
+	ta, tb := trimDigest(a), trimDigest(b)
+	if ta == tb {
+		return true
+	}
+	if trimTag(ta) != ta && trimTag(tb) != tb {
+		return false
+	}
+	return trimTag(ta) == trimTag(tb)
+}
+
+// pinImage makes the digest the current image of the app, and the previous one would be pushed into the history.
+// The spec was the one of the app with the Defaults. It returns true if the status has been changed.
+func pinImage(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec, digest string, now metav1.Time) bool {
+	current := app.Status.CurrentImage
+	if current.Image == spec.Image && current.Digest == digest {
+		return false
+	}
+	if current.Digest != "" {
+		history := make([]helixSagaV1.ImageRecord, 0, len(app.Status.ImageHistory)+1)
+		history = append(history, current)
+		for _, v := range app.Status.ImageHistory {
+			if v.Digest == digest {
+				continue
+			}
+			history = append(history, v)
+		}
+		limit := DefaultImageHistoryLimit
+		if spec.ImageHistoryLimit != nil {
+			limit = int(*spec.ImageHistoryLimit)
+		}
+		if len(history) > limit {
+			history = history[:limit]
+		}
+		app.Status.ImageHistory = history
+	}
+	app.Status.CurrentImage = helixSagaV1.ImageRecord{
+		Image:      spec.Image,
+		Digest:     digest,
+		DeployedAt: now,
+	}
+	return true
+}
+
+// PinAppImage resolves the digest which the app should be pinned to, and records it in the status of the app.
+// The RollbackTo would always be preferred, then the present digest of the same image, then the one of the registry.
+// An invalid RollbackTo would be returned as an error without changing the digest being deployed.
+func (ws *Watchers) PinAppImage(ki kubernetes.Interface, hs *helixSagaV1.HelixSaga, app *helixSagaV1.HelixSagaApp) error {
+	spec := GetAppSpec(hs, &app.Spec)
+	var digest string
+	switch {
+	case spec.RollbackTo != "":
+		if err := ValidateDigest(spec.RollbackTo); err != nil {
+			return fmt.Errorf("the rollbackTo of the app %s would be ignored: %v", spec.Name, err)
+		}
+		digest = spec.RollbackTo
+	case !IsDigestPinned(spec):
+		// the UpdateTrigger restart keeps the digest of the present image for the pod template annotations
+		if GetUpdateTrigger(spec) != helixSagaV1.UpdateTriggerRestart || app.Status.CurrentImage.Image != spec.Image {
+			app.Status.CurrentImage = helixSagaV1.ImageRecord{}
+		}
+		return nil
+	case app.Status.CurrentImage.Image == spec.Image && app.Status.CurrentImage.Digest != "":
+		return nil
+	default:
+		var err error
+		if digest, err = ws.ResolveDigest(spec.Image); err != nil {
+			klog.V(2).Info(err)
+			if digest = GetRunningDigest(ki, hs.Namespace, hs.Name, spec.Name, spec.Image); digest == "" {
+				klog.Infof("HelixSaga crdName:%s app:%s image:%s would be deployed without a digest", hs.Name, spec.Name, spec.Image)
+				return nil
+			}
+		}
+	}
+	pinImage(app, spec, digest, metav1.Now())
+	return nil
+}
+
+// ResolveDigest returns the digest of the image tag in the registry
+func (ws *Watchers) ResolveDigest(image string) (string, error) {
+	info, err := ParseImage(trimDigest(image))
+	if err != nil {
+		return "", err
+	}
+	if info.Tag == "" {
+		return "", fmt.Errorf("image:%s was without a tag to resolve", image)
+	}
+	hb, err := ws.harborHub.Get(fmt.Sprintf("%s%s", harbor.HttpPrefix, info.Domain))
+	if err != nil {
+		return "", err
+	}
+	if err = hb.Login(); err != nil {
+		return "", err
+	}
+	a, err := hb.References(info.Project, info.Repository, info.Tag)
+	if err != nil {
+		return "", err
+	}
+	if a.Digest == "" {
+		return "", fmt.Errorf("the digest of image:%s was not found in the registry", image)
+	}
+	return a.Digest, nil
+}
+
+// GetRunningDigest returns the digest of the image which the running pods of the app were using
+func GetRunningDigest(ki kubernetes.Interface, namespace, crdName, specName, image string) string {
+	pl, err := ListPodByLabels(ki, namespace, crdName, specName)
+	if err != nil {
+		klog.V(2).Info(err)
+		return ""
+	}
+	for _, v := range pl.Items {
+		if v.Status.Phase != corev1.PodRunning || len(v.Status.ContainerStatuses) == 0 {
+			continue
+		}
+		if !sameImage(v.Status.ContainerStatuses[0].Image, image) {
+			continue
+		}
+		if hash := harbor.GetHashFromDockerImageId(v.Status.ContainerStatuses[0].ImageID); hash != "" {
+			return hash
+		}
+	}
+	return ""
+}
+
+// RecordImageDigest records the new digest for the apps which were using the image with WatchPolicyAuto,
+// and the apps would be rolled by their UpdateTrigger digestPin or restart.
+// The apps which were rolled back would be kept on the RollbackTo.
+func RecordImageDigest(clientSet helixSagaClientSet.Interface, namespace, crdName, image, digest string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+		defer cancel()
+		hs, err := clientSet.NevercaseV1().HelixSagas(namespace).Get(ctx, crdName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		hs = hs.DeepCopy()
+		changed := false
+		now := metav1.Now()
+		for i, v := range GetAppSpecs(hs) {
+			if v.Image != image || v.RollbackTo != "" || v.WatchPolicy != helixSagaV1.WatchPolicyAuto {
+				continue
+			}
+			if !IsDigestPinned(&v) && GetUpdateTrigger(&v) != helixSagaV1.UpdateTriggerRestart {
+				continue
+			}
+			if pinImage(&hs.Spec.Applications[i], &v, digest, now) {
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		_, err = clientSet.NevercaseV1().HelixSagas(namespace).Update(ctx, hs, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,185 @@
+package helixsaga
+
+import (
+	"strings"
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSameImage(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{
+			name: "TestSameImage_equal",
+			a:    "harbor.domain.com/helix-saga/go-all:latest",
+			b:    "harbor.domain.com/helix-saga/go-all:latest",
+			want: true,
+		},
+		{
+			name: "TestSameImage_tag_and_digest",
+			a:    "harbor.domain.com/helix-saga/go-all:latest@sha256:aaa",
+			b:    "harbor.domain.com/helix-saga/go-all:latest",
+			want: true,
+		},
+		{
+			name: "TestSameImage_digest_only",
+			a:    "harbor.domain.com:8080/helix-saga/go-all@sha256:aaa",
+			b:    "harbor.domain.com:8080/helix-saga/go-all:latest",
+			want: true,
+		},
+		{
+			name: "TestSameImage_different_tags",
+			a:    "harbor.domain.com/helix-saga/go-all:v1",
+			b:    "harbor.domain.com/helix-saga/go-all:v2",
+			want: false,
+		},
+		{
+			name: "TestSameImage_different_repositories",
+			a:    "harbor.domain.com/helix-saga/go-all@sha256:aaa",
+			b:    "harbor.domain.com/helix-saga/go-game:latest",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameImage(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameImage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAppImage(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	tests := []struct {
+		name   string
+		spec   helixSagaV1.HelixSagaAppSpec
+		status helixSagaV1.HelixSagaAppStatus
+		want   string
+	}{
+		{
+			name:   "TestGetAppImage_not_pinned",
+			spec:   helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image},
+			status: helixSagaV1.HelixSagaAppStatus{CurrentImage: helixSagaV1.ImageRecord{Image: image, Digest: "sha256:aaa"}},
+			want:   image,
+		},
+		{
+			name: "TestGetAppImage_pinned_without_digest",
+			spec: helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, PinDigest: true},
+			want: image,
+		},
+		{
+			name:   "TestGetAppImage_pinned",
+			spec:   helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, PinDigest: true},
+			status: helixSagaV1.HelixSagaAppStatus{CurrentImage: helixSagaV1.ImageRecord{Image: image, Digest: "sha256:aaa"}},
+			want:   image + "@sha256:aaa",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &helixSagaV1.HelixSaga{
+				Spec: helixSagaV1.HelixSagaSpec{
+					Applications: []helixSagaV1.HelixSagaApp{{Spec: tt.spec, Status: tt.status}},
+				},
+			}
+			if got := GetAppImage(hs, &tt.spec); got != tt.want {
+				t.Errorf("GetAppImage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPinImage(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	limit := int32(2)
+	app := &helixSagaV1.HelixSagaApp{
+		Spec: helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, PinDigest: true, ImageHistoryLimit: &limit},
+	}
+	now := metaV1.Now()
+	for _, digest := range []string{"sha256:a", "sha256:b", "sha256:c", "sha256:d"} {
+		if !pinImage(app, &app.Spec, digest, now) {
+			t.Fatalf("pinImage(%s) = false, want true", digest)
+		}
+	}
+	if pinImage(app, &app.Spec, "sha256:d", now) {
+		t.Errorf("pinImage() with the current digest = true, want false")
+	}
+	if got := app.Status.CurrentImage.Digest; got != "sha256:d" {
+		t.Errorf("CurrentImage.Digest = %v, want sha256:d", got)
+	}
+	want := []string{"sha256:c", "sha256:b"}
+	if len(app.Status.ImageHistory) != len(want) {
+		t.Fatalf("ImageHistory = %v, want %v", app.Status.ImageHistory, want)
+	}
+	for i, v := range want {
+		if app.Status.ImageHistory[i].Digest != v {
+			t.Errorf("ImageHistory[%d].Digest = %v, want %v", i, app.Status.ImageHistory[i].Digest, v)
+		}
+	}
+	// rolling back to a digest of the history moves it out of the history
+	if !pinImage(app, &app.Spec, "sha256:b", now) {
+		t.Fatalf("pinImage(sha256:b) = false, want true")
+	}
+	want = []string{"sha256:d", "sha256:c"}
+	for i, v := range want {
+		if app.Status.ImageHistory[i].Digest != v {
+			t.Errorf("ImageHistory[%d].Digest = %v, want %v", i, app.Status.ImageHistory[i].Digest, v)
+		}
+	}
+}
+
+func TestPinAppImage_rollbackTo(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	current := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		name       string
+		rollbackTo string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "TestPinAppImage_rollbackTo_valid",
+			rollbackTo: "sha256:" + strings.Repeat("b", 64),
+			want:       "sha256:" + strings.Repeat("b", 64),
+		},
+		{
+			name:       "TestPinAppImage_rollbackTo_typo",
+			rollbackTo: "sha256:bbbb",
+			want:       current,
+			wantErr:    true,
+		},
+		{
+			name:       "TestPinAppImage_rollbackTo_uppercase",
+			rollbackTo: "SHA256:" + strings.Repeat("B", 64),
+			want:       current,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &helixSagaV1.HelixSaga{
+				Spec: helixSagaV1.HelixSagaSpec{
+					Applications: []helixSagaV1.HelixSagaApp{{
+						Spec: helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, PinDigest: true, RollbackTo: tt.rollbackTo},
+						Status: helixSagaV1.HelixSagaAppStatus{
+							CurrentImage: helixSagaV1.ImageRecord{Image: image, Digest: current},
+						},
+					}},
+				},
+			}
+			app := &hs.Spec.Applications[0]
+			if err := NewWatchers(nil).PinAppImage(nil, hs, app); (err != nil) != tt.wantErr {
+				t.Errorf("PinAppImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := app.Status.CurrentImage.Digest; got != tt.want {
+				t.Errorf("PinAppImage() digest = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,451 @@
+package helixsaga
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaClientSet "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	"github.com/nevercase/k8s-controller-custom-resource/pkg/env"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+// HookTimeout is the default duration of waiting for a hook Job
+var HookTimeout = time.Minute * 10
+
+// HookJobTTL is the duration of keeping a finished hook Job if its TTLSecondsAfterFinished was nil.
+// Every pushed digest has its own hook Job, so the finished ones would be removed by the TTL controller.
+var HookJobTTL = time.Hour * 24
+
+// HookPollInterval is the interval between the checks of a hook Job or a rollout
+var HookPollInterval = time.Second * 2
+
+// GetHookTimeout returns the Timeout of the UpdateHooks, HookTimeout would be used if it was nil
+func GetHookTimeout(spec *helixSagaV1.HelixSagaAppSpec) time.Duration {
+	if spec.UpdateHooks != nil && spec.UpdateHooks.Timeout != nil && spec.UpdateHooks.Timeout.Duration > 0 {
+		return spec.UpdateHooks.Timeout.Duration
+	}
+	return HookTimeout
+}
+
+// GetHookJobSpec returns the JobSpec of the hook, or nil if the app has no such hook
+func GetHookJobSpec(spec *helixSagaV1.HelixSagaAppSpec, hookType helixSagaV1.HookType) *batchV1.JobSpec {
+	if spec.UpdateHooks == nil {
+		return nil
+	}
+	switch hookType {
+	case helixSagaV1.HookTypePreUpdate:
+		return spec.UpdateHooks.PreUpdate
+	case helixSagaV1.HookTypePostUpdate:
+		return spec.UpdateHooks.PostUpdate
+	}
+	return nil
+}
+
+// HookJobName returns the name of the hook Job of the image.
+// The same hook of the same image would always be the same Job, so it would never be run twice.
+func HookJobName(crdName, specName string, hookType helixSagaV1.HookType, image string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(image))
+	suffix := fmt.Sprintf("%08x", h.Sum32())
+	prefix := fmt.Sprintf("%s-%s-%s", crdName, specName, strings.ToLower(strings.TrimSuffix(string(hookType), "Update")))
+	// the name was also the value of the job-name label of the pods
+	if max := 63 - len(suffix) - 1; len(prefix) > max {
+		prefix = strings.TrimRight(prefix[:max], "-.")
+	}
+	return fmt.Sprintf("%s-%s", prefix, suffix)
+}
+
+// NewHookJob returns the hook Job of the image, or nil if the app has no such hook.
+// The containers without an image would run the image, and the Job would be failed after the Timeout.
+// The finished Job would be removed after the HookJobTTL unless the TTLSecondsAfterFinished was set.
+func NewHookJob(hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec, hookType helixSagaV1.HookType, image string) *batchV1.Job {
+	t := GetHookJobSpec(spec, hookType)
+	if t == nil {
+		return nil
+	}
+	jobSpec := t.DeepCopy()
+	for i := range jobSpec.Template.Spec.Containers {
+		if jobSpec.Template.Spec.Containers[i].Image == "" {
+			jobSpec.Template.Spec.Containers[i].Image = image
+		}
+	}
+	if jobSpec.Template.Spec.RestartPolicy == "" {
+		jobSpec.Template.Spec.RestartPolicy = coreV1.RestartPolicyNever
+	}
+	if jobSpec.ActiveDeadlineSeconds == nil {
+		deadline := int64(GetHookTimeout(spec) / time.Second)
+		jobSpec.ActiveDeadlineSeconds = &deadline
+	}
+	if jobSpec.TTLSecondsAfterFinished == nil {
+		ttl := int32(HookJobTTL / time.Second)
+		jobSpec.TTLSecondsAfterFinished = &ttl
+	}
+	return &batchV1.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      HookJobName(hs.Name, spec.Name, hookType, image),
+			Namespace: hs.Namespace,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(hs, helixSagaV1.SchemeGroupVersion.WithKind(OperatorKindName)),
+			},
+			Labels: map[string]string{
+				k8sCoreV1.LabelApp:        OperatorKindName,
+				k8sCoreV1.LabelController: hs.Name,
+				k8sCoreV1.LabelName:       spec.Name,
+				HookTypeLabel:             string(hookType),
+			},
+			Annotations: map[string]string{
+				HookImageAnnotation: image,
+			},
+		},
+		Spec: *jobSpec,
+	}
+}
+
+// jobFinished returns whether the Job has been finished, and the error if it failed
+func jobFinished(job *batchV1.Job) (bool, error) {
+	for _, c := range job.Status.Conditions {
+		if c.Status != coreV1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchV1.JobComplete:
+			return true, nil
+		case batchV1.JobFailed:
+			return true, fmt.Errorf("job %s failed: %s %s", job.Name, c.Reason, c.Message)
+		}
+	}
+	return false, nil
+}
+
+// ensureHookJob returns the hook Job, it would be created if it was not found
+func ensureHookJob(ctx context.Context, ki kubernetes.Interface, hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec,
+	hookType helixSagaV1.HookType, image string, job *batchV1.Job) (*batchV1.Job, error) {
+	subCtx, cancel := context.WithTimeout(ctx, time.Second*time.Duration(env.DefaultExecutionDuration))
+	defer cancel()
+	t, err := ki.BatchV1().Jobs(job.Namespace).Get(subCtx, job.Name, metaV1.GetOptions{})
+	if errors.IsNotFound(err) {
+		klog.Infof("HelixSaga crdName:%s app:%s image:%s starts the %s hook job:%s", hs.Name, spec.Name, image, hookType, job.Name)
+		t, err = ki.BatchV1().Jobs(job.Namespace).Create(subCtx, job, metaV1.CreateOptions{})
+	}
+	return t, err
+}
+
+// finishUpdateHook records the result of the hook Job in the status of the app, and the failure would be reported by an Event
+func finishUpdateHook(clientSet helixSagaClientSet.Interface, recorder record.EventRecorder, hs *helixSagaV1.HelixSaga,
+	spec *helixSagaV1.HelixSagaAppSpec, status *helixSagaV1.HookStatus, err error) {
+	status.Phase, status.Message = helixSagaV1.HookPhaseSucceeded, ""
+	if err != nil {
+		status.Phase, status.Message = helixSagaV1.HookPhaseFailed, err.Error()
+	}
+	if e := SetHookStatus(clientSet, hs.Namespace, hs.Name, spec.Name, status); e != nil {
+		klog.V(2).Info(e)
+	}
+	if err != nil && recorder != nil {
+		recorder.Eventf(hs, coreV1.EventTypeWarning, UpdateHookFailed, MessageUpdateHookFailed, status.Type, status.JobName, spec.Name, status.Image, err)
+	}
+}
+
+// RunUpdateHook runs the hook Job of the image for the app, waits for it and records it in the status of the app.
+// It returns nil if the app has no such hook. An existing Job of the image would be waited for instead of being
+// created again, so a failed hook would only be retried after its Job has been deleted.
+func RunUpdateHook(ctx context.Context, ki kubernetes.Interface, clientSet helixSagaClientSet.Interface, recorder record.EventRecorder,
+	hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec, hookType helixSagaV1.HookType, image string) error {
+	job := NewHookJob(hs, spec, hookType, image)
+	if job == nil {
+		return nil
+	}
+	status := &helixSagaV1.HookStatus{
+		Type:    hookType,
+		JobName: job.Name,
+		Image:   image,
+		Phase:   helixSagaV1.HookPhaseRunning,
+	}
+	t, err := ensureHookJob(ctx, ki, hs, spec, hookType, image, job)
+	if err != nil {
+		return err
+	}
+	finished, err := jobFinished(t)
+	if !finished {
+		if err = SetHookStatus(clientSet, hs.Namespace, hs.Name, spec.Name, status); err != nil {
+			klog.V(2).Info(err)
+		}
+		waitCtx, waitCancel := context.WithTimeout(ctx, GetHookTimeout(spec))
+		defer waitCancel()
+		err = wait.PollImmediateUntil(HookPollInterval, func() (bool, error) {
+			subCtx, cancel := context.WithTimeout(waitCtx, time.Second*time.Duration(env.DefaultExecutionDuration))
+			defer cancel()
+			t, err := ki.BatchV1().Jobs(hs.Namespace).Get(subCtx, job.Name, metaV1.GetOptions{})
+			if err != nil {
+				if errors.IsNotFound(err) {
+					return false, err
+				}
+				klog.V(2).Info(err)
+				return false, nil
+			}
+			return jobFinished(t)
+		}, waitCtx.Done())
+		if err == wait.ErrWaitTimeout {
+			err = fmt.Errorf("job %s was not finished in %v", job.Name, GetHookTimeout(spec))
+		}
+	}
+	finishUpdateHook(clientSet, recorder, hs, spec, status, err)
+	return err
+}
+
+// SetHookStatus records the status of the hook Job in the status of the app
+func SetHookStatus(clientSet helixSagaClientSet.Interface, namespace, crdName, specName string, status *helixSagaV1.HookStatus) error {
+	return updateApplications(clientSet, namespace, crdName, func(app *helixSagaV1.HelixSagaApp, _ *helixSagaV1.HelixSagaAppSpec) bool {
+		if app.Spec.Name != specName {
+			return false
+		}
+		t := app.Status.Hook
+		if t != nil && t.Type == status.Type && t.JobName == status.JobName && t.Image == status.Image &&
+			t.Phase == status.Phase && t.Message == status.Message {
+			return false
+		}
+		app.Status.Hook = status.DeepCopy()
+		app.Status.Hook.LastTransitionTime = metaV1.Now()
+		return true
+	})
+}
+
+// hookSucceeded reports whether the hook of the image has been succeeded
+func hookSucceeded(status *helixSagaV1.HookStatus, hookType helixSagaV1.HookType, image string) bool {
+	return status != nil && status.Type == hookType && status.Image == image && status.Phase == helixSagaV1.HookPhaseSucceeded
+}
+
+// podReady reports whether the PodReady condition of the pod was true
+func podReady(pod *coreV1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == coreV1.PodReady {
+			return c.Status == coreV1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// WaitForAppRollout waits until all the pods of the app have been ready with the image and the pod template annotations
+// which were expected by the latest HelixSaga
+func WaitForAppRollout(ctx context.Context, ki kubernetes.Interface, clientSet helixSagaClientSet.Interface, namespace, crdName, specName string) error {
+	return wait.PollImmediateUntil(HookPollInterval, func() (bool, error) {
+		subCtx, cancel := context.WithTimeout(ctx, time.Second*time.Duration(env.DefaultExecutionDuration))
+		defer cancel()
+		hs, err := clientSet.NevercaseV1().HelixSagas(namespace).Get(subCtx, crdName, metaV1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return false, err
+			}
+			klog.V(2).Info(err)
+			return false, nil
+		}
+		var spec *helixSagaV1.HelixSagaAppSpec
+		for i, v := range hs.Spec.Applications {
+			if v.Spec.Name == specName {
+				spec = GetAppSpec(hs, &hs.Spec.Applications[i].Spec)
+				break
+			}
+		}
+		if spec == nil {
+			return false, fmt.Errorf("app %s was not found in HelixSaga %s/%s", specName, namespace, crdName)
+		}
+		image := GetAppImage(hs, spec)
+		digest := GetPodTemplateAnnotations(hs, spec)[ImageDigestAnnotation]
+		pl, err := ListPodByLabels(ki, namespace, crdName, specName)
+		if err != nil {
+			klog.V(2).Info(err)
+			return false, nil
+		}
+		var ready int32
+		for _, v := range pl.Items {
+			if len(v.Spec.Containers) == 0 || v.Spec.Containers[0].Image != image || v.Annotations[ImageDigestAnnotation] != digest {
+				// the pods of the previous image were still running
+				return false, nil
+			}
+			if v.DeletionTimestamp == nil && podReady(&v) {
+				ready++
+			}
+		}
+		return ready >= appReplicas(spec), nil
+	}, ctx.Done())
+}
+
+// runPostUpdateHook waits for the rollout of the app, then runs the PostUpdate hook of the image
+func runPostUpdateHook(ctx context.Context, ki kubernetes.Interface, clientSet helixSagaClientSet.Interface, recorder record.EventRecorder,
+	hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec, image string) error {
+	if GetHookJobSpec(spec, helixSagaV1.HookTypePostUpdate) == nil {
+		return nil
+	}
+	rolloutCtx, cancel := context.WithTimeout(ctx, PatchTimeout)
+	err := WaitForAppRollout(rolloutCtx, ki, clientSet, hs.Namespace, hs.Name, spec.Name)
+	cancel()
+	if err != nil {
+		if err == wait.ErrWaitTimeout {
+			err = fmt.Errorf("app %s was not rolled out to image %s in %v", spec.Name, image, PatchTimeout)
+		}
+		if recorder != nil {
+			recorder.Eventf(hs, coreV1.EventTypeWarning, UpdateHookFailed, MessageUpdateHookFailed,
+				helixSagaV1.HookTypePostUpdate, HookJobName(hs.Name, spec.Name, helixSagaV1.HookTypePostUpdate, image), spec.Name, image, err)
+		}
+		return err
+	}
+	return RunUpdateHook(ctx, ki, clientSet, recorder, hs, spec, helixSagaV1.HookTypePostUpdate, image)
+}
+
+// startUpdateHook runs the hook of the image in background, unless the same hook Job was already being run
+func (ws *Watchers) startUpdateHook(ki kubernetes.Interface, clientSet helixSagaClientSet.Interface, recorder record.EventRecorder,
+	hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec, hookType helixSagaV1.HookType, image string) {
+	name := fmt.Sprintf("%s/%s", hs.Namespace, HookJobName(hs.Name, spec.Name, hookType, image))
+	ws.mu.Lock()
+	if ws.hooks[name] {
+		ws.mu.Unlock()
+		return
+	}
+	ws.hooks[name] = true
+	ws.mu.Unlock()
+	hs, spec = hs.DeepCopy(), spec.DeepCopy()
+	go func() {
+		defer func() {
+			ws.mu.Lock()
+			delete(ws.hooks, name)
+			ws.mu.Unlock()
+		}()
+		var err error
+		if hookType == helixSagaV1.HookTypePostUpdate {
+			err = runPostUpdateHook(context.Background(), ki, clientSet, recorder, hs, spec, image)
+		} else {
+			err = RunUpdateHook(context.Background(), ki, clientSet, recorder, hs, spec, hookType, image)
+		}
+		if err != nil {
+			klog.V(2).Info(err)
+		}
+	}()
+}
+
+// GetDeployedImage returns the image of the Deployment or the StatefulSet of the app, it returns false if
+// the app has not been deployed
+func GetDeployedImage(ks k8sCoreV1.KubernetesResource, hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) (string, bool) {
+	var containers []coreV1.Container
+	switch spec.Template {
+	case helixSagaV1.TemplateTypeDeployment:
+		dp, err := ks.Deployment().Get(hs.Namespace, spec.Name)
+		if err != nil {
+			return "", false
+		}
+		containers = dp.Spec.Template.Spec.Containers
+	case helixSagaV1.TemplateTypeStatefulSet:
+		sts, err := ks.StatefulSet().Get(hs.Namespace, spec.Name)
+		if err != nil {
+			return "", false
+		}
+		containers = sts.Spec.Template.Spec.Containers
+	}
+	if len(containers) == 0 {
+		return "", false
+	}
+	return containers[0].Image, true
+}
+
+// syncUpdateHooks runs the UpdateHooks around the manual image edits of the app.
+// The spec was the one of the app with the Defaults.
+// It returns true if the rollout would be held until the PreUpdate hook of the new image has been succeeded.
+func (c *controller) syncUpdateHooks(ks k8sCoreV1.KubernetesResource, clientSet helixSagaClientSet.Interface, recorder record.EventRecorder,
+	hs *helixSagaV1.HelixSaga, app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec) bool {
+	hooks := spec.UpdateHooks
+	if hooks == nil || (hooks.PreUpdate == nil && hooks.PostUpdate == nil) {
+		return false
+	}
+	image := GetAppImage(hs, spec)
+	deployed, ok := GetDeployedImage(ks, hs, spec)
+	if !ok || deployed == image {
+		return false
+	}
+	if hooks.PreUpdate != nil && !hookSucceeded(app.Status.Hook, helixSagaV1.HookTypePreUpdate, image) {
+		klog.Infof("HelixSaga crdName:%s app:%s image:%s is held by the PreUpdate hook", hs.Name, spec.Name, image)
+		c.watchers.startUpdateHook(ks.ClientSet(), clientSet, recorder, hs, spec, helixSagaV1.HookTypePreUpdate, image)
+		return true
+	}
+	c.watchers.startUpdateHook(ks.ClientSet(), clientSet, recorder, hs, spec, helixSagaV1.HookTypePostUpdate, image)
+	return false
+}
+
+// CheckPreUpdateHooks starts the PreUpdate hooks of the new digest for the apps with WatchPolicy auto which were using the image
+// without waiting for them. It returns the reason if any of them was still running, and the error if any of them failed.
+// The update would be held until all of them have been succeeded, which would be checked again by the later syncs.
+func CheckPreUpdateHooks(ctx context.Context, wo *WatchOption, digest string) (string, error) {
+	subCtx, cancel := context.WithTimeout(ctx, time.Second*time.Duration(env.DefaultExecutionDuration))
+	hs, err := wo.HelixSagaClient.NevercaseV1().HelixSagas(wo.Namespace).Get(subCtx, wo.OperatorName, metaV1.GetOptions{})
+	cancel()
+	if err != nil {
+		return "", err
+	}
+	image := fmt.Sprintf("%s@%s", trimDigest(wo.Image), digest)
+	specs := GetAppSpecs(hs)
+	for i := range specs {
+		spec := &specs[i]
+		if spec.Image != wo.Image || spec.WatchPolicy != helixSagaV1.WatchPolicyAuto {
+			continue
+		}
+		if hookSucceeded(hs.Spec.Applications[i].Status.Hook, helixSagaV1.HookTypePreUpdate, image) {
+			continue
+		}
+		job := NewHookJob(hs, spec, helixSagaV1.HookTypePreUpdate, image)
+		if job == nil {
+			continue
+		}
+		t, err := ensureHookJob(ctx, wo.K8sClientSet, hs, spec, helixSagaV1.HookTypePreUpdate, image, job)
+		if err != nil {
+			return "", err
+		}
+		status := &helixSagaV1.HookStatus{
+			Type:    helixSagaV1.HookTypePreUpdate,
+			JobName: job.Name,
+			Image:   image,
+			Phase:   helixSagaV1.HookPhaseRunning,
+		}
+		finished, err := jobFinished(t)
+		if !finished {
+			if err = SetHookStatus(wo.HelixSagaClient, hs.Namespace, hs.Name, spec.Name, status); err != nil {
+				klog.V(2).Info(err)
+			}
+			return fmt.Sprintf("waiting for the PreUpdate hook job %s of app %s", job.Name, spec.Name), nil
+		}
+		finishUpdateHook(wo.HelixSagaClient, wo.Recorder, hs, spec, status, err)
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// startPostUpdateHooks runs the PostUpdate hooks of the new digest in background for the apps with WatchPolicy auto
+// which were using the image
+func (ws *Watchers) startPostUpdateHooks(wo *WatchOption, digest string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+	hs, err := wo.HelixSagaClient.NevercaseV1().HelixSagas(wo.Namespace).Get(ctx, wo.OperatorName, metaV1.GetOptions{})
+	cancel()
+	if err != nil {
+		return err
+	}
+	image := fmt.Sprintf("%s@%s", trimDigest(wo.Image), digest)
+	specs := GetAppSpecs(hs)
+	for i := range specs {
+		spec := &specs[i]
+		if spec.Image != wo.Image || spec.WatchPolicy != helixSagaV1.WatchPolicyAuto || GetHookJobSpec(spec, helixSagaV1.HookTypePostUpdate) == nil {
+			continue
+		}
+		ws.startUpdateHook(wo.K8sClientSet, wo.HelixSagaClient, wo.Recorder, hs, spec, helixSagaV1.HookTypePostUpdate, image)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package helixsaga
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaFake "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/fake"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHookJobName(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest@sha256:1"
+	tests := []struct {
+		name     string
+		crdName  string
+		specName string
+		hookType helixSagaV1.HookType
+		want     string
+	}{
+		{
+			name:     "TestHookJobName_pre",
+			crdName:  "hs",
+			specName: "game",
+			hookType: helixSagaV1.HookTypePreUpdate,
+			want:     "hs-game-pre-",
+		},
+		{
+			name:     "TestHookJobName_post",
+			crdName:  "hs",
+			specName: "game",
+			hookType: helixSagaV1.HookTypePostUpdate,
+			want:     "hs-game-post-",
+		},
+		{
+			name:     "TestHookJobName_truncated",
+			crdName:  strings.Repeat("h", 40),
+			specName: strings.Repeat("g", 40),
+			hookType: helixSagaV1.HookTypePreUpdate,
+			want:     strings.Repeat("h", 40) + "-" + strings.Repeat("g", 13) + "-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HookJobName(tt.crdName, tt.specName, tt.hookType, image)
+			if !strings.HasPrefix(got, tt.want) || len(got) != len(tt.want)+8 || len(got) > 63 {
+				t.Errorf("HookJobName() = %v, want %v<hash>", got, tt.want)
+			}
+			if got != HookJobName(tt.crdName, tt.specName, tt.hookType, image) {
+				t.Error("HookJobName() was not stable")
+			}
+			if got == HookJobName(tt.crdName, tt.specName, tt.hookType, image+"2") {
+				t.Error("HookJobName() returned the same name of different images")
+			}
+		})
+	}
+}
+
+func TestNewHookJob(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest@sha256:1"
+	hs := &helixSagaV1.HelixSaga{ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"}}
+	spec := &helixSagaV1.HelixSagaAppSpec{
+		Name: "game",
+		UpdateHooks: &helixSagaV1.UpdateHooks{
+			PreUpdate: &batchV1.JobSpec{
+				Template: coreV1.PodTemplateSpec{
+					Spec: coreV1.PodSpec{
+						Containers: []coreV1.Container{
+							{Name: "migrate", Command: []string{"/migrate"}},
+							{Name: "notify", Image: "busybox"},
+						},
+					},
+				},
+			},
+			Timeout: &metaV1.Duration{Duration: time.Minute},
+		},
+	}
+	if job := NewHookJob(hs, spec, helixSagaV1.HookTypePostUpdate, image); job != nil {
+		t.Errorf("NewHookJob() = %v, want nil without the PostUpdate hook", job.Name)
+	}
+	job := NewHookJob(hs, spec, helixSagaV1.HookTypePreUpdate, image)
+	if job == nil {
+		t.Fatal("NewHookJob() = nil")
+	}
+	if c := job.Spec.Template.Spec.Containers; c[0].Image != image || c[1].Image != "busybox" {
+		t.Errorf("NewHookJob() images = %v, %v", c[0].Image, c[1].Image)
+	}
+	if job.Spec.Template.Spec.RestartPolicy != coreV1.RestartPolicyNever {
+		t.Errorf("NewHookJob() restartPolicy = %v", job.Spec.Template.Spec.RestartPolicy)
+	}
+	if job.Spec.ActiveDeadlineSeconds == nil || *job.Spec.ActiveDeadlineSeconds != 60 {
+		t.Errorf("NewHookJob() activeDeadlineSeconds = %v, want 60", job.Spec.ActiveDeadlineSeconds)
+	}
+	if job.Spec.TTLSecondsAfterFinished == nil || *job.Spec.TTLSecondsAfterFinished != int32(HookJobTTL/time.Second) {
+		t.Errorf("NewHookJob() ttlSecondsAfterFinished = %v, want %v", job.Spec.TTLSecondsAfterFinished, HookJobTTL)
+	}
+	if spec.UpdateHooks.PreUpdate.Template.Spec.Containers[0].Image != "" {
+		t.Error("NewHookJob() modified the spec")
+	}
+}
+
+func TestRunUpdateHook(t *testing.T) {
+	interval := HookPollInterval
+	HookPollInterval = time.Millisecond * 10
+	defer func() {
+		HookPollInterval = interval
+	}()
+	image := "harbor.domain.com/helix-saga/go-all:latest@sha256:1"
+	hooks := &helixSagaV1.UpdateHooks{
+		PreUpdate: &batchV1.JobSpec{
+			Template: coreV1.PodTemplateSpec{
+				Spec: coreV1.PodSpec{Containers: []coreV1.Container{{Name: "migrate"}}},
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		condition batchV1.JobConditionType
+		wantPhase helixSagaV1.HookPhase
+		wantErr   bool
+	}{
+		{
+			name:      "TestRunUpdateHook_complete",
+			condition: batchV1.JobComplete,
+			wantPhase: helixSagaV1.HookPhaseSucceeded,
+		},
+		{
+			name:      "TestRunUpdateHook_failed",
+			condition: batchV1.JobFailed,
+			wantPhase: helixSagaV1.HookPhaseFailed,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &helixSagaV1.HelixSaga{
+				ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"},
+				Spec: helixSagaV1.HelixSagaSpec{
+					Applications: []helixSagaV1.HelixSagaApp{
+						{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game", Image: "harbor.domain.com/helix-saga/go-all:latest", UpdateHooks: hooks}},
+					},
+				},
+			}
+			ki := k8sFake.NewSimpleClientset()
+			clientSet := helixSagaFake.NewSimpleClientset(hs)
+			name := HookJobName("hs", "game", helixSagaV1.HookTypePreUpdate, image)
+			go func() {
+				for {
+					time.Sleep(time.Millisecond * 20)
+					job, err := ki.BatchV1().Jobs("default").Get(context.Background(), name, metaV1.GetOptions{})
+					if err != nil {
+						continue
+					}
+					job.Status.Conditions = []batchV1.JobCondition{{Type: tt.condition, Status: coreV1.ConditionTrue}}
+					_, _ = ki.BatchV1().Jobs("default").UpdateStatus(context.Background(), job, metaV1.UpdateOptions{})
+					return
+				}
+			}()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer cancel()
+			err := RunUpdateHook(ctx, ki, clientSet, nil, hs, &hs.Spec.Applications[0].Spec, helixSagaV1.HookTypePreUpdate, image)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RunUpdateHook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got, err := clientSet.NevercaseV1().HelixSagas("default").Get(context.Background(), "hs", metaV1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			status := got.Spec.Applications[0].Status.Hook
+			if status == nil || status.Phase != tt.wantPhase || status.JobName != name || status.Image != image {
+				t.Errorf("RunUpdateHook() status = %+v, want phase %v", status, tt.wantPhase)
+			}
+			// the finished Job would not be run again
+			err = RunUpdateHook(ctx, ki, clientSet, nil, hs, &hs.Spec.Applications[0].Spec, helixSagaV1.HookTypePreUpdate, image)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RunUpdateHook() of the finished job error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckPreUpdateHooks(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	digest := "sha256:" + strings.Repeat("a", 64)
+	hooks := &helixSagaV1.UpdateHooks{
+		PreUpdate: &batchV1.JobSpec{
+			Template: coreV1.PodTemplateSpec{
+				Spec: coreV1.PodSpec{Containers: []coreV1.Container{{Name: "migrate"}}},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		condition  batchV1.JobConditionType
+		wantReason bool
+		wantPhase  helixSagaV1.HookPhase
+		wantErr    bool
+	}{
+		{
+			name:       "TestCheckPreUpdateHooks_running",
+			wantReason: true,
+			wantPhase:  helixSagaV1.HookPhaseRunning,
+		},
+		{
+			name:      "TestCheckPreUpdateHooks_complete",
+			condition: batchV1.JobComplete,
+			wantPhase: helixSagaV1.HookPhaseSucceeded,
+		},
+		{
+			name:      "TestCheckPreUpdateHooks_failed",
+			condition: batchV1.JobFailed,
+			wantPhase: helixSagaV1.HookPhaseFailed,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &helixSagaV1.HelixSaga{
+				ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"},
+				Spec: helixSagaV1.HelixSagaSpec{
+					Applications: []helixSagaV1.HelixSagaApp{
+						{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, WatchPolicy: helixSagaV1.WatchPolicyAuto, UpdateHooks: hooks}},
+					},
+				},
+			}
+			ki := k8sFake.NewSimpleClientset()
+			clientSet := helixSagaFake.NewSimpleClientset(hs)
+			wo := NewWatchOption(context.Background(), ki, clientSet, hs, image)
+			defer wo.Close()
+			name := HookJobName("hs", "game", helixSagaV1.HookTypePreUpdate, image+"@"+digest)
+			// the Job would be started without being waited for
+			reason, err := CheckPreUpdateHooks(context.Background(), wo, digest)
+			if err != nil || reason == "" {
+				t.Fatalf("CheckPreUpdateHooks() = %q, %v, want the reason of the running job", reason, err)
+			}
+			if tt.condition != "" {
+				job, e := ki.BatchV1().Jobs("default").Get(context.Background(), name, metaV1.GetOptions{})
+				if e != nil {
+					t.Fatal(e)
+				}
+				job.Status.Conditions = []batchV1.JobCondition{{Type: tt.condition, Status: coreV1.ConditionTrue}}
+				if _, e = ki.BatchV1().Jobs("default").UpdateStatus(context.Background(), job, metaV1.UpdateOptions{}); e != nil {
+					t.Fatal(e)
+				}
+				reason, err = CheckPreUpdateHooks(context.Background(), wo, digest)
+			}
+			if (reason != "") != tt.wantReason || (err != nil) != tt.wantErr {
+				t.Errorf("CheckPreUpdateHooks() = %q, %v, want reason %v, wantErr %v", reason, err, tt.wantReason, tt.wantErr)
+			}
+			got, err := clientSet.NevercaseV1().HelixSagas("default").Get(context.Background(), "hs", metaV1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			status := got.Spec.Applications[0].Status.Hook
+			if status == nil || status.Phase != tt.wantPhase || status.JobName != name {
+				t.Errorf("CheckPreUpdateHooks() status = %+v, want phase %v", status, tt.wantPhase)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package helixsaga
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaClientSet "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	"github.com/Shanghai-Lunara/helixsaga-operator/pkg/imagepolicy"
+	harbor "github.com/nevercase/harbor-api"
+	"github.com/nevercase/k8s-controller-custom-resource/pkg/env"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// ImagePolicyInterval is the interval between the selections of the apps with the ImagePolicy
+var ImagePolicyInterval = time.Minute
+
+// ListTags returns the tags of the repository of the image in the registry
+func (ws *Watchers) ListTags(image string) ([]imagepolicy.Tag, error) {
+	info, err := ParseImage(trimDigest(image))
+	if err != nil {
+		return nil, err
+	}
+	hb, err := ws.harborHub.Get(fmt.Sprintf("%s%s", harbor.HttpPrefix, info.Domain))
+	if err != nil {
+		return nil, err
+	}
+	if err = hb.Login(); err != nil {
+		return nil, err
+	}
+	artifacts, err := hb.Artifacts(info.Project, info.Repository)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]imagepolicy.Tag, 0)
+	for _, a := range artifacts {
+		for _, t := range a.Tags {
+			res = append(res, imagepolicy.Tag{Name: t.Name, PushTime: t.PushTime})
+		}
+	}
+	return res, nil
+}
+
+// ReplaceImageTag returns the image with the tag
+func ReplaceImageTag(image, tag string) string {
+	return fmt.Sprintf("%s:%s", trimTag(trimDigest(image)), tag)
+}
+
+// applyImagePolicy replaces the tag of the image with the one selected from the tags, and records the selection.
+// The spec was the one of the app with the Defaults, and the selected image would be written into the app.
+// It returns true if the app has been changed.
+func applyImagePolicy(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec, tags []imagepolicy.Tag, now metav1.Time) bool {
+	status := helixSagaV1.ImagePolicyStatus{
+		Tag: app.Status.ImagePolicy.Tag,
+	}
+	image := spec.Image
+	t, reason, err := imagepolicy.Select(spec.ImagePolicy, tags)
+	if err != nil {
+		// keep the present image
+		status.Reason = err.Error()
+	} else {
+		status.Tag, status.Reason = t.Name, reason
+		image = ReplaceImageTag(spec.Image, t.Name)
+	}
+	if image == spec.Image && status.Tag == app.Status.ImagePolicy.Tag && status.Reason == app.Status.ImagePolicy.Reason {
+		return false
+	}
+	if image != spec.Image {
+		klog.Infof("ImagePolicy app:%s image:%s has been replaced by %s: %s", spec.Name, spec.Image, image, reason)
+		app.Spec.Image = image
+	}
+	status.LastTransitionTime = now
+	app.Status.ImagePolicy = status
+	return true
+}
+
+// SyncImagePolicies applies the ImagePolicy of the apps of all the HelixSagas
+func (c *controller) SyncImagePolicies() {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		klog.V(2).Info(err)
+		return
+	}
+	for _, hs := range list {
+		for _, v := range GetAppSpecs(hs) {
+			if v.ImagePolicy == nil {
+				continue
+			}
+			if err = UpdateImagePolicies(c.clientSet, c.watchers, hs.Namespace, hs.Name); err != nil {
+				klog.V(2).Info(err)
+			}
+			break
+		}
+	}
+}
+
+// UpdateImagePolicies applies the ImagePolicy of the apps of the HelixSaga, and updates the HelixSaga if it has been changed.
+// The tags of the same image would only be listed once.
+func UpdateImagePolicies(clientSet helixSagaClientSet.Interface, ws *Watchers, namespace, crdName string) error {
+	cache := make(map[string][]imagepolicy.Tag, 0)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+		defer cancel()
+		hs, err := clientSet.NevercaseV1().HelixSagas(namespace).Get(ctx, crdName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		hs = hs.DeepCopy()
+		changed := false
+		now := metav1.Now()
+		for i, v := range GetAppSpecs(hs) {
+			// the tags would be selected again after the app has been unpaused
+			if v.ImagePolicy == nil || IsPaused(hs, &v) {
+				continue
+			}
+			repository := trimTag(trimDigest(v.Image))
+			tags, ok := cache[repository]
+			if !ok {
+				if tags, err = ws.ListTags(v.Image); err != nil {
+					klog.V(2).Infof("ImagePolicy crdName:%s app:%s list tags err:%v", crdName, v.Name, err)
+					continue
+				}
+				cache[repository] = tags
+			}
+			if applyImagePolicy(&hs.Spec.Applications[i], &v, tags, now) {
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		_, err = clientSet.NevercaseV1().HelixSagas(namespace).Update(ctx, hs, metav1.UpdateOptions{})
+		return err
+	})
+}
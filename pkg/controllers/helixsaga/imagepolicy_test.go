@@ -0,0 +1,45 @@
+package helixsaga
+
+import (
+	"testing"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	"github.com/Shanghai-Lunara/helixsaga-operator/pkg/imagepolicy"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyImagePolicy(t *testing.T) {
+	now := time.Now()
+	tags := []imagepolicy.Tag{
+		{Name: "1.4.0", PushTime: now.Add(-time.Hour)},
+		{Name: "1.5.0", PushTime: now},
+	}
+	app := &helixSagaV1.HelixSagaApp{
+		Spec: helixSagaV1.HelixSagaAppSpec{
+			Name:        "game",
+			Image:       "harbor.domain.com:8080/helix-saga/go-all:1.4.0",
+			ImagePolicy: &helixSagaV1.ImagePolicy{Type: helixSagaV1.ImagePolicyTypeSemver, Range: ">=1.4.0 <2"},
+		},
+	}
+	if !applyImagePolicy(app, &app.Spec, tags, metaV1.Now()) {
+		t.Fatal("applyImagePolicy() = false, want true")
+	}
+	if want := "harbor.domain.com:8080/helix-saga/go-all:1.5.0"; app.Spec.Image != want {
+		t.Errorf("applyImagePolicy() image = %v, want %v", app.Spec.Image, want)
+	}
+	if app.Status.ImagePolicy.Tag != "1.5.0" || app.Status.ImagePolicy.Reason == "" {
+		t.Errorf("applyImagePolicy() status = %+v", app.Status.ImagePolicy)
+	}
+	if applyImagePolicy(app, &app.Spec, tags, metaV1.Now()) {
+		t.Error("applyImagePolicy() with the same tags = true, want false")
+	}
+	// the present image would be kept if no tag has been matched
+	app.Spec.ImagePolicy.Range = ">=3"
+	if !applyImagePolicy(app, &app.Spec, tags, metaV1.Now()) {
+		t.Fatal("applyImagePolicy() = false, want true")
+	}
+	if want := "harbor.domain.com:8080/helix-saga/go-all:1.5.0"; app.Spec.Image != want || app.Status.ImagePolicy.Tag != "1.5.0" {
+		t.Errorf("applyImagePolicy() image = %v status = %+v", app.Spec.Image, app.Status.ImagePolicy)
+	}
+}
@@ -0,0 +1,129 @@
+package helixsaga
+
+import (
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaClientSet "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+// SetAppReplicas changes the replicas of the app. If the app has been stopped by the Maintenance or an in-flight
+// image update, the replicas would be written into their status instead, and the app would be scaled to them after
+// it has been restored. The Maintenance takes precedence since it would keep the app stopped after the image update.
+func SetAppReplicas(app *helixSagaV1.HelixSagaApp, replicas int32) {
+	switch {
+	case app.Status.Maintenance != nil:
+		app.Status.Maintenance.Replicas = replicas
+	case app.Status.ImageUpdate != nil:
+		app.Status.ImageUpdate.Replicas = replicas
+	default:
+		app.Spec.Replicas = &replicas
+	}
+}
+
+// InMaintenance reports whether the app would be stopped by the Maintenance of the HelixSaga
+func InMaintenance(hs *helixSagaV1.HelixSaga, name string) bool {
+	m := hs.Spec.Maintenance
+	if m == nil || !m.Enabled {
+		return false
+	}
+	for _, v := range m.KeepRunning {
+		if v == name {
+			return false
+		}
+	}
+	return true
+}
+
+// stopApp scales the app to zero and records the replicas it had in the status.
+// The replicas which the in-flight image update would restore were recorded instead, and the image update
+// would leave the app stopped. It returns true if the app has been changed.
+func stopApp(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec, now time.Time) bool {
+	if app.Status.Maintenance != nil {
+		return false
+	}
+	replicas := appReplicas(spec)
+	if t := app.Status.ImageUpdate; t != nil {
+		replicas = t.Replicas
+		t.Replicas = 0
+	}
+	app.Status.Maintenance = &helixSagaV1.MaintenanceStatus{
+		Replicas:  replicas,
+		StartedAt: metav1.NewTime(now),
+	}
+	var zero int32
+	app.Spec.Replicas = &zero
+	return true
+}
+
+// restoreApp scales the app back to the replicas which were recorded by stopApp, and the record would be removed.
+// It returns true if the app has been changed.
+func restoreApp(app *helixSagaV1.HelixSagaApp) bool {
+	t := app.Status.Maintenance
+	if t == nil {
+		return false
+	}
+	app.Status.Maintenance = nil
+	SetAppReplicas(app, t.Replicas)
+	return true
+}
+
+// maintenanceEdited reports whether the replicas of the app have been edited after it was stopped by the Maintenance
+func maintenanceEdited(app *helixSagaV1.HelixSagaApp) bool {
+	return app.Status.Maintenance != nil && (app.Spec.Replicas == nil || *app.Spec.Replicas != 0)
+}
+
+// recordMaintenanceReplicas moves the replicas edited during the Maintenance into the status, so the app would be kept
+// stopped and restored to them. It returns true if the app has been changed.
+func recordMaintenanceReplicas(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec) bool {
+	if !maintenanceEdited(app) {
+		return false
+	}
+	app.Status.Maintenance.Replicas = appReplicas(spec)
+	var zero int32
+	app.Spec.Replicas = &zero
+	return true
+}
+
+// syncMaintenance stops or restores the apps of the HelixSaga by its Maintenance.
+// The replicas edited during the Maintenance would be recorded by recordMaintenanceReplicas.
+// It returns true if the HelixSaga has been updated, which would be synced again.
+func syncMaintenance(clientSet helixSagaClientSet.Interface, recorder record.EventRecorder, hs *helixSagaV1.HelixSaga) (bool, error) {
+	stopped, restored, edited := 0, 0, 0
+	for i, v := range hs.Spec.Applications {
+		switch in := InMaintenance(hs, v.Spec.Name); {
+		case in && v.Status.Maintenance == nil:
+			stopped++
+		case in && maintenanceEdited(&hs.Spec.Applications[i]):
+			edited++
+		case !in && v.Status.Maintenance != nil:
+			restored++
+		}
+	}
+	if stopped == 0 && restored == 0 && edited == 0 {
+		return false, nil
+	}
+	now := time.Now()
+	err := updateApplications(clientSet, hs.Namespace, hs.Name, func(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec) bool {
+		if InMaintenance(hs, spec.Name) {
+			return stopApp(app, spec, now) || recordMaintenanceReplicas(app, spec)
+		}
+		return restoreApp(app)
+	})
+	if err != nil {
+		klog.V(2).Info(err)
+		return false, err
+	}
+	klog.Infof("HelixSaga crdName:%s maintenance stopped:%d restored:%d edited:%d", hs.Name, stopped, restored, edited)
+	if stopped > 0 {
+		recorder.Eventf(hs, coreV1.EventTypeNormal, MaintenanceStarted, MessageMaintenanceStarted, stopped, hs.Spec.Maintenance.Message)
+	}
+	if restored > 0 {
+		recorder.Eventf(hs, coreV1.EventTypeNormal, MaintenanceFinished, MessageMaintenanceFinished, restored)
+	}
+	return true, nil
+}
@@ -0,0 +1,103 @@
+package helixsaga
+
+import (
+	"context"
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaFake "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/fake"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestSyncMaintenance(t *testing.T) {
+	one, zero := int32(1), int32(0)
+	hs := &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"},
+		Spec: helixSagaV1.HelixSagaSpec{
+			Defaults: &helixSagaV1.HelixSagaAppDefaults{Replicas: &one},
+			Maintenance: &helixSagaV1.Maintenance{
+				Enabled:     true,
+				KeepRunning: []string{"version"},
+				Message:     "weekly maintenance",
+			},
+			Applications: []helixSagaV1.HelixSagaApp{
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "version"}},
+				// the replicas were inherited from the Defaults
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game"}},
+				// the chat has been scaled down by an in-flight image update
+				{
+					Spec:   helixSagaV1.HelixSagaAppSpec{Name: "chat", Replicas: &zero},
+					Status: helixSagaV1.HelixSagaAppStatus{ImageUpdate: &helixSagaV1.ImageUpdateStatus{Replicas: 5, Phase: helixSagaV1.ImageUpdatePhaseScaledDown}},
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+	client := helixSagaFake.NewSimpleClientset(hs)
+	recorder := record.NewFakeRecorder(10)
+	get := func() *helixSagaV1.HelixSaga {
+		res, err := client.NevercaseV1().HelixSagas("default").Get(ctx, "hs", metaV1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	// start
+	if updated, err := syncMaintenance(client, recorder, hs); err != nil || !updated {
+		t.Fatalf("syncMaintenance() = %v, %v, want true", updated, err)
+	}
+	got := get()
+	version, game, chat := got.Spec.Applications[0], got.Spec.Applications[1], got.Spec.Applications[2]
+	if version.Status.Maintenance != nil || version.Spec.Replicas != nil {
+		t.Errorf("version = %+v, want kept running", version)
+	}
+	if game.Status.Maintenance == nil || game.Status.Maintenance.Replicas != 1 || *game.Spec.Replicas != 0 {
+		t.Errorf("game = %+v, want stopped with the replicas 1", game)
+	}
+	// the image update would leave the chat stopped
+	if chat.Status.Maintenance == nil || chat.Status.Maintenance.Replicas != 5 || chat.Status.ImageUpdate.Replicas != 0 {
+		t.Errorf("chat = %+v, want stopped with the replicas 5", chat)
+	}
+	if s := SummarizeStatus(got); s.Maintenance != "weekly maintenance" {
+		t.Errorf("SummarizeStatus() maintenance = %q", s.Maintenance)
+	}
+	if updated, err := syncMaintenance(client, recorder, got); err != nil || updated {
+		t.Fatalf("syncMaintenance() = %v, %v, want false after it has been started", updated, err)
+	}
+
+	// the replicas edited by the user during the maintenance would be recorded, and the app would be kept stopped
+	four := int32(4)
+	got.Spec.Applications[1].Spec.Replicas = &four
+	if got, err := client.NevercaseV1().HelixSagas("default").Update(ctx, got, metaV1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	} else if updated, err := syncMaintenance(client, recorder, got); err != nil || !updated {
+		t.Fatalf("syncMaintenance() = %v, %v, want true after the replicas have been edited", updated, err)
+	}
+	got = get()
+	if game = got.Spec.Applications[1]; game.Status.Maintenance == nil || game.Status.Maintenance.Replicas != 4 || *game.Spec.Replicas != 0 {
+		t.Errorf("game = %+v, want stopped with the edited replicas 4", game)
+	}
+
+	// a schedule during the maintenance would be applied after it
+	SetAppReplicas(&got.Spec.Applications[1], 3)
+	got.Spec.Maintenance.Enabled = false
+	if got, err := client.NevercaseV1().HelixSagas("default").Update(ctx, got, metaV1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	} else if _, err = syncMaintenance(client, recorder, got); err != nil {
+		t.Fatal(err)
+	}
+	got = get()
+	game, chat = got.Spec.Applications[1], got.Spec.Applications[2]
+	if game.Status.Maintenance != nil || *game.Spec.Replicas != 3 {
+		t.Errorf("game = %+v, want restored to the replicas 3", game)
+	}
+	// the chat would be restored by the image update
+	if chat.Status.Maintenance != nil || *chat.Spec.Replicas != 0 || chat.Status.ImageUpdate.Replicas != 5 {
+		t.Errorf("chat = %+v, want the replicas 5 in the image update", chat)
+	}
+	if s := SummarizeStatus(got); s.Maintenance != "" {
+		t.Errorf("SummarizeStatus() maintenance = %q, want empty", s.Maintenance)
+	}
+}
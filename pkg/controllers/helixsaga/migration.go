@@ -0,0 +1,116 @@
+package helixsaga
+
+import (
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaClientSet "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// otherTemplate returns the Template which an app in the template might be switched from
+func otherTemplate(template helixSagaV1.TemplateType) helixSagaV1.TemplateType {
+	if template == helixSagaV1.TemplateTypeDeployment {
+		return helixSagaV1.TemplateTypeStatefulSet
+	}
+	return helixSagaV1.TemplateTypeDeployment
+}
+
+// getAppWorkload returns the Deployment or the StatefulSet of the app in the template which was owned by the HelixSaga.
+// A nil one would be returned if it didn't exist, was being deleted or was not owned by the HelixSaga.
+func getAppWorkload(ks k8sCoreV1.KubernetesResource, hs *helixSagaV1.HelixSaga, name string, template helixSagaV1.TemplateType) (metav1.Object, error) {
+	var obj metav1.Object
+	var err error
+	switch template {
+	case helixSagaV1.TemplateTypeDeployment:
+		obj, err = ks.Deployment().Get(hs.Namespace, name)
+	case helixSagaV1.TemplateTypeStatefulSet:
+		obj, err = ks.StatefulSet().Get(hs.Namespace, name)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if obj.GetDeletionTimestamp() != nil || !metav1.IsControlledBy(obj, hs) {
+		return nil, nil
+	}
+	return obj, nil
+}
+
+// GetTemplateMigration returns the in-flight switch of the Template of the app, or nil
+func GetTemplateMigration(hs *helixSagaV1.HelixSaga, name string) *helixSagaV1.TemplateMigrationStatus {
+	for _, v := range hs.Spec.Applications {
+		if v.Spec.Name == name {
+			return v.Status.TemplateMigration
+		}
+	}
+	return nil
+}
+
+// syncTemplateMigration switches the app from the workload of the other Template without an outage. The old workload
+// would keep serving until the new one has been ready, then the Service would be moved to the new one by NewService,
+// and the old one would be deleted by the next sync. The workloads were looked up instead of the last HelixSaga,
+// so that the switch would be resumed after the operator has been restarted.
+// It returns the status of the switch which should be recorded, and true if it has been changed.
+func syncTemplateMigration(ks k8sCoreV1.KubernetesResource, hs *helixSagaV1.HelixSaga, app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec, now time.Time) (*helixSagaV1.TemplateMigrationStatus, bool, error) {
+	t := app.Status.TemplateMigration
+	if spec.Template != helixSagaV1.TemplateTypeDeployment && spec.Template != helixSagaV1.TemplateTypeStatefulSet {
+		return t, false, nil
+	}
+	from := otherTemplate(spec.Template)
+	old, err := getAppWorkload(ks, hs, spec.Name, from)
+	if err != nil {
+		return t, false, err
+	}
+	if old == nil {
+		// the switch has been finished, or the Template was switched back before the new workload was created
+		return nil, t != nil, nil
+	}
+	changed := false
+	if t == nil || t.From != from || t.To != spec.Template {
+		t = &helixSagaV1.TemplateMigrationStatus{
+			From:      from,
+			To:        spec.Template,
+			Phase:     helixSagaV1.TemplateMigrationPhaseCreating,
+			StartedAt: metav1.NewTime(now),
+		}
+		changed = true
+	} else {
+		t = t.DeepCopy()
+	}
+	switch t.Phase {
+	case helixSagaV1.TemplateMigrationPhaseSwitched:
+		// the Service has been moved to the new workload by the NewAppResources of this sync,
+		// and the status would be removed after the old one has gone from the cache
+		if err = DeleteAppResource(ks, hs.Namespace, hs.Name, spec.Name, from); err != nil && !errors.IsNotFound(err) {
+			return t, changed, err
+		}
+	default:
+		if IsAppReady(ks, hs.Namespace, spec) {
+			t.Phase = helixSagaV1.TemplateMigrationPhaseSwitched
+			changed = true
+		}
+	}
+	return t, changed, nil
+}
+
+// updateTemplateMigrations records the statuses of the switches of the Templates of the apps, a nil one would be removed
+func updateTemplateMigrations(clientSet helixSagaClientSet.Interface, hs *helixSagaV1.HelixSaga, migrations map[string]*helixSagaV1.TemplateMigrationStatus) error {
+	if len(migrations) == 0 {
+		return nil
+	}
+	return updateApplications(clientSet, hs.Namespace, hs.Name, func(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec) bool {
+		t, ok := migrations[spec.Name]
+		if !ok {
+			return false
+		}
+		app.Status.TemplateMigration = t
+		return true
+	})
+}
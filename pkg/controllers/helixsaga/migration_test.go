@@ -0,0 +1,202 @@
+package helixsaga
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeInformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSyncTemplateMigration(t *testing.T) {
+	one := int32(1)
+	now := time.Now()
+	spec := helixSagaV1.HelixSagaAppSpec{Name: "game", Replicas: &one, Template: helixSagaV1.TemplateTypeDeployment}
+	hs := &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default", UID: "hs-uid"},
+		Spec: helixSagaV1.HelixSagaSpec{
+			Applications: []helixSagaV1.HelixSagaApp{{Spec: spec}},
+		},
+	}
+	// the old StatefulSet was created before the Template has been switched
+	old := NewStatefulSet(hs, &helixSagaV1.HelixSagaAppSpec{Name: "game", Replicas: &one})
+	notReady := NewDeployment(hs, &spec)
+	ready := notReady.DeepCopy()
+	ready.Status.ReadyReplicas = 1
+	creating := &helixSagaV1.TemplateMigrationStatus{
+		From:  helixSagaV1.TemplateTypeStatefulSet,
+		To:    helixSagaV1.TemplateTypeDeployment,
+		Phase: helixSagaV1.TemplateMigrationPhaseCreating,
+	}
+	switched := creating.DeepCopy()
+	switched.Phase = helixSagaV1.TemplateMigrationPhaseSwitched
+	foreign := old.DeepCopy()
+	foreign.OwnerReferences = nil
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		status      *helixSagaV1.TemplateMigrationStatus
+		want        *helixSagaV1.TemplateMigrationPhase
+		wantChanged bool
+		wantGone    bool
+	}{
+		{
+			name:        "TestSyncTemplateMigration_started",
+			objects:     []runtime.Object{old, notReady},
+			want:        &creating.Phase,
+			wantChanged: true,
+		},
+		{
+			name:    "TestSyncTemplateMigration_not_ready",
+			objects: []runtime.Object{old, notReady},
+			status:  creating,
+			want:    &creating.Phase,
+		},
+		{
+			name:        "TestSyncTemplateMigration_ready",
+			objects:     []runtime.Object{old, ready},
+			status:      creating,
+			want:        &switched.Phase,
+			wantChanged: true,
+		},
+		{
+			name:     "TestSyncTemplateMigration_switched",
+			objects:  []runtime.Object{old, ready},
+			status:   switched,
+			want:     &switched.Phase,
+			wantGone: true,
+		},
+		{
+			name:        "TestSyncTemplateMigration_finished",
+			objects:     []runtime.Object{ready},
+			status:      switched,
+			wantChanged: true,
+			wantGone:    true,
+		},
+		{
+			name:    "TestSyncTemplateMigration_not_owned",
+			objects: []runtime.Object{foreign, notReady},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tt.objects...)
+			factory := kubeInformers.NewSharedInformerFactory(client, 0)
+			ks := k8sCoreV1.NewKubernetesResource(client, factory)
+			for _, v := range tt.objects {
+				var err error
+				switch v.(type) {
+				case *appsV1.StatefulSet:
+					err = factory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(v)
+				case *appsV1.Deployment:
+					err = factory.Apps().V1().Deployments().Informer().GetIndexer().Add(v)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			app := hs.Spec.Applications[0].DeepCopy()
+			app.Status.TemplateMigration = tt.status
+			got, changed, err := syncTemplateMigration(ks, hs, app, &spec, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("syncTemplateMigration() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("syncTemplateMigration() = %+v, want nil", got)
+			case tt.want != nil && (got == nil || got.Phase != *tt.want):
+				t.Errorf("syncTemplateMigration() = %+v, want the phase %s", got, *tt.want)
+			}
+			_, err = client.AppsV1().StatefulSets("default").Get(context.Background(), "game", metaV1.GetOptions{})
+			if gone := errors.IsNotFound(err); gone != tt.wantGone {
+				t.Errorf("the old StatefulSet gone = %v, want %v", gone, tt.wantGone)
+			}
+		})
+	}
+}
+
+func TestNewService_templateMigration(t *testing.T) {
+	spec := &helixSagaV1.HelixSagaAppSpec{
+		Name:         "game",
+		Template:     helixSagaV1.TemplateTypeDeployment,
+		ServicePorts: []coreV1.ServicePort{{Name: "http", Port: 80}},
+	}
+	hs := &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"},
+		Spec: helixSagaV1.HelixSagaSpec{
+			Applications: []helixSagaV1.HelixSagaApp{{Spec: *spec}},
+		},
+	}
+	for _, phase := range []helixSagaV1.TemplateMigrationPhase{"", helixSagaV1.TemplateMigrationPhaseCreating, helixSagaV1.TemplateMigrationPhaseSwitched} {
+		hs.Spec.Applications[0].Status.TemplateMigration = nil
+		if phase != "" {
+			hs.Spec.Applications[0].Status.TemplateMigration = &helixSagaV1.TemplateMigrationStatus{
+				From:  helixSagaV1.TemplateTypeStatefulSet,
+				To:    helixSagaV1.TemplateTypeDeployment,
+				Phase: phase,
+			}
+		}
+		svc, err := NewService(hs, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the Service would only select the new pods after the new workload has been ready
+		want := ""
+		if phase == helixSagaV1.TemplateMigrationPhaseSwitched {
+			want = string(helixSagaV1.TemplateTypeDeployment)
+		}
+		if got := svc.Spec.Selector[TemplateLabel]; got != want {
+			t.Errorf("NewService() phase:%s selector %s = %q, want %q", phase, TemplateLabel, got, want)
+		}
+		if got := svc.Spec.Selector[k8sCoreV1.LabelName]; got != spec.Name {
+			t.Errorf("NewService() phase:%s selector %s = %q", phase, k8sCoreV1.LabelName, got)
+		}
+	}
+}
+
+func TestSyncTemplateMigration_emptyTemplate(t *testing.T) {
+	one := int32(1)
+	hs := &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default", UID: "hs-uid"},
+		Spec: helixSagaV1.HelixSagaSpec{
+			// the template: Deployment has been dropped
+			Applications: []helixSagaV1.HelixSagaApp{{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game", Replicas: &one}}},
+		},
+	}
+	old := NewDeployment(hs, &helixSagaV1.HelixSagaAppSpec{Name: "game", Replicas: &one, Template: helixSagaV1.TemplateTypeDeployment})
+	client := fake.NewSimpleClientset(old)
+	factory := kubeInformers.NewSharedInformerFactory(client, 0)
+	ks := k8sCoreV1.NewKubernetesResource(client, factory)
+	if err := factory.Apps().V1().Deployments().Informer().GetIndexer().Add(old); err != nil {
+		t.Fatal(err)
+	}
+	spec := GetAppSpec(hs, &hs.Spec.Applications[0].Spec)
+	if spec.Template != helixSagaV1.TemplateTypeStatefulSet {
+		t.Fatalf("GetAppSpec() template = %q, want StatefulSet", spec.Template)
+	}
+	got, changed, err := syncTemplateMigration(ks, hs, hs.Spec.Applications[0].DeepCopy(), spec, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || got == nil || got.From != helixSagaV1.TemplateTypeDeployment || got.To != helixSagaV1.TemplateTypeStatefulSet {
+		t.Errorf("syncTemplateMigration() = %+v, %v, want the switch from Deployment to StatefulSet", got, changed)
+	}
+	actions, err := planTemplateMigration(ks, hs, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) == 0 {
+		t.Error("planTemplateMigration() = [], want the old Deployment to be deleted")
+	}
+}
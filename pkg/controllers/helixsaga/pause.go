@@ -0,0 +1,111 @@
+package helixsaga
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaClientSet "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	"github.com/nevercase/k8s-controller-custom-resource/pkg/env"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// IsPaused reports whether the reconciliation of the app has been paused by the HelixSaga or the app itself
+func IsPaused(hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) bool {
+	return hs.Spec.Paused || spec.Paused
+}
+
+// GetAppDrift reports whether the workload of the app has drifted from the one desired by the spec.
+// The workload would be returned for reporting the status, and a missing one has drifted.
+func GetAppDrift(ks k8sCoreV1.KubernetesResource, hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) (bool, interface{}, error) {
+	switch spec.Template {
+	case helixSagaV1.TemplateTypeDeployment:
+		dp, err := ks.Deployment().Get(hs.Namespace, spec.Name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return true, nil, nil
+			}
+			return false, nil, err
+		}
+		return compareDeployment(dp, NewDeployment(hs, spec)), dp, nil
+	default:
+		sts, err := ks.StatefulSet().Get(hs.Namespace, spec.Name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return true, nil, nil
+			}
+			return false, nil, err
+		}
+		return compareStatefulSet(sts, NewStatefulSet(hs, spec)), sts, nil
+	}
+}
+
+// NewPausedCondition returns the Paused condition of the HelixSaga with the names of the paused and the drifted apps.
+// It would be nil if nothing was paused and the HelixSaga has never been paused.
+func NewPausedCondition(hs *helixSagaV1.HelixSaga, paused, drifted []string) *metav1.Condition {
+	if len(paused) == 0 {
+		if meta.FindStatusCondition(hs.Status.Conditions, ConditionPaused) == nil {
+			return nil
+		}
+		return &metav1.Condition{
+			Type:    ConditionPaused,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonReconciling,
+			Message: "All the apps were reconciled",
+		}
+	}
+	res := &metav1.Condition{
+		Type:    ConditionPaused,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonAppsPaused,
+		Message: fmt.Sprintf("Apps %s were paused", strings.Join(paused, ",")),
+	}
+	if hs.Spec.Paused {
+		res.Reason = ReasonHelixSagaPaused
+		res.Message = "HelixSaga was paused"
+	}
+	if len(drifted) > 0 {
+		res.Message += fmt.Sprintf(", apps %s have drifted from the spec", strings.Join(drifted, ","))
+	}
+	return res
+}
+
+// samePausedCondition reports whether the Paused condition was the same one in the conditions.
+// The ObservedGeneration was never set since the generation would be increased by the status of the HelixSaga.
+func samePausedCondition(conditions []metav1.Condition, condition *metav1.Condition) bool {
+	if condition == nil {
+		return true
+	}
+	t := meta.FindStatusCondition(conditions, ConditionPaused)
+	return t != nil && t.Status == condition.Status && t.Reason == condition.Reason && t.Message == condition.Message
+}
+
+// updatePausedCondition sets the Paused condition in the status of the HelixSaga if it has been changed
+func updatePausedCondition(clientSet helixSagaClientSet.Interface, hs *helixSagaV1.HelixSaga, paused, drifted []string) error {
+	if samePausedCondition(hs.Status.Conditions, NewPausedCondition(hs, paused, drifted)) {
+		return nil
+	}
+	namespace, crdName := hs.Namespace, hs.Name
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+		defer cancel()
+		hs, err := clientSet.NevercaseV1().HelixSagas(namespace).Get(ctx, crdName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		condition := NewPausedCondition(hs, paused, drifted)
+		if samePausedCondition(hs.Status.Conditions, condition) {
+			return nil
+		}
+		hs = hs.DeepCopy()
+		meta.SetStatusCondition(&hs.Status.Conditions, *condition)
+		_, err = clientSet.NevercaseV1().HelixSagas(namespace).Update(ctx, hs, metav1.UpdateOptions{})
+		return err
+	})
+}
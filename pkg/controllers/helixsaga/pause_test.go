@@ -0,0 +1,101 @@
+package helixsaga
+
+import (
+	"context"
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaFake "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/fake"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeInformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetAppDrift(t *testing.T) {
+	two, three := int32(2), int32(3)
+	hs := &helixSagaV1.HelixSaga{ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"}}
+	spec := &helixSagaV1.HelixSagaAppSpec{Name: "game", Replicas: &two, Image: "harbor.domain.com/helix-saga/go-all:latest"}
+	client := fake.NewSimpleClientset()
+	factory := kubeInformers.NewSharedInformerFactory(client, 0)
+	ks := k8sCoreV1.NewKubernetesResource(client, factory)
+	if drift, obj, err := GetAppDrift(ks, hs, spec); err != nil || !drift || obj != nil {
+		t.Errorf("GetAppDrift() = %v, %v, %v, want drifted without the workload", drift, obj, err)
+	}
+	sts := NewStatefulSet(hs, spec)
+	if err := factory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(sts); err != nil {
+		t.Fatal(err)
+	}
+	if drift, obj, err := GetAppDrift(ks, hs, spec); err != nil || drift || obj == nil {
+		t.Errorf("GetAppDrift() = %v, %v, %v, want not drifted", drift, obj, err)
+	}
+	// the replicas have been edited by hand
+	hotfix := sts.DeepCopy()
+	hotfix.Spec.Replicas = &three
+	if err := factory.Apps().V1().StatefulSets().Informer().GetIndexer().Update(hotfix); err != nil {
+		t.Fatal(err)
+	}
+	if drift, _, err := GetAppDrift(ks, hs, spec); err != nil || !drift {
+		t.Errorf("GetAppDrift() = %v, %v, want drifted", drift, err)
+	}
+}
+
+func TestUpdatePausedCondition(t *testing.T) {
+	hs := &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"},
+		Spec: helixSagaV1.HelixSagaSpec{
+			Applications: []helixSagaV1.HelixSagaApp{
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game", Paused: true}},
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "lobby"}},
+			},
+		},
+	}
+	ctx := context.Background()
+	client := helixSagaFake.NewSimpleClientset(hs)
+	get := func() *helixSagaV1.HelixSaga {
+		res, err := client.NevercaseV1().HelixSagas("default").Get(ctx, "hs", metaV1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	tests := []struct {
+		name        string
+		paused      []string
+		drifted     []string
+		wantStatus  metaV1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:        "TestUpdatePausedCondition_paused",
+			paused:      []string{"game"},
+			drifted:     []string{"game"},
+			wantStatus:  metaV1.ConditionTrue,
+			wantReason:  ReasonAppsPaused,
+			wantMessage: "Apps game were paused, apps game have drifted from the spec",
+		},
+		{
+			name:        "TestUpdatePausedCondition_unpaused",
+			wantStatus:  metaV1.ConditionFalse,
+			wantReason:  ReasonReconciling,
+			wantMessage: "All the apps were reconciled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := updatePausedCondition(client, get(), tt.paused, tt.drifted); err != nil {
+				t.Fatal(err)
+			}
+			got := meta.FindStatusCondition(get().Status.Conditions, ConditionPaused)
+			if got == nil || got.Status != tt.wantStatus || got.Reason != tt.wantReason || got.Message != tt.wantMessage {
+				t.Errorf("Paused condition = %+v", got)
+			}
+		})
+	}
+	// the condition would never be added to the HelixSaga which has never been paused
+	if c := NewPausedCondition(&helixSagaV1.HelixSaga{}, nil, nil); c != nil {
+		t.Errorf("NewPausedCondition() = %+v, want nil", c)
+	}
+}
@@ -0,0 +1,512 @@
+package helixsaga
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// PlanActionType is the type of the change which the Sync would make on a resource
+type PlanActionType string
+
+const (
+	PlanActionCreate PlanActionType = "create"
+	PlanActionUpdate PlanActionType = "update"
+	PlanActionDelete PlanActionType = "delete"
+)
+
+// FieldDiff is the change of a field of the resource. The values were in JSON, and empty if the field was not set.
+type FieldDiff struct {
+	Path string `json:"path"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// PlanAction is a change which the Sync would make on a resource of the HelixSaga
+type PlanAction struct {
+	Type      PlanActionType `json:"type"`
+	Kind      string         `json:"kind"`
+	Namespace string         `json:"namespace"`
+	Name      string         `json:"name"`
+	// App is the name of the app which the resource belongs to
+	App string `json:"app"`
+	// Reason is why the resource would be deleted or recreated, e.g. the app was removed
+	Reason string      `json:"reason,omitempty"`
+	Diffs  []FieldDiff `json:"diffs,omitempty"`
+}
+
+// HelixSagaPlan is the list of the changes which the Sync would make for a proposed HelixSaga, in the order of the Sync
+type HelixSagaPlan struct {
+	Namespace string       `json:"namespace"`
+	Name      string       `json:"name"`
+	Actions   []PlanAction `json:"actions"`
+	// Notes were what would affect the actions, e.g. the paused apps and the apps held by their DependsOn
+	Notes []string `json:"notes,omitempty"`
+}
+
+// Plan returns the changes which the Sync would make on the workloads and the Services after the live HelixSaga
+// has been replaced by the proposed one, without changing anything. The live one would be nil if it didn't exist,
+// and its statuses would be kept since they were maintained by the operator.
+// The holds of the DependsOn and the switches of the Templates would be reported in the Notes with the actions
+// which would be taken after them. The PreUpdate hooks and the digests pinned from the registry were not planned.
+func Plan(ks k8sCoreV1.KubernetesResource, live, proposed *helixSagaV1.HelixSaga) (*HelixSagaPlan, error) {
+	hs := proposed.DeepCopy()
+	res := &HelixSagaPlan{
+		Namespace: hs.Namespace,
+		Name:      hs.Name,
+		Actions:   make([]PlanAction, 0),
+		Notes:     make([]string, 0),
+	}
+	if live != nil {
+		hs.UID = live.UID
+		hs.Status = live.Status
+		for i := range hs.Spec.Applications {
+			for _, v := range live.Spec.Applications {
+				if v.Spec.Name == hs.Spec.Applications[i].Spec.Name {
+					hs.Spec.Applications[i].Status = *v.Status.DeepCopy()
+				}
+			}
+		}
+		names := make(map[string]bool, len(hs.Spec.Applications))
+		for _, v := range hs.Spec.Applications {
+			names[v.Spec.Name] = true
+		}
+		for i, v := range GetAppSpecs(live) {
+			if names[v.Name] {
+				continue
+			}
+			if v.Template == "" {
+				v.Template = helixSagaV1.TemplateTypeStatefulSet
+			}
+			actions, err := planDeleteAppResource(ks, hs.Namespace, hs.Name, v.Name, v.Template, "the app was removed")
+			if err != nil {
+				return nil, err
+			}
+			res.Actions = append(res.Actions, actions...)
+			if action, err := planDeleteService(ks, hs.Namespace, v.Name, k8sCoreV1.GetServiceName(v.Name), "the app was removed"); err != nil {
+				return nil, err
+			} else if action != nil {
+				res.Actions = append(res.Actions, *action)
+			}
+			if t := live.Spec.Applications[i].Status.TemplateMigration; t != nil && t.From != v.Template {
+				actions, err := planDeleteAppResource(ks, hs.Namespace, hs.Name, v.Name, t.From, "the app was removed")
+				if err != nil {
+					return nil, err
+				}
+				res.Actions = append(res.Actions, actions...)
+			}
+		}
+	}
+	now := time.Now()
+	for i := range hs.Spec.Applications {
+		app := &hs.Spec.Applications[i]
+		spec := GetAppSpec(hs, &app.Spec)
+		if InMaintenance(hs, spec.Name) {
+			if stopApp(app, spec, now) {
+				res.Notes = append(res.Notes, fmt.Sprintf("app %s would be stopped by the Maintenance", spec.Name))
+			}
+		} else if restoreApp(app) {
+			res.Notes = append(res.Notes, fmt.Sprintf("app %s would be restored after the Maintenance", spec.Name))
+		}
+	}
+	specs := GetAppSpecs(hs)
+	for i := range hs.Spec.Applications {
+		if hs.Spec.Applications[i].Status.Maintenance != nil {
+			var zero int32
+			specs[i].Replicas = &zero
+		}
+	}
+	if err := ValidateServiceNames(specs); err != nil {
+		res.Notes = append(res.Notes, fmt.Sprintf("the HelixSaga would not be synced: %v", err))
+		return res, nil
+	}
+	order, err := SyncOrder(ks, hs.Namespace, specs)
+	sorted := err == nil
+	if !sorted {
+		res.Notes = append(res.Notes, fmt.Sprintf("the apps would be synced in the order of the Applications: %v", err))
+	}
+	for _, i := range order {
+		v := specs[i]
+		if IsPaused(hs, &v) {
+			res.Notes = append(res.Notes, fmt.Sprintf("app %s was paused, its drift would only be reported", v.Name))
+			continue
+		}
+		if sorted {
+			if reason := GetDependencyHold(ks, hs, specs, i); reason != "" {
+				res.Notes = append(res.Notes, fmt.Sprintf("app %s would be held until %s", v.Name, reason))
+			}
+		}
+		actions, err := planAppResources(ks, hs, &v)
+		if err != nil {
+			return nil, err
+		}
+		res.Actions = append(res.Actions, actions...)
+		if actions, err = planTemplateMigration(ks, hs, &v); err != nil {
+			return nil, err
+		}
+		if len(actions) > 0 {
+			res.Notes = append(res.Notes, fmt.Sprintf("app %s would be switched to %s, the old workload would be deleted after "+
+				"the new one has been ready and the Service has been moved to it", v.Name, v.Template))
+		}
+		res.Actions = append(res.Actions, actions...)
+	}
+	return res, nil
+}
+
+// planTemplateMigration returns the deletions of the old workload which syncTemplateMigration would make after the switch
+func planTemplateMigration(ks k8sCoreV1.KubernetesResource, hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) ([]PlanAction, error) {
+	if spec.Template != helixSagaV1.TemplateTypeDeployment && spec.Template != helixSagaV1.TemplateTypeStatefulSet {
+		return nil, nil
+	}
+	from := otherTemplate(spec.Template)
+	old, err := getAppWorkload(ks, hs, spec.Name, from)
+	if err != nil || old == nil {
+		return nil, err
+	}
+	reason := fmt.Sprintf("the Template was switched from %s to %s", from, spec.Template)
+	return planDeleteAppResource(ks, hs.Namespace, hs.Name, spec.Name, from, reason)
+}
+
+// planAppResources returns the changes which NewAppResources would make on the resources of the app
+func planAppResources(ks k8sCoreV1.KubernetesResource, hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) ([]PlanAction, error) {
+	res := make([]PlanAction, 0)
+	switch spec.Template {
+	case helixSagaV1.TemplateTypeDeployment:
+		dp := NewDeployment(hs, spec)
+		action := PlanAction{Kind: "Deployment", Namespace: hs.Namespace, Name: dp.Name, App: spec.Name}
+		t, err := ks.Deployment().Get(hs.Namespace, spec.Name)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			action.Type = PlanActionCreate
+			action.Diffs = diffWorkload(nil, &coreV1.PodTemplateSpec{}, dp.Spec.Replicas, &dp.Spec.Template)
+			res = append(res, action)
+		} else if compareDeployment(t, dp) {
+			action.Type = PlanActionUpdate
+			action.Diffs = diffWorkload(t.Spec.Replicas, &t.Spec.Template, dp.Spec.Replicas, &dp.Spec.Template)
+			res = append(res, action)
+		}
+	case helixSagaV1.TemplateTypeStatefulSet:
+		actions, err := planStatefulSetServices(ks, hs, spec)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, actions...)
+		sts := NewStatefulSet(hs, spec)
+		action := PlanAction{Kind: "StatefulSet", Namespace: hs.Namespace, Name: sts.Name, App: spec.Name}
+		t, err := ks.StatefulSet().Get(hs.Namespace, spec.Name)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			action.Type = PlanActionCreate
+			action.Diffs = diffWorkload(nil, &coreV1.PodTemplateSpec{}, sts.Spec.Replicas, &sts.Spec.Template)
+			res = append(res, action)
+			break
+		}
+		// the pods would be rolled again if the ServiceNameAnnotation of the recreated StatefulSet was dropped
+		if v, ok := t.Spec.Template.Annotations[ServiceNameAnnotation]; ok {
+			setPodTemplateAnnotation(&sts.Spec.Template, ServiceNameAnnotation, v)
+		}
+		if t.Spec.ServiceName != sts.Spec.ServiceName {
+			// the serviceName was immutable, the StatefulSet would be recreated without its pods by recreateStatefulSet,
+			// and the adopted pods would be rolled by the ServiceNameAnnotation
+			res = append(res, PlanAction{
+				Type:      PlanActionDelete,
+				Kind:      "StatefulSet",
+				Namespace: hs.Namespace,
+				Name:      t.Name,
+				App:       spec.Name,
+				Reason:    fmt.Sprintf("the serviceName was changed from %s to %s, the pods would be orphaned", t.Spec.ServiceName, sts.Spec.ServiceName),
+			})
+			setPodTemplateAnnotation(&sts.Spec.Template, ServiceNameAnnotation, sts.Spec.ServiceName)
+			action.Type = PlanActionCreate
+			action.Reason = fmt.Sprintf("the orphaned pods would be adopted and rolled for the serviceName %s", sts.Spec.ServiceName)
+			action.Diffs = diffField(make([]FieldDiff, 0), "spec.serviceName", t.Spec.ServiceName, sts.Spec.ServiceName)
+			action.Diffs = append(action.Diffs, diffWorkload(t.Spec.Replicas, &t.Spec.Template, sts.Spec.Replicas, &sts.Spec.Template)...)
+			res = append(res, action)
+		} else if compareStatefulSet(t, sts) {
+			action.Type = PlanActionUpdate
+			action.Diffs = diffWorkload(t.Spec.Replicas, &t.Spec.Template, sts.Spec.Replicas, &sts.Spec.Template)
+			res = append(res, action)
+		}
+	}
+	name := k8sCoreV1.GetServiceName(spec.Name)
+	if len(spec.ServicePorts) == 0 {
+		action, err := planDeleteService(ks, hs.Namespace, spec.Name, name, "the ServicePorts were empty")
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			res = append(res, *action)
+		}
+		return res, nil
+	}
+	tmpSvc, err := NewService(hs, spec)
+	if err != nil {
+		return nil, err
+	}
+	action := PlanAction{Kind: "Service", Namespace: hs.Namespace, Name: name, App: spec.Name}
+	svc, err := ks.Service().Get(hs.Namespace, name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		action.Type = PlanActionCreate
+		action.Diffs = diffService(&coreV1.Service{}, tmpSvc)
+		return append(res, action), nil
+	}
+	updated := svc.DeepCopy()
+	lbChanged, err := reconcileServiceLoadBalancer(updated, spec)
+	if err != nil {
+		return nil, err
+	}
+	if ok := compareService(updated, tmpSvc); ok || lbChanged {
+		updated.Labels = tmpSvc.Labels
+		updated.Spec.Type = tmpSvc.Spec.Type
+		updated.Spec.Ports = tmpSvc.Spec.Ports
+		updated.Spec.Selector = tmpSvc.Spec.Selector
+		action.Type = PlanActionUpdate
+		action.Diffs = diffService(svc, updated)
+		res = append(res, action)
+	}
+	return res, nil
+}
+
+// planStatefulSetServices returns the changes which SyncStatefulSetServices would make
+func planStatefulSetServices(ks k8sCoreV1.KubernetesResource, hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) ([]PlanAction, error) {
+	res := make([]PlanAction, 0)
+	svcs := []*coreV1.Service{NewHeadlessService(hs, spec)}
+	var replicas int32
+	if spec.PodService != nil && len(spec.PodService.ServicePorts) > 0 {
+		replicas = appReplicas(spec)
+	}
+	for i := int32(0); i < replicas; i++ {
+		svc, err := NewPodService(hs, spec, i)
+		if err != nil {
+			return nil, err
+		}
+		svcs = append(svcs, svc)
+	}
+	for _, tmpSvc := range svcs {
+		action, err := planService(ks, hs.Namespace, tmpSvc, spec)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			res = append(res, *action)
+		}
+	}
+	actions, err := planDeletePodServices(ks, hs.Namespace, hs.Name, spec.Name, replicas, "the ordinal was out of the replicas")
+	if err != nil {
+		return nil, err
+	}
+	return append(res, actions...), nil
+}
+
+// planService returns the change which syncService would make, or nil if the Service was up to date
+func planService(ks k8sCoreV1.KubernetesResource, namespace string, tmpSvc *coreV1.Service, spec *helixSagaV1.HelixSagaAppSpec) (*PlanAction, error) {
+	action := &PlanAction{Kind: "Service", Namespace: namespace, Name: tmpSvc.Name, App: spec.Name}
+	svc, err := ks.Service().Get(namespace, tmpSvc.Name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		action.Type = PlanActionCreate
+		action.Diffs = diffService(&coreV1.Service{}, tmpSvc)
+		return action, nil
+	}
+	updated := svc.DeepCopy()
+	lbChanged, err := reconcileServiceLoadBalancerWithType(updated, tmpSvc.Spec.Type, spec)
+	if err != nil {
+		return nil, err
+	}
+	if ok := compareService(updated, tmpSvc); !ok && !lbChanged && updated.Spec.PublishNotReadyAddresses == tmpSvc.Spec.PublishNotReadyAddresses {
+		return nil, nil
+	}
+	updated.Labels = tmpSvc.Labels
+	updated.Spec.Type = tmpSvc.Spec.Type
+	updated.Spec.Ports = tmpSvc.Spec.Ports
+	updated.Spec.Selector = tmpSvc.Spec.Selector
+	updated.Spec.PublishNotReadyAddresses = tmpSvc.Spec.PublishNotReadyAddresses
+	action.Type = PlanActionUpdate
+	action.Diffs = diffService(svc, updated)
+	return action, nil
+}
+
+// planDeleteAppResource returns the deletions which DeleteAppResource would make on the existing resources
+func planDeleteAppResource(ks k8sCoreV1.KubernetesResource, namespace, crdName, name string, template helixSagaV1.TemplateType, reason string) ([]PlanAction, error) {
+	res := make([]PlanAction, 0)
+	action := PlanAction{Type: PlanActionDelete, Namespace: namespace, App: name, Reason: reason}
+	switch template {
+	case helixSagaV1.TemplateTypeDeployment:
+		dp, err := ks.Deployment().Get(namespace, name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return res, nil
+			}
+			return nil, err
+		}
+		action.Kind, action.Name = "Deployment", dp.Name
+		return append(res, action), nil
+	case helixSagaV1.TemplateTypeStatefulSet:
+		sts, err := ks.StatefulSet().Get(namespace, name)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			action.Kind, action.Name = "StatefulSet", sts.Name
+			res = append(res, action)
+		}
+		t, err := planDeleteService(ks, namespace, name, GetHeadlessServiceName(name), reason)
+		if err != nil {
+			return nil, err
+		}
+		if t != nil {
+			res = append(res, *t)
+		}
+		actions, err := planDeletePodServices(ks, namespace, crdName, name, 0, reason)
+		if err != nil {
+			return nil, err
+		}
+		return append(res, actions...), nil
+	}
+	return res, nil
+}
+
+// planDeleteService returns the deletion of the Service, or nil if it didn't exist
+func planDeleteService(ks k8sCoreV1.KubernetesResource, namespace, app, name, reason string) (*PlanAction, error) {
+	if _, err := ks.Service().Get(namespace, name); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &PlanAction{Type: PlanActionDelete, Kind: "Service", Namespace: namespace, Name: name, App: app, Reason: reason}, nil
+}
+
+// planDeletePodServices returns the deletions which deletePodServices would make
+func planDeletePodServices(ks k8sCoreV1.KubernetesResource, namespace, crdName, name string, replicas int32, reason string) ([]PlanAction, error) {
+	res := make([]PlanAction, 0)
+	sl, err := ks.Service().List(namespace, getPodServiceLabelSelector(crdName, name))
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range sl.Items {
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(v.Name, fmt.Sprintf("%s-", k8sCoreV1.GetStatefulSetName(name))))
+		if err == nil && int32(ordinal) < replicas {
+			continue
+		}
+		res = append(res, PlanAction{Type: PlanActionDelete, Kind: "Service", Namespace: namespace, Name: v.Name, App: name, Reason: reason})
+	}
+	return res, nil
+}
+
+// diffWorkload returns the changes of the fields of the workload which were set by the operator
+func diffWorkload(oldReplicas *int32, old *coreV1.PodTemplateSpec, newReplicas *int32, new *coreV1.PodTemplateSpec) []FieldDiff {
+	res := make([]FieldDiff, 0)
+	res = diffField(res, "spec.replicas", oldReplicas, newReplicas)
+	res = diffField(res, "spec.template.metadata.annotations", old.Annotations, new.Annotations)
+	var c1, c2 coreV1.Container
+	if len(old.Spec.Containers) > 0 {
+		c1 = old.Spec.Containers[0]
+	}
+	if len(new.Spec.Containers) > 0 {
+		c2 = new.Spec.Containers[0]
+	}
+	prefix := "spec.template.spec.containers[0]"
+	res = diffField(res, prefix+".image", c1.Image, c2.Image)
+	res = diffField(res, prefix+".imagePullPolicy", c1.ImagePullPolicy, c2.ImagePullPolicy)
+	res = diffField(res, prefix+".command", c1.Command, c2.Command)
+	res = diffField(res, prefix+".args", c1.Args, c2.Args)
+	res = diffField(res, prefix+".env", c1.Env, c2.Env)
+	res = diffField(res, prefix+".resources", c1.Resources, c2.Resources)
+	res = diffField(res, "spec.template.spec.nodeSelector", old.Spec.NodeSelector, new.Spec.NodeSelector)
+	res = diffField(res, "spec.template.spec.affinity", old.Spec.Affinity, new.Spec.Affinity)
+	res = diffField(res, "spec.template.spec.tolerations", old.Spec.Tolerations, new.Spec.Tolerations)
+	res = diffField(res, "spec.template.spec.serviceAccountName", old.Spec.ServiceAccountName, new.Spec.ServiceAccountName)
+	return res
+}
+
+// diffService returns the changes of the fields of the Service which were set by the operator
+func diffService(old, new *coreV1.Service) []FieldDiff {
+	res := make([]FieldDiff, 0)
+	res = diffField(res, "metadata.labels", old.Labels, new.Labels)
+	res = diffField(res, "metadata.annotations", old.Annotations, new.Annotations)
+	res = diffField(res, "spec.type", old.Spec.Type, new.Spec.Type)
+	res = diffField(res, "spec.ports", old.Spec.Ports, new.Spec.Ports)
+	res = diffField(res, "spec.selector", old.Spec.Selector, new.Spec.Selector)
+	res = diffField(res, "spec.loadBalancerSourceRanges", old.Spec.LoadBalancerSourceRanges, new.Spec.LoadBalancerSourceRanges)
+	res = diffField(res, "spec.publishNotReadyAddresses", old.Spec.PublishNotReadyAddresses, new.Spec.PublishNotReadyAddresses)
+	return res
+}
+
+// diffField appends the change of the field if the values were different in JSON
+func diffField(diffs []FieldDiff, path string, old, new interface{}) []FieldDiff {
+	o, n := planValue(old), planValue(new)
+	if o == n {
+		return diffs
+	}
+	return append(diffs, FieldDiff{Path: path, Old: o, New: n})
+}
+
+// planValue returns the value in JSON, the empty string would be returned for the zero values
+func planValue(v interface{}) string {
+	t, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	switch s := string(t); s {
+	case "null", `""`, "{}", "[]", "false":
+		return ""
+	default:
+		return s
+	}
+}
+
+// String returns the plan in a human readable form, one action per line and the changes of the fields below them
+func (p *HelixSagaPlan) String() string {
+	count := make(map[PlanActionType]int, 0)
+	for _, v := range p.Actions {
+		count[v.Type]++
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "HelixSaga %s/%s: %d to create, %d to update, %d to delete\n", p.Namespace, p.Name,
+		count[PlanActionCreate], count[PlanActionUpdate], count[PlanActionDelete])
+	for _, v := range p.Actions {
+		sign := "~"
+		switch v.Type {
+		case PlanActionCreate:
+			sign = "+"
+		case PlanActionDelete:
+			sign = "-"
+		}
+		fmt.Fprintf(&b, "%s %s %s/%s (app %s)", sign, v.Kind, v.Namespace, v.Name, v.App)
+		if v.Reason != "" {
+			fmt.Fprintf(&b, ": %s", v.Reason)
+		}
+		b.WriteString("\n")
+		for _, d := range v.Diffs {
+			fmt.Fprintf(&b, "    %s: %s => %s\n", d.Path, planOrNone(d.Old), planOrNone(d.New))
+		}
+	}
+	for _, v := range p.Notes {
+		fmt.Fprintf(&b, "note: %s\n", v)
+	}
+	return b.String()
+}
+
+func planOrNone(v string) string {
+	if v == "" {
+		return "<none>"
+	}
+	return v
+}
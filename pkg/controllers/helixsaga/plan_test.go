@@ -0,0 +1,163 @@
+package helixsaga
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeInformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPlan(t *testing.T) {
+	one, two := int32(1), int32(2)
+	ports := []coreV1.ServicePort{{Name: "http", Port: 80}}
+	game := helixSagaV1.HelixSagaAppSpec{Name: "game", Replicas: &one, Image: "harbor.domain.com/helix-saga/go-all:1.0.0", Template: helixSagaV1.TemplateTypeStatefulSet}
+	lobby := helixSagaV1.HelixSagaAppSpec{Name: "lobby", Replicas: &one, Image: "harbor.domain.com/helix-saga/go-all:1.0.0", Template: helixSagaV1.TemplateTypeDeployment, ServicePorts: ports}
+	live := &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"},
+		Spec: helixSagaV1.HelixSagaSpec{
+			Applications: []helixSagaV1.HelixSagaApp{{Spec: game}, {Spec: lobby}},
+		},
+	}
+	newResource := func(liveStatefulSet func(sts *appsV1.StatefulSet)) k8sCoreV1.KubernetesResource {
+		client := fake.NewSimpleClientset()
+		factory := kubeInformers.NewSharedInformerFactory(client, 0)
+		ks := k8sCoreV1.NewKubernetesResource(client, factory)
+		sts := NewStatefulSet(live, &game)
+		if liveStatefulSet != nil {
+			liveStatefulSet(sts)
+		}
+		objects := []interface{}{
+			sts, NewHeadlessService(live, &game),
+			NewDeployment(live, &lobby),
+		}
+		svc, err := NewService(live, &lobby)
+		if err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, svc)
+		for _, v := range objects {
+			var err error
+			switch v.(type) {
+			case *coreV1.Service:
+				err = factory.Core().V1().Services().Informer().GetIndexer().Add(v)
+			case *appsV1.StatefulSet:
+				err = factory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(v)
+			case *appsV1.Deployment:
+				err = factory.Apps().V1().Deployments().Informer().GetIndexer().Add(v)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return ks
+	}
+	tests := []struct {
+		name            string
+		live            *helixSagaV1.HelixSaga
+		liveStatefulSet func(sts *appsV1.StatefulSet)
+		proposed        func(hs *helixSagaV1.HelixSaga)
+		want            []string
+	}{
+		{
+			name:     "TestPlan_unchanged",
+			live:     live,
+			proposed: func(hs *helixSagaV1.HelixSaga) {},
+			want:     []string{},
+		},
+		{
+			name: "TestPlan_scaled",
+			live: live,
+			proposed: func(hs *helixSagaV1.HelixSaga) {
+				hs.Spec.Applications[0].Spec.Replicas = &two
+			},
+			want: []string{"update StatefulSet game spec.replicas: 1 => 2"},
+		},
+		{
+			name: "TestPlan_template_switched",
+			live: live,
+			proposed: func(hs *helixSagaV1.HelixSaga) {
+				hs.Spec.Applications[0].Spec.Template = helixSagaV1.TemplateTypeDeployment
+			},
+			// the old StatefulSet would be deleted after the Deployment has been ready
+			want: []string{"create Deployment game", "delete StatefulSet game", "delete Service game-headless"},
+		},
+		{
+			name: "TestPlan_app_removed",
+			live: live,
+			proposed: func(hs *helixSagaV1.HelixSaga) {
+				hs.Spec.Applications = hs.Spec.Applications[:1]
+			},
+			want: []string{"delete Deployment lobby", "delete Service lobby"},
+		},
+		{
+			name: "TestPlan_service_ports_removed",
+			live: live,
+			proposed: func(hs *helixSagaV1.HelixSaga) {
+				hs.Spec.Applications[1].Spec.ServicePorts = nil
+			},
+			want: []string{"delete Service lobby"},
+		},
+		{
+			name: "TestPlan_service_name_changed",
+			live: live,
+			liveStatefulSet: func(sts *appsV1.StatefulSet) {
+				sts.Spec.ServiceName = ""
+			},
+			proposed: func(hs *helixSagaV1.HelixSaga) {},
+			want: []string{
+				"delete StatefulSet game",
+				"create StatefulSet game spec.serviceName:  => \"game-headless\"" +
+					" spec.template.metadata.annotations:  => {\"helixsaga.nevercase.io/service-name\":\"game-headless\"}",
+			},
+		},
+		{
+			name: "TestPlan_service_name_recreated",
+			live: live,
+			liveStatefulSet: func(sts *appsV1.StatefulSet) {
+				setPodTemplateAnnotation(&sts.Spec.Template, ServiceNameAnnotation, sts.Spec.ServiceName)
+			},
+			proposed: func(hs *helixSagaV1.HelixSaga) {},
+			want:     []string{},
+		},
+		{
+			name: "TestPlan_paused",
+			live: live,
+			proposed: func(hs *helixSagaV1.HelixSaga) {
+				hs.Spec.Paused = true
+				hs.Spec.Applications[0].Spec.Replicas = &two
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposed := tt.live.DeepCopy()
+			tt.proposed(proposed)
+			p, err := Plan(newResource(tt.liveStatefulSet), tt.live, proposed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0)
+			for _, v := range p.Actions {
+				s := fmt.Sprintf("%s %s %s", v.Type, v.Kind, v.Name)
+				// the diffs of a recreated resource were against the live one
+				if v.Type == PlanActionUpdate || (v.Type == PlanActionCreate && v.Reason != "") {
+					for _, d := range v.Diffs {
+						s += fmt.Sprintf(" %s: %s => %s", d.Path, d.Old, d.New)
+					}
+				}
+				got = append(got, s)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan() = %v, want %v\n%s", got, tt.want, p)
+			}
+		})
+	}
+}
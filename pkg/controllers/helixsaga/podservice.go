@@ -0,0 +1,332 @@
+package helixsaga
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	"github.com/nevercase/k8s-controller-custom-resource/pkg/env"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/klog/v2"
+)
+
+// GetHeadlessServiceName returns the name of the headless Service which governs the StatefulSet
+func GetHeadlessServiceName(name string) string {
+	return fmt.Sprintf(HeadlessServiceNameTemplate, k8sCoreV1.GetStatefulSetName(name))
+}
+
+// GetPodServiceName returns the name of the Service of the pod in the ordinal, which was the same as the pod name
+func GetPodServiceName(name string, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", k8sCoreV1.GetStatefulSetName(name), ordinal)
+}
+
+// ValidateServiceNames checks that the headless Service and the pod Services of every StatefulSet app were not named
+// after another app, e.g. the pod Service game-1 of the app game and the Service of the app game-1,
+// since they would be overwritten by each other.
+func ValidateServiceNames(specs []helixSagaV1.HelixSagaAppSpec) error {
+	names := make(map[string]bool, len(specs))
+	for _, v := range specs {
+		names[k8sCoreV1.GetServiceName(v.Name)] = true
+	}
+	for _, v := range specs {
+		if v.Template == helixSagaV1.TemplateTypeDeployment {
+			continue
+		}
+		if t := GetHeadlessServiceName(v.Name); names[t] {
+			return fmt.Errorf("the headless Service of the app %s conflicted with the app %s", v.Name, t)
+		}
+		if v.PodService == nil || len(v.PodService.ServicePorts) == 0 {
+			continue
+		}
+		prefix := fmt.Sprintf("%s-", k8sCoreV1.GetStatefulSetName(v.Name))
+		for _, t := range specs {
+			name := k8sCoreV1.GetServiceName(t.Name)
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if _, err := strconv.ParseUint(strings.TrimPrefix(name, prefix), 10, 32); err == nil {
+				return fmt.Errorf("the pod Services of the app %s conflicted with the app %s", v.Name, t.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// NewHeadlessService returns the headless Service which gives every pod of the StatefulSet a stable DNS name
+// in the form of <pod-name>.<service-name>.<namespace>.svc.
+// The not ready pods would be published as well, so that the shards could address each other while starting.
+func NewHeadlessService(hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) *coreV1.Service {
+	labels := map[string]string{
+		k8sCoreV1.LabelApp:        OperatorKindName,
+		k8sCoreV1.LabelController: hs.Name,
+		k8sCoreV1.LabelName:       spec.Name,
+	}
+	return &coreV1.Service{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      GetHeadlessServiceName(spec.Name),
+			Namespace: hs.Namespace,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(hs, helixSagaV1.SchemeGroupVersion.WithKind(OperatorKindName)),
+			},
+			Labels: labels,
+		},
+		Spec: coreV1.ServiceSpec{
+			Type:                     coreV1.ServiceTypeClusterIP,
+			ClusterIP:                coreV1.ClusterIPNone,
+			Selector:                 labels,
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+// NewPodService returns the Service which only selects the pod of the StatefulSet in the ordinal
+func NewPodService(hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec, ordinal int32) (*coreV1.Service, error) {
+	podName := GetPodServiceName(spec.Name, ordinal)
+	svc := &coreV1.Service{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      podName,
+			Namespace: hs.Namespace,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(hs, helixSagaV1.SchemeGroupVersion.WithKind(OperatorKindName)),
+			},
+			Labels: map[string]string{
+				k8sCoreV1.LabelApp:        OperatorKindName,
+				k8sCoreV1.LabelController: hs.Name,
+				k8sCoreV1.LabelName:       spec.Name,
+				k8sCoreV1.LabelRole:       RolePodService,
+			},
+		},
+		Spec: coreV1.ServiceSpec{
+			Type:  k8sCoreV1.GetServiceType(spec.PodService.ServiceType),
+			Ports: spec.PodService.ServicePorts,
+			Selector: map[string]string{
+				k8sCoreV1.LabelApp:             OperatorKindName,
+				k8sCoreV1.LabelController:      hs.Name,
+				k8sCoreV1.LabelName:            spec.Name,
+				appsV1.StatefulSetPodNameLabel: podName,
+			},
+		},
+	}
+	if _, err := reconcileServiceLoadBalancerWithType(svc, svc.Spec.Type, spec); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// SyncStatefulSetServices creates or updates the headless Service and the pod Services of the StatefulSet app,
+// and removes the pod Services whose ordinals were out of the replicas.
+func SyncStatefulSetServices(ks k8sCoreV1.KubernetesResource, hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) error {
+	if err := syncService(ks, hs.Namespace, NewHeadlessService(hs, spec), spec); err != nil {
+		return err
+	}
+	var replicas int32
+	if spec.PodService != nil && len(spec.PodService.ServicePorts) > 0 {
+		replicas = appReplicas(spec)
+	}
+	for i := int32(0); i < replicas; i++ {
+		svc, err := NewPodService(hs, spec, i)
+		if err != nil {
+			return err
+		}
+		if err = syncService(ks, hs.Namespace, svc, spec); err != nil {
+			return err
+		}
+	}
+	return deletePodServices(ks, hs.Namespace, hs.Name, spec.Name, replicas)
+}
+
+// DeleteStatefulSetServices removes the headless Service and all the pod Services of the StatefulSet app
+func DeleteStatefulSetServices(ks k8sCoreV1.KubernetesResource, namespace, crdName, name string) error {
+	if err := ks.Service().Delete(namespace, GetHeadlessServiceName(name)); err != nil {
+		return err
+	}
+	return deletePodServices(ks, namespace, crdName, name, 0)
+}
+
+func syncService(ks k8sCoreV1.KubernetesResource, namespace string, tmpSvc *coreV1.Service, spec *helixSagaV1.HelixSagaAppSpec) error {
+	svc, err := ks.Service().Get(namespace, tmpSvc.Name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		_, err = ks.Service().Create(namespace, tmpSvc)
+		return err
+	}
+	// NEVER modify objects from the store
+	svc = svc.DeepCopy()
+	lbChanged, err := reconcileServiceLoadBalancerWithType(svc, tmpSvc.Spec.Type, spec)
+	if err != nil {
+		return err
+	}
+	if ok := compareService(svc, tmpSvc); !ok && !lbChanged && svc.Spec.PublishNotReadyAddresses == tmpSvc.Spec.PublishNotReadyAddresses {
+		return nil
+	}
+	svc.Labels = tmpSvc.Labels
+	svc.Spec.Type = tmpSvc.Spec.Type
+	svc.Spec.Ports = tmpSvc.Spec.Ports
+	svc.Spec.Selector = tmpSvc.Spec.Selector
+	svc.Spec.PublishNotReadyAddresses = tmpSvc.Spec.PublishNotReadyAddresses
+	_, err = ks.Service().Update(namespace, svc)
+	return err
+}
+
+// deletePodServices removes the pod Services whose ordinals were not less than the replicas
+func deletePodServices(ks k8sCoreV1.KubernetesResource, namespace, crdName, name string, replicas int32) error {
+	sl, err := ks.Service().List(namespace, getPodServiceLabelSelector(crdName, name))
+	if err != nil {
+		return err
+	}
+	for _, v := range sl.Items {
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(v.Name, fmt.Sprintf("%s-", k8sCoreV1.GetStatefulSetName(name))))
+		if err == nil && int32(ordinal) < replicas {
+			continue
+		}
+		klog.Infof("remove pod service namespace:%s crdName:%s name:%s", namespace, crdName, v.Name)
+		if err = ks.Service().Delete(namespace, v.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getPodServiceLabelSelector(crdName, name string) string {
+	ls, err := labels.Parse(GetLabelSelector(crdName, name))
+	if err != nil {
+		klog.Fatal(err)
+	}
+	req, err := labels.NewRequirement(k8sCoreV1.LabelRole, selection.Equals, []string{RolePodService})
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return ls.Add(*req).String()
+}
+
+// ListPodEndpoints returns the endpoints of the pods which were exposed by the pod Services of the StatefulSet app
+func ListPodEndpoints(ks k8sCoreV1.KubernetesResource, hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) ([]helixSagaV1.PodEndpoint, error) {
+	if spec.PodService == nil || len(spec.PodService.ServicePorts) == 0 {
+		return nil, nil
+	}
+	sl, err := ks.Service().List(hs.Namespace, getPodServiceLabelSelector(hs.Name, spec.Name))
+	if err != nil {
+		return nil, err
+	}
+	pl, err := ListPodByLabels(ks.ClientSet(), hs.Namespace, hs.Name, spec.Name)
+	if err != nil {
+		return nil, err
+	}
+	pods := make(map[string]*coreV1.Pod, len(pl.Items))
+	for i := range pl.Items {
+		pods[pl.Items[i].Name] = &pl.Items[i]
+	}
+	nodes := make(map[string]*coreV1.Node, 0)
+	res := make([]helixSagaV1.PodEndpoint, 0, len(sl.Items))
+	for i := range sl.Items {
+		svc := &sl.Items[i]
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(svc.Name, fmt.Sprintf("%s-", k8sCoreV1.GetStatefulSetName(spec.Name))))
+		if err != nil || int32(ordinal) >= appReplicas(spec) {
+			continue
+		}
+		pod := pods[svc.Name]
+		var node *coreV1.Node
+		if svc.Spec.Type == coreV1.ServiceTypeNodePort && pod != nil && pod.Spec.NodeName != "" {
+			if node, err = getNode(ks, nodes, pod.Spec.NodeName); err != nil {
+				klog.V(2).Info(err)
+			}
+		}
+		res = append(res, NewPodEndpoint(svc, int32(ordinal), pod, node))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Ordinal < res[j].Ordinal
+	})
+	return res, nil
+}
+
+func getNode(ks k8sCoreV1.KubernetesResource, nodes map[string]*coreV1.Node, name string) (*coreV1.Node, error) {
+	if t, ok := nodes[name]; ok {
+		return t, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+	defer cancel()
+	node, err := ks.ClientSet().CoreV1().Nodes().Get(ctx, name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nodes[name] = node
+	return node, nil
+}
+
+// NewPodEndpoint returns the endpoint of the pod which was exposed by the pod Service.
+// The pod and the node could be nil before the pod has been scheduled.
+func NewPodEndpoint(svc *coreV1.Service, ordinal int32, pod *coreV1.Pod, node *coreV1.Node) helixSagaV1.PodEndpoint {
+	ep := helixSagaV1.PodEndpoint{
+		PodName: svc.Name,
+		Ordinal: ordinal,
+		Ports:   make([]helixSagaV1.PodEndpointPort, 0, len(svc.Spec.Ports)),
+	}
+	switch svc.Spec.Type {
+	case coreV1.ServiceTypeLoadBalancer:
+		for _, v := range svc.Status.LoadBalancer.Ingress {
+			if v.IP != "" {
+				ep.Address = v.IP
+				break
+			}
+			if v.Hostname != "" {
+				ep.Address = v.Hostname
+				break
+			}
+		}
+	case coreV1.ServiceTypeNodePort:
+		ep.Address = nodeAddress(node)
+		if ep.Address == "" && pod != nil {
+			ep.Address = pod.Status.HostIP
+		}
+	default:
+		if svc.Spec.ClusterIP != coreV1.ClusterIPNone {
+			ep.Address = svc.Spec.ClusterIP
+		}
+	}
+	for _, v := range svc.Spec.Ports {
+		port := v.Port
+		if svc.Spec.Type == coreV1.ServiceTypeNodePort {
+			port = v.NodePort
+		}
+		ep.Ports = append(ep.Ports, helixSagaV1.PodEndpointPort{
+			Name:     v.Name,
+			Port:     port,
+			Protocol: v.Protocol,
+		})
+	}
+	if pod != nil && pod.DeletionTimestamp == nil {
+		for _, c := range pod.Status.Conditions {
+			if c.Type == coreV1.PodReady && c.Status == coreV1.ConditionTrue {
+				ep.Ready = true
+			}
+		}
+	}
+	return ep
+}
+
+// nodeAddress prefers the external address of the node, so that the clients outside the cluster could connect to it
+func nodeAddress(node *coreV1.Node) string {
+	if node == nil {
+		return ""
+	}
+	for _, t := range []coreV1.NodeAddressType{coreV1.NodeExternalIP, coreV1.NodeExternalDNS, coreV1.NodeInternalIP} {
+		for _, v := range node.Status.Addresses {
+			if v.Type == t && v.Address != "" {
+				return v.Address
+			}
+		}
+	}
+	return ""
+}
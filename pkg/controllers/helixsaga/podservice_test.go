@@ -0,0 +1,230 @@
+package helixsaga
+
+import (
+	"reflect"
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewPodService(t *testing.T) {
+	hs := &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "hs",
+			Namespace: "default",
+		},
+	}
+	spec := &helixSagaV1.HelixSagaAppSpec{
+		Name:     "game",
+		Template: helixSagaV1.TemplateTypeStatefulSet,
+		PodService: &helixSagaV1.PodServiceSpec{
+			ServiceType: coreV1.ServiceTypeNodePort,
+			ServicePorts: []coreV1.ServicePort{
+				{Name: "room", Port: 7000},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		ordinal int32
+		want    string
+	}{
+		{
+			name:    "TestNewPodService_ordinal_0",
+			ordinal: 0,
+			want:    "game-0",
+		},
+		{
+			name:    "TestNewPodService_ordinal_12",
+			ordinal: 12,
+			want:    "game-12",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, err := NewPodService(hs, spec, tt.ordinal)
+			if err != nil {
+				t.Fatalf("NewPodService() error = %v", err)
+			}
+			if svc.Name != tt.want {
+				t.Errorf("NewPodService() name = %v, want %v", svc.Name, tt.want)
+			}
+			if got := svc.Spec.Selector[appsV1.StatefulSetPodNameLabel]; got != tt.want {
+				t.Errorf("NewPodService() selector = %v, want %v", got, tt.want)
+			}
+			if svc.Spec.Type != coreV1.ServiceTypeNodePort {
+				t.Errorf("NewPodService() type = %v, want %v", svc.Spec.Type, coreV1.ServiceTypeNodePort)
+			}
+		})
+	}
+	headless := NewHeadlessService(hs, spec)
+	if headless.Spec.ClusterIP != coreV1.ClusterIPNone {
+		t.Errorf("NewHeadlessService() clusterIP = %v, want None", headless.Spec.ClusterIP)
+	}
+	if sts := NewStatefulSet(hs, spec); sts.Spec.ServiceName != headless.Name {
+		t.Errorf("NewStatefulSet() serviceName = %v, want %v", sts.Spec.ServiceName, headless.Name)
+	}
+}
+
+func TestNewPodEndpoint(t *testing.T) {
+	readyPod := &coreV1.Pod{
+		Status: coreV1.PodStatus{
+			HostIP: "10.0.0.1",
+			Conditions: []coreV1.PodCondition{
+				{Type: coreV1.PodReady, Status: coreV1.ConditionTrue},
+			},
+		},
+	}
+	node := &coreV1.Node{
+		Status: coreV1.NodeStatus{
+			Addresses: []coreV1.NodeAddress{
+				{Type: coreV1.NodeInternalIP, Address: "10.0.0.1"},
+				{Type: coreV1.NodeExternalIP, Address: "1.2.3.4"},
+			},
+		},
+	}
+	ports := []coreV1.ServicePort{
+		{Name: "room", Port: 7000, NodePort: 30700, Protocol: coreV1.ProtocolUDP},
+	}
+	type args struct {
+		svc  *coreV1.Service
+		pod  *coreV1.Pod
+		node *coreV1.Node
+	}
+	tests := []struct {
+		name string
+		args args
+		want helixSagaV1.PodEndpoint
+	}{
+		{
+			name: "TestNewPodEndpoint_load_balancer",
+			args: args{
+				svc: &coreV1.Service{
+					ObjectMeta: metaV1.ObjectMeta{Name: "game-1"},
+					Spec:       coreV1.ServiceSpec{Type: coreV1.ServiceTypeLoadBalancer, Ports: ports},
+					Status: coreV1.ServiceStatus{
+						LoadBalancer: coreV1.LoadBalancerStatus{
+							Ingress: []coreV1.LoadBalancerIngress{{IP: "5.6.7.8"}},
+						},
+					},
+				},
+				pod: readyPod,
+			},
+			want: helixSagaV1.PodEndpoint{
+				PodName: "game-1",
+				Ordinal: 1,
+				Address: "5.6.7.8",
+				Ports:   []helixSagaV1.PodEndpointPort{{Name: "room", Port: 7000, Protocol: coreV1.ProtocolUDP}},
+				Ready:   true,
+			},
+		},
+		{
+			name: "TestNewPodEndpoint_load_balancer_pending",
+			args: args{
+				svc: &coreV1.Service{
+					ObjectMeta: metaV1.ObjectMeta{Name: "game-1"},
+					Spec:       coreV1.ServiceSpec{Type: coreV1.ServiceTypeLoadBalancer, Ports: ports},
+				},
+			},
+			want: helixSagaV1.PodEndpoint{
+				PodName: "game-1",
+				Ordinal: 1,
+				Ports:   []helixSagaV1.PodEndpointPort{{Name: "room", Port: 7000, Protocol: coreV1.ProtocolUDP}},
+			},
+		},
+		{
+			name: "TestNewPodEndpoint_node_port",
+			args: args{
+				svc: &coreV1.Service{
+					ObjectMeta: metaV1.ObjectMeta{Name: "game-1"},
+					Spec:       coreV1.ServiceSpec{Type: coreV1.ServiceTypeNodePort, Ports: ports},
+				},
+				pod:  readyPod,
+				node: node,
+			},
+			want: helixSagaV1.PodEndpoint{
+				PodName: "game-1",
+				Ordinal: 1,
+				Address: "1.2.3.4",
+				Ports:   []helixSagaV1.PodEndpointPort{{Name: "room", Port: 30700, Protocol: coreV1.ProtocolUDP}},
+				Ready:   true,
+			},
+		},
+		{
+			name: "TestNewPodEndpoint_node_port_without_node",
+			args: args{
+				svc: &coreV1.Service{
+					ObjectMeta: metaV1.ObjectMeta{Name: "game-1"},
+					Spec:       coreV1.ServiceSpec{Type: coreV1.ServiceTypeNodePort, Ports: ports},
+				},
+				pod: readyPod,
+			},
+			want: helixSagaV1.PodEndpoint{
+				PodName: "game-1",
+				Ordinal: 1,
+				Address: "10.0.0.1",
+				Ports:   []helixSagaV1.PodEndpointPort{{Name: "room", Port: 30700, Protocol: coreV1.ProtocolUDP}},
+				Ready:   true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewPodEndpoint(tt.args.svc, 1, tt.args.pod, tt.args.node); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewPodEndpoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateServiceNames(t *testing.T) {
+	ports := []coreV1.ServicePort{{Name: "room", Port: 7000}}
+	tests := []struct {
+		name    string
+		specs   []helixSagaV1.HelixSagaAppSpec
+		wantErr bool
+	}{
+		{
+			name: "TestValidateServiceNames_pod_service",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "game", PodService: &helixSagaV1.PodServiceSpec{ServicePorts: ports}},
+				{Name: "game-1", Template: helixSagaV1.TemplateTypeDeployment},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestValidateServiceNames_headless",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "game", Template: helixSagaV1.TemplateTypeStatefulSet},
+				{Name: "game-headless", Template: helixSagaV1.TemplateTypeDeployment},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestValidateServiceNames_without_pod_service",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "game"},
+				{Name: "game-1"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "TestValidateServiceNames_not_ordinal",
+			specs: []helixSagaV1.HelixSagaAppSpec{
+				{Name: "game", PodService: &helixSagaV1.PodServiceSpec{ServicePorts: ports}},
+				{Name: "game-lobby"},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateServiceNames(tt.specs); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateServiceNames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package helixsaga
+
+import (
+	"context"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaClientSet "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// BeginImageUpdate records the in-flight update of the digest in the status of the apps with the UpdateTrigger scaleToZero
+// which were using the image. The replicas of an update which has been recorded would be kept, since the app might
+// have been scaled down by it.
+func BeginImageUpdate(clientSet helixSagaClientSet.Interface, namespace, crdName, image, digest string) error {
+	now := metav1.Now()
+	return updateApplications(clientSet, namespace, crdName, func(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec) bool {
+		if spec.Image != image || !IsScaledToZero(spec) {
+			return false
+		}
+		if t := app.Status.ImageUpdate; t != nil && t.Image == image {
+			if t.Digest == digest {
+				return false
+			}
+			t.Digest = digest
+			return true
+		}
+		app.Status.ImageUpdate = &helixSagaV1.ImageUpdateStatus{
+			Image:     image,
+			Digest:    digest,
+			Replicas:  appReplicas(spec),
+			Phase:     helixSagaV1.ImageUpdatePhaseScalingDown,
+			StartedAt: now,
+		}
+		return true
+	})
+}
+
+// ClearImageUpdate removes the in-flight update of the image from the apps which have not been scaled down by it
+func ClearImageUpdate(clientSet helixSagaClientSet.Interface, namespace, crdName, image string) error {
+	return updateApplications(clientSet, namespace, crdName, func(app *helixSagaV1.HelixSagaApp, _ *helixSagaV1.HelixSagaAppSpec) bool {
+		t := app.Status.ImageUpdate
+		if t == nil || t.Image != image || t.Phase != helixSagaV1.ImageUpdatePhaseScalingDown {
+			return false
+		}
+		app.Status.ImageUpdate = nil
+		return true
+	})
+}
+
+// rollbackImageUpdate restores the replicas which the app had before it was scaled down by the in-flight update,
+// and the update would be removed. It returns true if the app has been changed.
+func rollbackImageUpdate(app *helixSagaV1.HelixSagaApp) bool {
+	t := app.Status.ImageUpdate
+	if t == nil {
+		return false
+	}
+	// the replicas which have been changed since the app was scaled down would be kept
+	if t.Phase == helixSagaV1.ImageUpdatePhaseScaledDown && (app.Spec.Replicas == nil || *app.Spec.Replicas == 0) {
+		replicas := t.Replicas
+		app.Spec.Replicas = &replicas
+	}
+	app.Status.ImageUpdate = nil
+	return true
+}
+
+// RollbackStaleImageUpdates rolls back the in-flight updates of the apps which were no longer updated by the
+// image with the UpdateTrigger scaleToZero, e.g. the image has been edited while the operator was down
+func RollbackStaleImageUpdates(clientSet helixSagaClientSet.Interface, namespace, crdName string) error {
+	return updateApplications(clientSet, namespace, crdName, func(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec) bool {
+		t := app.Status.ImageUpdate
+		if t == nil || (t.Image == spec.Image && IsScaledToZero(spec)) {
+			return false
+		}
+		klog.Infof("ImageUpdate app:%s image:%s digest:%s has been rolled back", spec.Name, t.Image, t.Digest)
+		return rollbackImageUpdate(app)
+	})
+}
+
+// ResumeImageUpdates resumes the in-flight image updates of all the HelixSagas which were interrupted by the
+// restart of the operator
+func (c *controller) ResumeImageUpdates() {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		klog.V(2).Info(err)
+		return
+	}
+	for _, hs := range list {
+		c.resumeImageUpdates(hs)
+	}
+}
+
+// resumeImageUpdates rolls back the stale in-flight updates of the HelixSaga, then the others would be applied again.
+// The apps which have been scaled down would be scaled back to the replicas recorded in their ImageUpdate.
+func (c *controller) resumeImageUpdates(hs *helixSagaV1.HelixSaga) {
+	digests := make(map[string]string, 0)
+	stale := false
+	specs := GetAppSpecs(hs)
+	for i, v := range hs.Spec.Applications {
+		t := v.Status.ImageUpdate
+		if t == nil {
+			continue
+		}
+		if t.Image != specs[i].Image || !IsScaledToZero(&specs[i]) {
+			stale = true
+			continue
+		}
+		digests[t.Image] = t.Digest
+	}
+	if stale {
+		if err := RollbackStaleImageUpdates(c.clientSet, hs.Namespace, hs.Name); err != nil {
+			klog.V(2).Info(err)
+		}
+	}
+	for image, digest := range digests {
+		klog.Infof("HelixSaga crdName:%s image:%s digest:%s resumes the interrupted update", hs.Name, image, digest)
+		wo := NewWatchOption(context.Background(), c.kubeClientSet, c.clientSet, hs, image)
+		wo.Recorder = c.getRecorder()
+		go func(wo *WatchOption, digest string) {
+			defer wo.Close()
+			c.watchers.applyImageChange(wo, digest)
+		}(wo, digest)
+	}
+}
@@ -0,0 +1,143 @@
+package helixsaga
+
+import (
+	"context"
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaFake "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/fake"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBeginImageUpdate(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	game, lobby := int32(3), int32(2)
+	hs := &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"},
+		Spec: helixSagaV1.HelixSagaSpec{
+			Applications: []helixSagaV1.HelixSagaApp{
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, Replicas: &game, WatchPolicy: helixSagaV1.WatchPolicyAuto}},
+				{Spec: helixSagaV1.HelixSagaAppSpec{Name: "lobby", Image: image, Replicas: &lobby, WatchPolicy: helixSagaV1.WatchPolicyManual}},
+			},
+		},
+	}
+	ki := k8sFake.NewSimpleClientset()
+	clientSet := helixSagaFake.NewSimpleClientset(hs)
+	get := func() *helixSagaV1.HelixSaga {
+		t, err := clientSet.NevercaseV1().HelixSagas("default").Get(context.Background(), "hs", metaV1.GetOptions{})
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	if err := BeginImageUpdate(clientSet, "default", "hs", image, "sha256:1"); err != nil {
+		t.Fatal(err)
+	}
+	got := get().Spec.Applications
+	if u := got[0].Status.ImageUpdate; u == nil || u.Replicas != 3 || u.Digest != "sha256:1" || u.Phase != helixSagaV1.ImageUpdatePhaseScalingDown {
+		t.Fatalf("BeginImageUpdate() game = %+v", u)
+	}
+	if u := got[1].Status.ImageUpdate; u != nil {
+		t.Errorf("BeginImageUpdate() lobby = %+v, want nil", u)
+	}
+
+	// scaled down, then the operator was restarted
+	if _, err := RetryPatchHelixSaga(context.Background(), ki, clientSet, "default", "hs", image, nil); err != nil {
+		t.Fatal(err)
+	}
+	if u := get().Spec.Applications[0].Status.ImageUpdate; u == nil || u.Phase != helixSagaV1.ImageUpdatePhaseScaledDown {
+		t.Fatalf("RetryPatchHelixSaga() game = %+v, want phase ScaledDown", u)
+	}
+	if err := BeginImageUpdate(clientSet, "default", "hs", image, "sha256:2"); err != nil {
+		t.Fatal(err)
+	}
+	res, err := RetryPatchHelixSaga(context.Background(), ki, clientSet, "default", "hs", image, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["game"] != 3 {
+		t.Errorf("RetryPatchHelixSaga() = %v, want the persisted map[game:3]", res)
+	}
+
+	// scaled up
+	if _, err = RetryPatchHelixSaga(context.Background(), ki, clientSet, "default", "hs", image, res); err != nil {
+		t.Fatal(err)
+	}
+	got = get().Spec.Applications
+	if *got[0].Spec.Replicas != 3 || got[0].Status.ImageUpdate != nil {
+		t.Errorf("RetryPatchHelixSaga() game replicas = %d update = %+v", *got[0].Spec.Replicas, got[0].Status.ImageUpdate)
+	}
+}
+
+func TestRollbackStaleImageUpdates(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	zero, one := int32(0), int32(1)
+	update := func(phase helixSagaV1.ImageUpdatePhase) *helixSagaV1.ImageUpdateStatus {
+		return &helixSagaV1.ImageUpdateStatus{Image: image, Digest: "sha256:1", Replicas: 3, Phase: phase}
+	}
+	tests := []struct {
+		name         string
+		app          helixSagaV1.HelixSagaApp
+		wantReplicas int32
+		wantUpdate   bool
+	}{
+		{
+			name: "TestRollbackStaleImageUpdates_resumed",
+			app: helixSagaV1.HelixSagaApp{
+				Spec:   helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, Replicas: &zero, WatchPolicy: helixSagaV1.WatchPolicyAuto},
+				Status: helixSagaV1.HelixSagaAppStatus{ImageUpdate: update(helixSagaV1.ImageUpdatePhaseScaledDown)},
+			},
+			wantReplicas: 0,
+			wantUpdate:   true,
+		},
+		{
+			name: "TestRollbackStaleImageUpdates_image_edited",
+			app: helixSagaV1.HelixSagaApp{
+				Spec:   helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image + "2", Replicas: &zero, WatchPolicy: helixSagaV1.WatchPolicyAuto},
+				Status: helixSagaV1.HelixSagaAppStatus{ImageUpdate: update(helixSagaV1.ImageUpdatePhaseScaledDown)},
+			},
+			wantReplicas: 3,
+		},
+		{
+			name: "TestRollbackStaleImageUpdates_manual_not_scaled",
+			app: helixSagaV1.HelixSagaApp{
+				Spec:   helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, Replicas: &zero, WatchPolicy: helixSagaV1.WatchPolicyManual},
+				Status: helixSagaV1.HelixSagaAppStatus{ImageUpdate: update(helixSagaV1.ImageUpdatePhaseScalingDown)},
+			},
+			wantReplicas: 0,
+		},
+		{
+			name: "TestRollbackStaleImageUpdates_replicas_changed",
+			app: helixSagaV1.HelixSagaApp{
+				Spec:   helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image + "2", Replicas: &one, WatchPolicy: helixSagaV1.WatchPolicyAuto},
+				Status: helixSagaV1.HelixSagaAppStatus{ImageUpdate: update(helixSagaV1.ImageUpdatePhaseScaledDown)},
+			},
+			wantReplicas: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &helixSagaV1.HelixSaga{
+				ObjectMeta: metaV1.ObjectMeta{Name: "hs", Namespace: "default"},
+				Spec:       helixSagaV1.HelixSagaSpec{Applications: []helixSagaV1.HelixSagaApp{tt.app}},
+			}
+			clientSet := helixSagaFake.NewSimpleClientset(hs)
+			if err := RollbackStaleImageUpdates(clientSet, "default", "hs"); err != nil {
+				t.Fatal(err)
+			}
+			got, err := clientSet.NevercaseV1().HelixSagas("default").Get(context.Background(), "hs", metaV1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			app := got.Spec.Applications[0]
+			if *app.Spec.Replicas != tt.wantReplicas {
+				t.Errorf("RollbackStaleImageUpdates() replicas = %d, want %d", *app.Spec.Replicas, tt.wantReplicas)
+			}
+			if (app.Status.ImageUpdate != nil) != tt.wantUpdate {
+				t.Errorf("RollbackStaleImageUpdates() update = %+v, want %v", app.Status.ImageUpdate, tt.wantUpdate)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package helixsaga
+
+import (
+	"fmt"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	"github.com/robfig/cron"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// ScheduleInterval is the interval between the checks of the Schedules
+var ScheduleInterval = time.Minute
+
+// scheduleLookbacks are the durations which would be searched backwards for the last start of a schedule.
+// The longer one would only be searched if the schedule hasn't been started within the shorter one.
+var scheduleLookbacks = []time.Duration{time.Hour, time.Hour * 24, time.Hour * 24 * 7, time.Hour * 24 * 31, time.Hour * 24 * 366}
+
+// LastScheduleTime returns the last start of the schedule which was not after the time t.
+// A zero time would be returned if it hasn't been started within a year.
+func LastScheduleTime(sched cron.Schedule, t time.Time) time.Time {
+	for _, d := range scheduleLookbacks {
+		var last time.Time
+		for next := sched.Next(t.Add(-d)); !next.IsZero() && !next.After(t); next = sched.Next(next) {
+			last = next
+		}
+		if !last.IsZero() {
+			return last
+		}
+	}
+	return time.Time{}
+}
+
+// ActiveSchedule returns the one of the Schedules which has been started most recently before the time t, and the start of it.
+// A nil schedule would be returned if none of them has been started.
+func ActiveSchedule(schedules []helixSagaV1.ReplicaSchedule, t time.Time) (*helixSagaV1.ReplicaSchedule, time.Time, error) {
+	var res *helixSagaV1.ReplicaSchedule
+	var start time.Time
+	for i, v := range schedules {
+		sched, err := ParseSchedule(v.Schedule)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("invalid schedule %s: %v", v.Name, err)
+		}
+		loc, err := LoadLocation(v.TimeZone)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("invalid schedule %s: %v", v.Name, err)
+		}
+		last := LastScheduleTime(sched, t.In(loc))
+		if last.IsZero() {
+			continue
+		}
+		if res == nil || last.After(start) {
+			res, start = &schedules[i], last
+		}
+	}
+	return res, start, nil
+}
+
+// applySchedule scales the app to the replicas of the active schedule if it has been started since the one which was applied.
+// The replicas would be restored after the Maintenance or the in-flight image update if the app has been stopped by them.
+// It returns true if the app has been changed.
+func applySchedule(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec, now time.Time) (bool, error) {
+	if len(spec.Schedules) == 0 {
+		if app.Status.Schedule == nil {
+			return false, nil
+		}
+		app.Status.Schedule = nil
+		return true, nil
+	}
+	s, start, err := ActiveSchedule(spec.Schedules, now)
+	if err != nil || s == nil {
+		return false, err
+	}
+	t := app.Status.Schedule
+	if t != nil && t.Name == s.Name && t.Replicas == s.Replicas && !start.After(t.LastScheduleTime.Time) {
+		return false, nil
+	}
+	replicas := s.Replicas
+	SetAppReplicas(app, replicas)
+	app.Status.Schedule = &helixSagaV1.ScheduleStatus{
+		Name:             s.Name,
+		Replicas:         replicas,
+		LastScheduleTime: metav1.NewTime(start),
+	}
+	return true, nil
+}
+
+// SyncSchedules scales the apps of all the HelixSagas by their Schedules
+func (c *controller) SyncSchedules() {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		klog.V(2).Info(err)
+		return
+	}
+	now := time.Now()
+	for _, hs := range list {
+		if err = c.syncSchedules(hs, now); err != nil {
+			klog.V(2).Info(err)
+		}
+	}
+}
+
+// syncSchedules scales the apps of the HelixSaga by their Schedules at the time now
+func (c *controller) syncSchedules(hs *helixSagaV1.HelixSaga, now time.Time) error {
+	scheduled := false
+	specs := GetAppSpecs(hs)
+	for i, v := range hs.Spec.Applications {
+		if len(specs[i].Schedules) > 0 || v.Status.Schedule != nil {
+			scheduled = true
+		}
+	}
+	if !scheduled {
+		return nil
+	}
+	recorder := c.getRecorder()
+	return updateApplications(c.clientSet, hs.Namespace, hs.Name, func(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec) bool {
+		changed, err := applySchedule(app, spec, now)
+		if err != nil {
+			klog.V(2).Infof("HelixSaga crdName:%s app:%s schedules err:%v", hs.Name, spec.Name, err)
+			if recorder != nil {
+				recorder.Eventf(hs, coreV1.EventTypeWarning, ScheduleInvalid, MessageScheduleInvalid, spec.Name, err)
+			}
+			return false
+		}
+		if changed && app.Status.Schedule != nil {
+			t := app.Status.Schedule
+			klog.Infof("HelixSaga crdName:%s app:%s was scaled to %d by schedule %s", hs.Name, spec.Name, t.Replicas, t.Name)
+			if recorder != nil {
+				recorder.Eventf(hs, coreV1.EventTypeNormal, ScheduleApplied, MessageScheduleApplied, spec.Name, t.Replicas, t.Name)
+			}
+		}
+		return changed
+	})
+}
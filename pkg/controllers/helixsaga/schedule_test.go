@@ -0,0 +1,168 @@
+package helixsaga
+
+import (
+	"testing"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// the event servers were scaled up at 18:00 on Fridays and down at 03:00 on Mondays in Asia/Shanghai
+var eventSchedules = []helixSagaV1.ReplicaSchedule{
+	{Name: "event-start", Schedule: "0 18 * * 5", TimeZone: "Asia/Shanghai", Replicas: 10},
+	{Name: "event-end", Schedule: "0 3 * * 1", TimeZone: "Asia/Shanghai", Replicas: 2},
+}
+
+func TestActiveSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedules []helixSagaV1.ReplicaSchedule
+		now       time.Time
+		want      string
+		wantStart time.Time
+		wantErr   bool
+	}{
+		{
+			// Saturday 2021-06-05 12:00 UTC
+			name:      "TestActiveSchedule_start",
+			schedules: eventSchedules,
+			now:       time.Date(2021, 6, 5, 12, 0, 0, 0, time.UTC),
+			want:      "event-start",
+			wantStart: time.Date(2021, 6, 4, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			// Wednesday 2021-06-09 12:00 UTC
+			name:      "TestActiveSchedule_end",
+			schedules: eventSchedules,
+			now:       time.Date(2021, 6, 9, 12, 0, 0, 0, time.UTC),
+			want:      "event-end",
+			wantStart: time.Date(2021, 6, 6, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "TestActiveSchedule_exact",
+			schedules: eventSchedules,
+			now:       time.Date(2021, 6, 4, 10, 0, 0, 0, time.UTC),
+			want:      "event-start",
+			wantStart: time.Date(2021, 6, 4, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "TestActiveSchedule_never",
+			schedules: []helixSagaV1.ReplicaSchedule{{Name: "leap", Schedule: "0 0 30 2 *", Replicas: 1}},
+			now:       time.Date(2021, 6, 9, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "TestActiveSchedule_invalid",
+			schedules: []helixSagaV1.ReplicaSchedule{{Name: "invalid", Schedule: "0 3 * *", Replicas: 1}},
+			now:       time.Date(2021, 6, 9, 12, 0, 0, 0, time.UTC),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, start, err := ActiveSchedule(tt.schedules, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ActiveSchedule() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("ActiveSchedule() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Name != tt.want || !start.Equal(tt.wantStart) {
+				t.Errorf("ActiveSchedule() = %+v %v, want %s %v", got, start, tt.want, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestApplySchedule(t *testing.T) {
+	// Saturday 2021-06-05 12:00 UTC, the event-start was started at 2021-06-04 10:00 UTC
+	now := time.Date(2021, 6, 5, 12, 0, 0, 0, time.UTC)
+	applied := &helixSagaV1.ScheduleStatus{
+		Name:             "event-start",
+		Replicas:         10,
+		LastScheduleTime: metaV1.NewTime(time.Date(2021, 6, 4, 10, 0, 0, 0, time.UTC)),
+	}
+	lastWeek := &helixSagaV1.ScheduleStatus{
+		Name:             "event-end",
+		Replicas:         2,
+		LastScheduleTime: metaV1.NewTime(time.Date(2021, 5, 30, 19, 0, 0, 0, time.UTC)),
+	}
+	two, twelve, zero := int32(2), int32(12), int32(0)
+	tests := []struct {
+		name         string
+		app          helixSagaV1.HelixSagaApp
+		want         bool
+		wantReplicas int32
+		wantUpdate   int32
+	}{
+		{
+			name: "TestApplySchedule_start",
+			app: helixSagaV1.HelixSagaApp{
+				Spec:   helixSagaV1.HelixSagaAppSpec{Name: "event", Replicas: &two, Schedules: eventSchedules},
+				Status: helixSagaV1.HelixSagaAppStatus{Schedule: lastWeek},
+			},
+			want:         true,
+			wantReplicas: 10,
+		},
+		{
+			// the replicas which have been edited after the start would be kept
+			name: "TestApplySchedule_manual",
+			app: helixSagaV1.HelixSagaApp{
+				Spec:   helixSagaV1.HelixSagaAppSpec{Name: "event", Replicas: &twelve, Schedules: eventSchedules},
+				Status: helixSagaV1.HelixSagaAppStatus{Schedule: applied},
+			},
+			want:         false,
+			wantReplicas: 12,
+		},
+		{
+			// the app has been scaled down by the image update, the replicas would be restored after it
+			name: "TestApplySchedule_imageUpdate",
+			app: helixSagaV1.HelixSagaApp{
+				Spec: helixSagaV1.HelixSagaAppSpec{Name: "event", Replicas: &zero, Schedules: eventSchedules},
+				Status: helixSagaV1.HelixSagaAppStatus{
+					Schedule:    lastWeek,
+					ImageUpdate: &helixSagaV1.ImageUpdateStatus{Replicas: 2, Phase: helixSagaV1.ImageUpdatePhaseScaledDown},
+				},
+			},
+			want:         true,
+			wantReplicas: 0,
+			wantUpdate:   10,
+		},
+		{
+			name: "TestApplySchedule_removed",
+			app: helixSagaV1.HelixSagaApp{
+				Spec:   helixSagaV1.HelixSagaAppSpec{Name: "event", Replicas: &twelve},
+				Status: helixSagaV1.HelixSagaAppStatus{Schedule: applied},
+			},
+			want:         true,
+			wantReplicas: 12,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.app.DeepCopy()
+			got, err := applySchedule(app, &app.Spec, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || *app.Spec.Replicas != tt.wantReplicas {
+				t.Errorf("applySchedule() = %v replicas %d, want %v replicas %d", got, *app.Spec.Replicas, tt.want, tt.wantReplicas)
+			}
+			if app.Status.ImageUpdate != nil && app.Status.ImageUpdate.Replicas != tt.wantUpdate {
+				t.Errorf("applySchedule() ImageUpdate replicas = %d, want %d", app.Status.ImageUpdate.Replicas, tt.wantUpdate)
+			}
+			if len(app.Spec.Schedules) == 0 {
+				if app.Status.Schedule != nil {
+					t.Errorf("applySchedule() status = %+v, want nil", app.Status.Schedule)
+				}
+				return
+			}
+			if s := app.Status.Schedule; s == nil || s.Name != "event-start" || !s.LastScheduleTime.Equal(&applied.LastScheduleTime) {
+				t.Errorf("applySchedule() status = %+v", s)
+			}
+		})
+	}
+}
@@ -1,33 +1,212 @@
 package helixsaga
 
 import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
 	helixSagav1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
 	"github.com/Shanghai-Lunara/helixsaga-operator/pkg/serviceloadbalancer"
 	k8scorev1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	"github.com/nevercase/k8s-controller-custom-resource/pkg/env"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 )
 
-func NewService(hs *helixSagav1.HelixSaga, spec *helixSagav1.HelixSagaAppSpec) *corev1.Service {
+func NewService(hs *helixSagav1.HelixSaga, spec *helixSagav1.HelixSagaAppSpec) (*corev1.Service, error) {
+	sourceRanges, err := serviceSourceRanges(spec.ServiceType, spec)
+	if err != nil {
+		return nil, err
+	}
 	labels := map[string]string{
 		k8scorev1.LabelApp:        OperatorKindName,
 		k8scorev1.LabelController: hs.Name,
 		k8scorev1.LabelName:       spec.Name,
 	}
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      k8scorev1.GetServiceName(spec.Name),
 			Namespace: hs.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(hs, helixSagav1.SchemeGroupVersion.WithKind(OperatorKindName)),
 			},
-			Labels:      labels,
-			Annotations: serviceloadbalancer.Annotation(spec.ServiceType, spec.ServiceWhiteList),
+			Labels: labels,
 		},
 		Spec: corev1.ServiceSpec{
-			Type:     k8scorev1.GetServiceType(spec.ServiceType),
-			Ports:    spec.ServicePorts,
-			Selector: labels,
+			Type:                     k8scorev1.GetServiceType(spec.ServiceType),
+			Ports:                    spec.ServicePorts,
+			Selector:                 labels,
+			LoadBalancerSourceRanges: serviceloadbalancer.LoadBalancerSourceRanges(spec.ServiceType, sourceRanges),
 		},
 	}
+	// the Service would only select the pods of the new workload after it has been ready during the switch of the Template
+	if t := GetTemplateMigration(hs, spec.Name); t != nil && t.Phase == helixSagav1.TemplateMigrationPhaseSwitched {
+		svc.Spec.Selector = GetPodTemplateLabels(labels, t.To)
+	}
+	ReconcileServiceAnnotations(svc, serviceloadbalancer.Annotation(spec.ServiceType, spec.ServiceWhiteList, sourceRanges))
+	return svc, nil
+}
+
+// serviceSourceRanges returns the allowlist of the app which was merged from
+// the ServiceSourceRanges and the shared allowlists referenced by the ServiceSourceRangeRefs
+func serviceSourceRanges(svcType corev1.ServiceType, spec *helixSagav1.HelixSagaAppSpec) ([]string, error) {
+	if svcType != corev1.ServiceTypeLoadBalancer {
+		return nil, nil
+	}
+	return serviceloadbalancer.SourceRanges(spec.ServiceSourceRanges, spec.ServiceSourceRangeRefs)
+}
+
+// reconcileServiceLoadBalancer applies the load balancer annotations and the allowlist of the app on the Service
+// in the type of the spec.ServiceType. It returns true if the Service has been changed.
+func reconcileServiceLoadBalancer(svc *corev1.Service, spec *helixSagav1.HelixSagaAppSpec) (bool, error) {
+	return reconcileServiceLoadBalancerWithType(svc, spec.ServiceType, spec)
+}
+
+func reconcileServiceLoadBalancerWithType(svc *corev1.Service, svcType corev1.ServiceType, spec *helixSagav1.HelixSagaAppSpec) (bool, error) {
+	sourceRanges, err := serviceSourceRanges(svcType, spec)
+	if err != nil {
+		return false, err
+	}
+	changed := ReconcileServiceAnnotations(svc, serviceloadbalancer.Annotation(svcType, spec.ServiceWhiteList, sourceRanges))
+	desired := serviceloadbalancer.LoadBalancerSourceRanges(svcType, sourceRanges)
+	if !equalStrings(svc.Spec.LoadBalancerSourceRanges, desired) {
+		svc.Spec.LoadBalancerSourceRanges = desired
+		changed = true
+	}
+	return changed, nil
+}
+
+func equalStrings(s1, s2 []string) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := range s1 {
+		if s1[i] != s2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ReconcileServiceAnnotations sets the desired annotations on the Service and removes the ones which had been
+// set by the operator before but were no longer desired. The keys owned by the operator were recorded
+// in the ManagedAnnotations annotation, so that the annotations added by others would be left untouched.
+// A Service created before the keys have been recorded would be treated as owning the whitelist groups.
+// It returns true if the annotations of the Service have been changed.
+func ReconcileServiceAnnotations(svc *corev1.Service, desired map[string]string) bool {
+	changed := false
+	for _, k := range managedAnnotationKeys(svc) {
+		if _, ok := svc.Annotations[k]; !ok {
+			continue
+		}
+		if _, ok := desired[k]; !ok {
+			delete(svc.Annotations, k)
+			changed = true
+		}
+	}
+	for k, v := range desired {
+		if t, ok := svc.Annotations[k]; ok && t == v {
+			continue
+		}
+		if svc.Annotations == nil {
+			svc.Annotations = make(map[string]string, 0)
+		}
+		svc.Annotations[k] = v
+		changed = true
+	}
+	keys := make([]string, 0, len(desired))
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	managed := strings.Join(keys, ",")
+	if t, ok := svc.Annotations[ManagedAnnotations]; ok && t == managed {
+		return changed
+	}
+	if managed == "" {
+		if _, ok := svc.Annotations[ManagedAnnotations]; ok {
+			delete(svc.Annotations, ManagedAnnotations)
+			changed = true
+		}
+		return changed
+	}
+	if svc.Annotations == nil {
+		svc.Annotations = make(map[string]string, 0)
+	}
+	svc.Annotations[ManagedAnnotations] = managed
+	return true
+}
+
+// managedAnnotationKeys returns the annotation keys of the Service which were owned by the operator.
+// The keys of the whitelist groups would be returned if they were not recorded, since the Services created
+// before the ManagedAnnotations have been introduced were set with one of the groups.
+func managedAnnotationKeys(svc *corev1.Service) []string {
+	t, ok := svc.Annotations[ManagedAnnotations]
+	if !ok {
+		return serviceloadbalancer.WhiteListKeys()
+	}
+	if t == "" {
+		return nil
+	}
+	return strings.Split(t, ",")
+}
+
+// SyncLoadBalancerServices re-applies the load balancer annotations and the allowlists on all the LoadBalancer Services
+// which were owned by the HelixSagas. It would be called after the serviceloadbalancer config has been reloaded.
+func (c *controller) SyncLoadBalancerServices(_ *serviceloadbalancer.Annotations) {
+	hsl, err := c.lister.List(labels.Everything())
+	if err != nil {
+		klog.V(2).Info(err)
+		return
+	}
+	for _, hs := range hsl {
+		for _, v := range GetAppSpecs(hs) {
+			for name, svcType := range loadBalancerServices(&v) {
+				if err := updateServiceAnnotations(c.kubeClientSet, hs.Namespace, name, svcType, &v); err != nil {
+					klog.V(2).Infof("SyncLoadBalancerServices namespace:%s crdName:%s specName:%s service:%s err:%v", hs.Namespace, hs.Name, v.Name, name, err)
+				}
+			}
+		}
+	}
+}
+
+// loadBalancerServices returns the names of the LoadBalancer Services of the app, including the pod Services
+func loadBalancerServices(spec *helixSagav1.HelixSagaAppSpec) map[string]corev1.ServiceType {
+	res := make(map[string]corev1.ServiceType, 0)
+	if spec.ServiceType == corev1.ServiceTypeLoadBalancer && len(spec.ServicePorts) > 0 {
+		res[k8scorev1.GetServiceName(spec.Name)] = spec.ServiceType
+	}
+	if spec.Template == helixSagav1.TemplateTypeDeployment || spec.PodService == nil {
+		return res
+	}
+	if spec.PodService.ServiceType != corev1.ServiceTypeLoadBalancer || len(spec.PodService.ServicePorts) == 0 {
+		return res
+	}
+	for i := int32(0); i < appReplicas(spec); i++ {
+		res[GetPodServiceName(spec.Name, i)] = spec.PodService.ServiceType
+	}
+	return res
+}
+
+func updateServiceAnnotations(ki kubernetes.Interface, namespace, name string, svcType corev1.ServiceType, spec *helixSagav1.HelixSagaAppSpec) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+	defer cancel()
+	svc, err := ki.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	ok, err := reconcileServiceLoadBalancerWithType(svc, svcType, spec)
+	if err != nil || !ok {
+		return err
+	}
+	_, err = ki.CoreV1().Services(namespace).Update(ctx, svc, metav1.UpdateOptions{})
+	return err
 }
@@ -0,0 +1,135 @@
+package helixsaga
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Shanghai-Lunara/helixsaga-operator/pkg/serviceloadbalancer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileServiceAnnotations(t *testing.T) {
+	serviceloadbalancer.Set(&serviceloadbalancer.Annotations{
+		WhiteListOn:  map[string]string{"acl-status": "on", "acl-id": "abc", "acl-type": "white"},
+		WhiteListOff: map[string]string{"acl-status": "off"},
+	})
+	defer serviceloadbalancer.Set(nil)
+	type args struct {
+		annotations map[string]string
+		desired     map[string]string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		want        map[string]string
+		wantChanged bool
+	}{
+		{
+			name: "TestReconcileServiceAnnotations_new",
+			args: args{
+				annotations: nil,
+				desired: map[string]string{
+					"acl-status": "on",
+					"acl-id":     "abc",
+				},
+			},
+			want: map[string]string{
+				"acl-status":       "on",
+				"acl-id":           "abc",
+				ManagedAnnotations: "acl-id,acl-status",
+			},
+			wantChanged: true,
+		},
+		{
+			name: "TestReconcileServiceAnnotations_whitelist_off",
+			args: args{
+				annotations: map[string]string{
+					"acl-status":       "on",
+					"acl-id":           "abc",
+					"others":           "kept",
+					ManagedAnnotations: "acl-id,acl-status",
+				},
+				desired: map[string]string{
+					"acl-status": "off",
+				},
+			},
+			want: map[string]string{
+				"acl-status":       "off",
+				"others":           "kept",
+				ManagedAnnotations: "acl-status",
+			},
+			wantChanged: true,
+		},
+		{
+			name: "TestReconcileServiceAnnotations_whitelist_off_unrecorded",
+			args: args{
+				// the Service was created before the managed annotations have been recorded
+				annotations: map[string]string{
+					"acl-status": "on",
+					"acl-id":     "abc",
+					"acl-type":   "white",
+					"others":     "kept",
+				},
+				desired: map[string]string{
+					"acl-status": "off",
+				},
+			},
+			want: map[string]string{
+				"acl-status":       "off",
+				"others":           "kept",
+				ManagedAnnotations: "acl-status",
+			},
+			wantChanged: true,
+		},
+		{
+			name: "TestReconcileServiceAnnotations_unchanged",
+			args: args{
+				annotations: map[string]string{
+					"acl-status":       "off",
+					"others":           "kept",
+					ManagedAnnotations: "acl-status",
+				},
+				desired: map[string]string{
+					"acl-status": "off",
+				},
+			},
+			want: map[string]string{
+				"acl-status":       "off",
+				"others":           "kept",
+				ManagedAnnotations: "acl-status",
+			},
+			wantChanged: false,
+		},
+		{
+			name: "TestReconcileServiceAnnotations_cluster_ip",
+			args: args{
+				annotations: map[string]string{
+					"acl-status":       "off",
+					"others":           "kept",
+					ManagedAnnotations: "acl-status",
+				},
+				desired: map[string]string{},
+			},
+			want: map[string]string{
+				"others": "kept",
+			},
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tt.args.annotations,
+				},
+			}
+			if got := ReconcileServiceAnnotations(svc, tt.args.desired); got != tt.wantChanged {
+				t.Errorf("ReconcileServiceAnnotations() = %v, want %v", got, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(svc.Annotations, tt.want) {
+				t.Errorf("ReconcileServiceAnnotations() annotations = %v, want %v", svc.Annotations, tt.want)
+			}
+		})
+	}
+}
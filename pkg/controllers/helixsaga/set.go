@@ -0,0 +1,331 @@
+package helixsaga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaClientSet "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	k8sCoreV1 "github.com/nevercase/k8s-controller-custom-resource/core/v1"
+	"github.com/nevercase/k8s-controller-custom-resource/pkg/env"
+	coreV1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+// parameterPattern matches the $(parameter) in the string fields of a HelixSagaTemplate
+var parameterPattern = regexp.MustCompile(`\$\(([A-Za-z0-9_.-]+)\)`)
+
+// ExpandParameters returns a copy of the spec whose $(parameter) in all the string fields have been replaced by the parameters.
+// An error listing the names would be returned if any one of the parameters was not found.
+func ExpandParameters(spec *helixSagaV1.HelixSagaSpec, parameters map[string]string) (*helixSagaV1.HelixSagaSpec, error) {
+	t, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var obj interface{}
+	if err = json.Unmarshal(t, &obj); err != nil {
+		return nil, err
+	}
+	missing := make(map[string]bool, 0)
+	obj = expandParameters(obj, parameters, missing)
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for k := range missing {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("parameters %s were not found", strings.Join(names, ","))
+	}
+	if t, err = json.Marshal(obj); err != nil {
+		return nil, err
+	}
+	res := &helixSagaV1.HelixSagaSpec{}
+	if err = json.Unmarshal(t, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// expandParameters replaces the parameters in the string values of the json object, the keys of the maps would be kept
+func expandParameters(obj interface{}, parameters map[string]string, missing map[string]bool) interface{} {
+	switch t := obj.(type) {
+	case string:
+		return parameterPattern.ReplaceAllStringFunc(t, func(s string) string {
+			name := parameterPattern.FindStringSubmatch(s)[1]
+			if v, ok := parameters[name]; ok {
+				return v
+			}
+			missing[name] = true
+			return s
+		})
+	case map[string]interface{}:
+		for k, v := range t {
+			t[k] = expandParameters(v, parameters, missing)
+		}
+	case []interface{}:
+		for i, v := range t {
+			t[i] = expandParameters(v, parameters, missing)
+		}
+	}
+	return obj
+}
+
+// NewHelixSaga returns the HelixSaga of the item which was stamped from the HelixSagaTemplate, and owned by the HelixSagaSet
+func NewHelixSaga(set *helixSagaV1.HelixSagaSet, template *helixSagaV1.HelixSagaTemplate, item *helixSagaV1.HelixSagaSetItem) (*helixSagaV1.HelixSaga, error) {
+	parameters := map[string]string{
+		"name":      item.Name,
+		"namespace": set.Namespace,
+	}
+	for k, v := range template.Spec.Parameters {
+		parameters[k] = v
+	}
+	for k, v := range item.Parameters {
+		parameters[k] = v
+	}
+	spec, err := ExpandParameters(&template.Spec.Template, parameters)
+	if err != nil {
+		return nil, err
+	}
+	// the statuses were never stamped
+	for i := range spec.Applications {
+		spec.Applications[i].Status = helixSagaV1.HelixSagaAppStatus{}
+	}
+	return &helixSagaV1.HelixSaga{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      item.Name,
+			Namespace: set.Namespace,
+			Labels: map[string]string{
+				HelixSagaSetLabel: set.Name,
+			},
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(set, helixSagaV1.SchemeGroupVersion.WithKind(HelixSagaSetKindName)),
+			},
+		},
+		Spec: *spec,
+	}, nil
+}
+
+// keepManagedFields copies the fields of the existing HelixSaga which were managed by the operator into the desired one:
+// the statuses, the replicas of the apps stopped by an in-flight image update or the Maintenance or scaled by the Schedules,
+// and the images selected by the ImagePolicy. The Maintenance of the HelixSaga would be kept unless the template has one.
+func keepManagedFields(existing, desired *helixSagaV1.HelixSaga) {
+	desired.Status = existing.Status
+	if desired.Spec.Maintenance == nil {
+		desired.Spec.Maintenance = existing.Spec.Maintenance
+	}
+	apps := make(map[string]*helixSagaV1.HelixSagaApp, len(existing.Spec.Applications))
+	for i := range existing.Spec.Applications {
+		apps[existing.Spec.Applications[i].Spec.Name] = &existing.Spec.Applications[i]
+	}
+	specs := GetAppSpecs(desired)
+	for i := range desired.Spec.Applications {
+		app := &desired.Spec.Applications[i]
+		t, ok := apps[app.Spec.Name]
+		if !ok {
+			continue
+		}
+		app.Status = *t.Status.DeepCopy()
+		if t.Status.ImageUpdate != nil || t.Status.Maintenance != nil || (t.Status.Schedule != nil && len(specs[i].Schedules) > 0) {
+			app.Spec.Replicas = t.Spec.Replicas
+		}
+		if specs[i].ImagePolicy != nil && t.Spec.Image != "" {
+			app.Spec.Image = t.Spec.Image
+		}
+	}
+}
+
+// syncHelixSaga creates or updates the HelixSaga of the item.
+// The HelixSaga of the same name which was not owned by the HelixSagaSet would never be adopted.
+func (c *controller) syncHelixSaga(clientSet helixSagaClientSet.Interface, set *helixSagaV1.HelixSagaSet, desired *helixSagaV1.HelixSaga) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+	defer cancel()
+	existing, err := c.lister.HelixSagas(desired.Namespace).Get(desired.Name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		klog.Infof("HelixSagaSet:%s/%s creates HelixSaga:%s", set.Namespace, set.Name, desired.Name)
+		_, err = clientSet.NevercaseV1().HelixSagas(desired.Namespace).Create(ctx, desired, metaV1.CreateOptions{})
+		return err
+	}
+	if !metaV1.IsControlledBy(existing, set) {
+		return fmt.Errorf("HelixSaga %s already exists and is not managed by the HelixSagaSet", desired.Name)
+	}
+	keepManagedFields(existing, desired)
+	if existing.Labels[HelixSagaSetLabel] == set.Name && apiequality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+		return nil
+	}
+	hs := existing.DeepCopy()
+	if hs.Labels == nil {
+		hs.Labels = make(map[string]string, 0)
+	}
+	hs.Labels[HelixSagaSetLabel] = set.Name
+	hs.Spec = desired.Spec
+	klog.Infof("HelixSagaSet:%s/%s updates HelixSaga:%s", set.Namespace, set.Name, desired.Name)
+	_, err = clientSet.NevercaseV1().HelixSagas(hs.Namespace).Update(ctx, hs, metaV1.UpdateOptions{})
+	return err
+}
+
+// SyncHelixSagaSet generates the HelixSagas of the items of the HelixSagaSet from its HelixSagaTemplate,
+// and the HelixSagas of the items which have been removed would be deleted
+func (c *controller) SyncHelixSagaSet(obj interface{}, clientObj interface{}, ks k8sCoreV1.KubernetesResource, recorder record.EventRecorder) error {
+	set := obj.(*helixSagaV1.HelixSagaSet)
+	clientSet := clientObj.(helixSagaClientSet.Interface)
+	status := helixSagaV1.HelixSagaSetStatus{
+		ObservedGeneration: set.Generation,
+		HelixSagas:         make([]string, 0, len(set.Spec.Items)),
+	}
+	messages := make([]string, 0)
+	template, err := c.templateLister.Get(set.Spec.TemplateName)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// the HelixSagaSet would be synced again once the HelixSagaTemplate has been created
+		messages = append(messages, fmt.Sprintf("HelixSagaTemplate %s was not found", set.Spec.TemplateName))
+		status.HelixSagas = set.Status.HelixSagas
+	} else {
+		for i := range set.Spec.Items {
+			item := &set.Spec.Items[i]
+			hs, err := NewHelixSaga(set, template, item)
+			if err == nil {
+				err = c.syncHelixSaga(clientSet, set, hs)
+			}
+			if err != nil {
+				klog.V(2).Infof("HelixSagaSet:%s/%s item:%s err:%v", set.Namespace, set.Name, item.Name, err)
+				messages = append(messages, fmt.Sprintf("%s: %v", item.Name, err))
+				continue
+			}
+			status.HelixSagas = append(status.HelixSagas, item.Name)
+		}
+		if err = c.deleteRemovedHelixSagas(clientSet, set); err != nil {
+			klog.V(2).Info(err)
+			messages = append(messages, err.Error())
+		}
+	}
+	status.Message = strings.Join(messages, "; ")
+	if status.Message != "" {
+		recorder.Eventf(set, coreV1.EventTypeWarning, HelixSagaSetFailed, MessageHelixSagaSetFailed, set.Name, status.Message)
+	}
+	if !apiequality.Semantic.DeepEqual(set.Status, status) {
+		setCopy := set.DeepCopy()
+		setCopy.Status = status
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+		_, err = clientSet.NevercaseV1().HelixSagaSets(set.Namespace).Update(ctx, setCopy, metaV1.UpdateOptions{})
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	recorder.Event(set, coreV1.EventTypeNormal, SuccessSynced, MessageResourceSynced)
+	return nil
+}
+
+// deleteRemovedHelixSagas deletes the HelixSagas owned by the HelixSagaSet whose items have been removed
+func (c *controller) deleteRemovedHelixSagas(clientSet helixSagaClientSet.Interface, set *helixSagaV1.HelixSagaSet) error {
+	list, err := c.lister.HelixSagas(set.Namespace).List(labels.SelectorFromSet(map[string]string{HelixSagaSetLabel: set.Name}))
+	if err != nil {
+		return err
+	}
+	items := make(map[string]bool, len(set.Spec.Items))
+	for _, v := range set.Spec.Items {
+		items[v.Name] = true
+	}
+	for _, hs := range list {
+		if items[hs.Name] || !metaV1.IsControlledBy(hs, set) {
+			continue
+		}
+		klog.Infof("HelixSagaSet:%s/%s deletes HelixSaga:%s whose item has been removed", set.Namespace, set.Name, hs.Name)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+		err = clientSet.NevercaseV1().HelixSagas(hs.Namespace).Delete(ctx, hs.Name, metaV1.DeleteOptions{})
+		cancel()
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *controller) CompareHelixSagaSetVersion(old, new interface{}) bool {
+	return new.(*helixSagaV1.HelixSagaSet).ResourceVersion == old.(*helixSagaV1.HelixSagaSet).ResourceVersion
+}
+
+func (c *controller) GetHelixSagaSet(_ interface{}, nameSpace, ownerRefName string) (obj interface{}, err error) {
+	return c.setLister.HelixSagaSets(nameSpace).Get(ownerRefName)
+}
+
+// SyncHelixSagaSetStatus does nothing, since no Deployment or StatefulSet was owned by a HelixSagaSet
+func (c *controller) SyncHelixSagaSetStatus(_ interface{}, _ interface{}, _ k8sCoreV1.KubernetesResource, _ record.EventRecorder) error {
+	return nil
+}
+
+// helixSagaSetHandlers enqueues the HelixSagaSets whose HelixSagaTemplate or generated HelixSagas have been changed,
+// so that the HelixSagas would be kept in sync with the HelixSagaSets
+func (c *controller) helixSagaSetHandlers(enqueue func(obj interface{})) (templateHandler, helixSagaHandler cache.ResourceEventHandler) {
+	onTemplate := func(obj interface{}) {
+		if t, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = t.Obj
+		}
+		template, ok := obj.(*helixSagaV1.HelixSagaTemplate)
+		if !ok {
+			return
+		}
+		list, err := c.setLister.List(labels.Everything())
+		if err != nil {
+			klog.V(2).Info(err)
+			return
+		}
+		for _, set := range list {
+			if set.Spec.TemplateName == template.Name {
+				enqueue(set)
+			}
+		}
+	}
+	onHelixSaga := func(obj interface{}) {
+		if t, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = t.Obj
+		}
+		hs, ok := obj.(*helixSagaV1.HelixSaga)
+		if !ok {
+			return
+		}
+		ref := metaV1.GetControllerOf(hs)
+		if ref == nil || ref.Kind != HelixSagaSetKindName {
+			return
+		}
+		set, err := c.setLister.HelixSagaSets(hs.Namespace).Get(ref.Name)
+		if err != nil || set.UID != ref.UID {
+			return
+		}
+		enqueue(set)
+	}
+	templateHandler = cache.ResourceEventHandlerFuncs{
+		AddFunc: onTemplate,
+		UpdateFunc: func(old, new interface{}) {
+			if old.(*helixSagaV1.HelixSagaTemplate).ResourceVersion != new.(*helixSagaV1.HelixSagaTemplate).ResourceVersion {
+				onTemplate(new)
+			}
+		},
+		DeleteFunc: onTemplate,
+	}
+	helixSagaHandler = cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			if old.(*helixSagaV1.HelixSaga).ResourceVersion != new.(*helixSagaV1.HelixSaga).ResourceVersion {
+				onHelixSaga(new)
+			}
+		},
+		DeleteFunc: onHelixSaga,
+	}
+	return templateHandler, helixSagaHandler
+}
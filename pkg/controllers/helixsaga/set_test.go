@@ -0,0 +1,173 @@
+package helixsaga
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaFake "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/fake"
+	listers "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/listers/helixsaga/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestExpandParameters(t *testing.T) {
+	spec := &helixSagaV1.HelixSagaSpec{
+		Defaults: &helixSagaV1.HelixSagaAppDefaults{
+			Image: "harbor.domain.com/helix-saga/go-all:$(tag)",
+			Env:   []coreV1.EnvVar{{Name: "ZONE", Value: "$(zone)"}},
+		},
+		Applications: []helixSagaV1.HelixSagaApp{
+			{Spec: helixSagaV1.HelixSagaAppSpec{Name: "$(name)-game", NodeSelector: map[string]string{"$(zone)": "$(zone)"}}},
+		},
+	}
+	tests := []struct {
+		name       string
+		parameters map[string]string
+		wantErr    string
+	}{
+		{
+			name:       "TestExpandParameters_all",
+			parameters: map[string]string{"tag": "1.5.0", "zone": "cn1", "name": "hs-cn1"},
+		},
+		{
+			name:       "TestExpandParameters_missing",
+			parameters: map[string]string{"name": "hs-cn1"},
+			wantErr:    "parameters tag,zone were not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandParameters(spec, tt.parameters)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ExpandParameters() err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Defaults.Image != "harbor.domain.com/helix-saga/go-all:1.5.0" || got.Defaults.Env[0].Value != "cn1" {
+				t.Errorf("ExpandParameters() defaults = %+v", got.Defaults)
+			}
+			// the keys of the maps would be kept
+			app := got.Applications[0].Spec
+			if app.Name != "hs-cn1-game" || app.NodeSelector["$(zone)"] != "cn1" {
+				t.Errorf("ExpandParameters() app = %+v", app)
+			}
+			if spec.Defaults.Image != "harbor.domain.com/helix-saga/go-all:$(tag)" {
+				t.Errorf("ExpandParameters() modified the spec: %+v", spec.Defaults)
+			}
+		})
+	}
+}
+
+func TestSyncHelixSagaSet(t *testing.T) {
+	template := &helixSagaV1.HelixSagaTemplate{
+		ObjectMeta: metaV1.ObjectMeta{Name: "helix-saga"},
+		Spec: helixSagaV1.HelixSagaTemplateSpec{
+			Parameters: map[string]string{"tag": "latest"},
+			Template: helixSagaV1.HelixSagaSpec{
+				Defaults: &helixSagaV1.HelixSagaAppDefaults{
+					Image: "harbor.domain.com/helix-saga/go-all:$(tag)",
+					Env:   []coreV1.EnvVar{{Name: "ZONE", Value: "$(zone)"}},
+				},
+				Applications: []helixSagaV1.HelixSagaApp{
+					{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game"}},
+				},
+			},
+		},
+	}
+	set := &helixSagaV1.HelixSagaSet{
+		ObjectMeta: metaV1.ObjectMeta{Name: "cn", Namespace: "default", UID: "set-uid", Generation: 2},
+		Spec: helixSagaV1.HelixSagaSetSpec{
+			TemplateName: "helix-saga",
+			Items: []helixSagaV1.HelixSagaSetItem{
+				{Name: "hs-cn1", Parameters: map[string]string{"zone": "1"}},
+				{Name: "hs-cn2", Parameters: map[string]string{"zone": "2", "tag": "1.5.0"}},
+				{Name: "hs-cn3", Parameters: map[string]string{"zone": "3"}},
+			},
+		},
+	}
+	// hs-cn1 has been scaled down by an in-flight image update
+	zero := int32(0)
+	cn1, err := NewHelixSaga(set, template, &set.Spec.Items[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	cn1.Spec.Applications[0].Spec.Replicas = &zero
+	cn1.Spec.Applications[0].Status.ImageUpdate = &helixSagaV1.ImageUpdateStatus{Image: "harbor.domain.com/helix-saga/go-all:latest"}
+	removed, err := NewHelixSaga(set, template, &helixSagaV1.HelixSagaSetItem{Name: "hs-removed", Parameters: map[string]string{"zone": "0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign := &helixSagaV1.HelixSaga{ObjectMeta: metaV1.ObjectMeta{Name: "hs-cn3", Namespace: "default"}}
+
+	hsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	setIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	templateIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	objects := []runtime.Object{set}
+	for _, v := range []*helixSagaV1.HelixSaga{cn1, removed, foreign} {
+		if err = hsIndexer.Add(v); err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, v)
+	}
+	if err = setIndexer.Add(set); err != nil {
+		t.Fatal(err)
+	}
+	if err = templateIndexer.Add(template); err != nil {
+		t.Fatal(err)
+	}
+	client := helixSagaFake.NewSimpleClientset(objects...)
+	c := &controller{
+		clientSet:      client,
+		lister:         listers.NewHelixSagaLister(hsIndexer),
+		setLister:      listers.NewHelixSagaSetLister(setIndexer),
+		templateLister: listers.NewHelixSagaTemplateLister(templateIndexer),
+	}
+	if err = c.SyncHelixSagaSet(set, client, nil, record.NewFakeRecorder(10)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	hss := client.NevercaseV1().HelixSagas("default")
+	got, err := hss.Get(ctx, "hs-cn1", metaV1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := got.Spec.Applications[0].Spec.Replicas; r == nil || *r != 0 {
+		t.Errorf("hs-cn1 replicas of the in-flight update = %v, want 0", r)
+	}
+	got, err = hss.Get(ctx, "hs-cn2", metaV1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.Defaults.Image != "harbor.domain.com/helix-saga/go-all:1.5.0" || got.Spec.Defaults.Env[0].Value != "2" {
+		t.Errorf("hs-cn2 defaults = %+v", got.Spec.Defaults)
+	}
+	if !metaV1.IsControlledBy(got, set) || got.Labels[HelixSagaSetLabel] != "cn" {
+		t.Errorf("hs-cn2 metadata = %+v", got.ObjectMeta)
+	}
+	if _, err = hss.Get(ctx, "hs-removed", metaV1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("hs-removed err = %v, want NotFound", err)
+	}
+	// the HelixSaga which was not owned by the HelixSagaSet would be kept
+	if got, err = hss.Get(ctx, "hs-cn3", metaV1.GetOptions{}); err != nil || metaV1.GetControllerOf(got) != nil {
+		t.Errorf("hs-cn3 = %+v err = %v", got, err)
+	}
+	res, err := client.NevercaseV1().HelixSagaSets("default").Get(ctx, "cn", metaV1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(res.Status.HelixSagas, ",") != "hs-cn1,hs-cn2" || res.Status.ObservedGeneration != 2 ||
+		!strings.Contains(res.Status.Message, "hs-cn3") {
+		t.Errorf("HelixSagaSet status = %+v", res.Status)
+	}
+}
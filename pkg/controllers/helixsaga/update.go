@@ -0,0 +1,86 @@
+package helixsaga
+
+import (
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// GetImagePullPolicy returns the ImagePullPolicy of the app, defaults to PullAlways
+func GetImagePullPolicy(spec *helixSagaV1.HelixSagaAppSpec) coreV1.PullPolicy {
+	if spec.ImagePullPolicy == "" {
+		return coreV1.PullAlways
+	}
+	return spec.ImagePullPolicy
+}
+
+// GetUpdateTrigger returns the UpdateTrigger of the app, defaults to UpdateTriggerScaleToZero
+func GetUpdateTrigger(spec *helixSagaV1.HelixSagaAppSpec) helixSagaV1.UpdateTrigger {
+	if spec.UpdateTrigger == "" {
+		return helixSagaV1.UpdateTriggerScaleToZero
+	}
+	return spec.UpdateTrigger
+}
+
+// IsDigestPinned reports whether the app would be deployed with the image@sha256:... of the tag
+func IsDigestPinned(spec *helixSagaV1.HelixSagaAppSpec) bool {
+	return spec.PinDigest || GetUpdateTrigger(spec) == helixSagaV1.UpdateTriggerDigestPin
+}
+
+// IsScaledToZero reports whether the app would be scaled down to zero and back up again on a new digest
+func IsScaledToZero(spec *helixSagaV1.HelixSagaAppSpec) bool {
+	return spec.WatchPolicy == helixSagaV1.WatchPolicyAuto && GetUpdateTrigger(spec) == helixSagaV1.UpdateTriggerScaleToZero
+}
+
+// IsImagePullUnsafe reports whether the app might keep running the stale contents of the tag after a new push.
+// A mutable tag would never be pulled again by the nodes which have cached it unless the ImagePullPolicy was Always,
+// except that a new image reference was deployed by the digestPin.
+func IsImagePullUnsafe(spec *helixSagaV1.HelixSagaAppSpec) bool {
+	if spec.WatchPolicy != helixSagaV1.WatchPolicyAuto || GetImagePullPolicy(spec) == coreV1.PullAlways {
+		return false
+	}
+	return !IsDigestPinned(spec) && spec.RollbackTo == ""
+}
+
+// GetPodTemplateAnnotations returns the annotations of the pod template of the app.
+// The digest recorded by the UpdateTrigger restart would be set, so that the pods would be rolled once it has been changed.
+func GetPodTemplateAnnotations(hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) map[string]string {
+	if GetUpdateTrigger(spec) != helixSagaV1.UpdateTriggerRestart || IsDigestPinned(spec) {
+		return nil
+	}
+	for _, v := range hs.Spec.Applications {
+		if v.Spec.Name != spec.Name || v.Status.CurrentImage.Digest == "" {
+			continue
+		}
+		return map[string]string{
+			ImageDigestAnnotation: v.Status.CurrentImage.Digest,
+		}
+	}
+	return nil
+}
+
+// GetPodTemplateLabels returns the labels of the pod template, which were the labels of the selector with the TemplateLabel
+func GetPodTemplateLabels(labels map[string]string, template helixSagaV1.TemplateType) map[string]string {
+	res := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		res[k] = v
+	}
+	res[TemplateLabel] = string(template)
+	return res
+}
+
+// comparePodTemplate returns true if the pod template has to be updated
+func comparePodTemplate(original, updated *coreV1.PodTemplateSpec) bool {
+	if len(original.Spec.Containers) == 0 || len(updated.Spec.Containers) == 0 {
+		return true
+	}
+	if updated.Spec.Containers[0].Image != original.Spec.Containers[0].Image {
+		return true
+	}
+	if updated.Spec.Containers[0].ImagePullPolicy != original.Spec.Containers[0].ImagePullPolicy {
+		return true
+	}
+	if updated.Annotations[ImageDigestAnnotation] != original.Annotations[ImageDigestAnnotation] {
+		return true
+	}
+	return false
+}
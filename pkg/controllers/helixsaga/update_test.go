@@ -0,0 +1,104 @@
+package helixsaga
+
+import (
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestIsImagePullUnsafe(t *testing.T) {
+	tests := []struct {
+		name string
+		spec helixSagaV1.HelixSagaAppSpec
+		want bool
+	}{
+		{
+			name: "TestIsImagePullUnsafe_default",
+			spec: helixSagaV1.HelixSagaAppSpec{WatchPolicy: helixSagaV1.WatchPolicyAuto},
+			want: false,
+		},
+		{
+			name: "TestIsImagePullUnsafe_manual",
+			spec: helixSagaV1.HelixSagaAppSpec{WatchPolicy: helixSagaV1.WatchPolicyManual, ImagePullPolicy: coreV1.PullIfNotPresent},
+			want: false,
+		},
+		{
+			name: "TestIsImagePullUnsafe_restart",
+			spec: helixSagaV1.HelixSagaAppSpec{WatchPolicy: helixSagaV1.WatchPolicyAuto, ImagePullPolicy: coreV1.PullIfNotPresent, UpdateTrigger: helixSagaV1.UpdateTriggerRestart},
+			want: true,
+		},
+		{
+			name: "TestIsImagePullUnsafe_digestPin",
+			spec: helixSagaV1.HelixSagaAppSpec{WatchPolicy: helixSagaV1.WatchPolicyAuto, ImagePullPolicy: coreV1.PullIfNotPresent, UpdateTrigger: helixSagaV1.UpdateTriggerDigestPin},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsImagePullUnsafe(&tt.spec); got != tt.want {
+				t.Errorf("IsImagePullUnsafe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDeploymentUpdateTrigger(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	status := helixSagaV1.HelixSagaAppStatus{CurrentImage: helixSagaV1.ImageRecord{Image: image, Digest: "sha256:aaa"}}
+	tests := []struct {
+		name           string
+		spec           helixSagaV1.HelixSagaAppSpec
+		wantImage      string
+		wantPolicy     coreV1.PullPolicy
+		wantAnnotation string
+	}{
+		{
+			name:       "TestNewDeploymentUpdateTrigger_scaleToZero",
+			spec:       helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image},
+			wantImage:  image,
+			wantPolicy: coreV1.PullAlways,
+		},
+		{
+			name:           "TestNewDeploymentUpdateTrigger_restart",
+			spec:           helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, UpdateTrigger: helixSagaV1.UpdateTriggerRestart},
+			wantImage:      image,
+			wantPolicy:     coreV1.PullAlways,
+			wantAnnotation: "sha256:aaa",
+		},
+		{
+			name:       "TestNewDeploymentUpdateTrigger_digestPin",
+			spec:       helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, UpdateTrigger: helixSagaV1.UpdateTriggerDigestPin, ImagePullPolicy: coreV1.PullIfNotPresent},
+			wantImage:  image + "@sha256:aaa",
+			wantPolicy: coreV1.PullIfNotPresent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &helixSagaV1.HelixSaga{
+				Spec: helixSagaV1.HelixSagaSpec{
+					Applications: []helixSagaV1.HelixSagaApp{{Spec: tt.spec, Status: status}},
+				},
+			}
+			dp := NewDeployment(hs, &tt.spec)
+			c := dp.Spec.Template.Spec.Containers[0]
+			if c.Image != tt.wantImage {
+				t.Errorf("NewDeployment() image = %v, want %v", c.Image, tt.wantImage)
+			}
+			if c.ImagePullPolicy != tt.wantPolicy {
+				t.Errorf("NewDeployment() imagePullPolicy = %v, want %v", c.ImagePullPolicy, tt.wantPolicy)
+			}
+			if got := dp.Spec.Template.Annotations[ImageDigestAnnotation]; got != tt.wantAnnotation {
+				t.Errorf("NewDeployment() annotation = %v, want %v", got, tt.wantAnnotation)
+			}
+			original := dp.DeepCopy()
+			original.Spec.Template.Annotations = nil
+			original.Spec.Template.Spec.Containers[0].Image = image
+			original.Spec.Template.Spec.Containers[0].ImagePullPolicy = coreV1.PullAlways
+			changed := tt.wantAnnotation != "" || tt.wantImage != image || tt.wantPolicy != coreV1.PullAlways
+			if got := compareDeployment(original, dp); got != changed {
+				t.Errorf("compareDeployment() = %v, want %v", got, changed)
+			}
+		})
+	}
+}
@@ -3,174 +3,605 @@ package helixsaga
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
 	"sync"
 	"time"
 
 	helixsagav1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
 	helixSagaClientSet "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	"github.com/Shanghai-Lunara/helixsaga-operator/pkg/registrywebhook"
 	harbor "github.com/nevercase/harbor-api"
+	"github.com/nevercase/k8s-controller-custom-resource/pkg/env"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 )
 
-// Watchers was a set which contains all the watchers
+// Watchers was a set which contains all the watchers.
+// There is only one Watcher for an image, and it dispatches the image changes to all the subscribed HelixSagas.
 type Watchers struct {
-	mu        sync.Mutex
+	mu sync.Mutex
+	// items are the Watchers keyed by the image
 	items     map[string]*Watcher
-	lockers   map[string]sync.Mutex
+	lockers   map[string]*sync.Mutex
 	harborHub harbor.HubInterface
+	// registryWatch was false if the image updates were only triggered by Notify
+	registryWatch bool
+	// applying are the digests of the image updates in progress keyed by the names of the WatchOptions
+	applying map[string]string
+	// hooks are the namespaced names of the hook Jobs which were being run by the Sync
+	hooks map[string]bool
 }
 
 // NewWatchers returns the pointer of the Watchers
 func NewWatchers(c []harbor.Config) *Watchers {
 	return &Watchers{
-		items:     make(map[string]*Watcher, 0),
-		harborHub: harbor.NewHub(c),
-		lockers:   make(map[string]sync.Mutex, 0),
+		items:         make(map[string]*Watcher, 0),
+		harborHub:     harbor.NewHub(c),
+		lockers:       make(map[string]*sync.Mutex, 0),
+		registryWatch: true,
+		applying:      make(map[string]string, 0),
+		hooks:         make(map[string]bool, 0),
 	}
 }
 
-// Subscribe adds a new Watcher into the Watchers
+// DisableRegistryWatch stops the Watchers from connecting to the registries.
+// The subscriptions would be kept, and the image updates would only be triggered by Notify.
+func (ws *Watchers) DisableRegistryWatch() {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.registryWatch = false
+}
+
+// Notify triggers the image update of the subscriptions which were watching the pushed image.
+// It returns the number of the triggered subscriptions.
+func (ws *Watchers) Notify(e registrywebhook.PushEvent) int {
+	ws.mu.Lock()
+	matched := make([]*WatchOption, 0)
+	for _, w := range ws.items {
+		info := w.info
+		if info.Project != e.Project || info.Repository != e.Repository || info.Tag != e.Tag {
+			continue
+		}
+		if e.Domain != "" && info.Domain != e.Domain {
+			continue
+		}
+		matched = append(matched, w.Subscribers()...)
+	}
+	ws.mu.Unlock()
+	t := harbor.Option{
+		APIVersion: "v1",
+		Project:    e.Project,
+		Repository: e.Repository,
+		Tag:        e.Tag,
+		Sha256:     e.Digest,
+	}
+	for _, wo := range matched {
+		go ws.handleImageChange(wo, t)
+	}
+	return len(matched)
+}
+
+// Subscribe adds the WatchOption into the Watcher of its image, the Watcher would be started by the first subscription.
+// The present subscription of the same HelixSaga would be replaced, so that the changes would be handled with its latest spec.
 func (ws *Watchers) Subscribe(wo *WatchOption) error {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
-	name := wo.Name()
-	if _, ok := ws.items[name]; !ok {
-		w, err := NewWatcher(context.Background(), ws.harborHub, wo)
-		if err != nil {
-			klog.V(2).Info(err)
-			return err
+	t, ok := ws.items[wo.Image]
+	if ok && !t.Failed(WatcherRestartDelay) {
+		t.Add(wo)
+		return nil
+	}
+	w := NewWatcher(context.Background(), ws.harborHub, wo.Image)
+	if ok {
+		klog.Infof("Watcher name:%s restarts after failed", w.name)
+		for _, v := range t.Subscribers() {
+			w.Add(v)
+		}
+	}
+	w.Add(wo)
+	w.handler = func(t harbor.Option) {
+		// dispatch the image change to all the subscribed HelixSagas without waiting for them,
+		// a HelixSaga without running pods would never hold up the others
+		for _, v := range w.Subscribers() {
+			go ws.handleImageChange(v, t)
 		}
-		ws.items[name] = w
+	}
+	ws.items[wo.Image] = w
+	if ws.registryWatch {
 		go ws.Loop(w)
 	}
-	// update WatchOption
-	//ws.items[name].opt = wo
 	return nil
 }
 
-// UnSubscribe removes the specific Watcher from the Watchers
+// UnSubscribe removes the subscription of the HelixSaga from the Watcher of the image,
+// and the Watcher would be closed after the last subscription has been removed
 func (ws *Watchers) UnSubscribe(wo *WatchOption) {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
-	name := wo.Name()
-	if t, ok := ws.items[name]; ok {
-		t.Close()
+	t, ok := ws.items[wo.Image]
+	if !ok {
+		return
+	}
+	if t.Remove(wo.Name()) > 0 {
+		return
 	}
-	delete(ws.items, name)
+	klog.Infof("Watcher name:%s has no subscriptions", t.name)
+	t.Close()
+	delete(ws.items, wo.Image)
 }
 
-func (ws *Watchers) Locker(crdName string) sync.Mutex {
+// Locker returns the lock of the HelixSaga, which serializes the image updates of it
+func (ws *Watchers) Locker(namespace, crdName string) *sync.Mutex {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
-	t, ok := ws.lockers[crdName]
+	name := fmt.Sprintf("%s/%s", namespace, crdName)
+	t, ok := ws.lockers[name]
 	if !ok {
-		t = sync.Mutex{}
-		ws.lockers[crdName] = t
+		t = &sync.Mutex{}
+		ws.lockers[name] = t
 	}
 	return t
 }
 
+// Loop runs the Watcher until it has been closed or failed.
+// A failed Watcher would be kept, and it would be restarted by the Subscribe after WatcherRestartDelay.
+func (ws *Watchers) Loop(w *Watcher) {
+	w.Run()
+}
+
+func (ws *Watchers) handleImageChange(wo *WatchOption, t harbor.Option) {
+	// handle the message which was received from the watch channel
+	image, err := wo.GetPodImage()
+	if err != nil {
+		klog.V(2).Info(err)
+		return
+	}
+	hash := harbor.GetHashFromDockerImageId(image)
+	if hash == "" {
+		klog.V(2).Infof("image:%s hash:%s", image, hash)
+		return
+	}
+	if hash == t.Sha256 {
+		return
+	}
+	if wo.HelixSagaClient != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+		hs, err := wo.HelixSagaClient.NevercaseV1().HelixSagas(wo.Namespace).Get(ctx, wo.OperatorName, metav1.GetOptions{})
+		cancel()
+		if err != nil {
+			klog.V(2).Info(err)
+			return
+		}
+		// queue the update until the UpdateWindow opens
+		if pending, next, reason := CheckUpdateWindow(hs, wo.Image, time.Now()); pending {
+			klog.Infof("HelixSaga:%s image:%s digest:%s is pending: %s", wo.OperatorName, wo.Image, t.Sha256, reason)
+			if err = QueueImageUpdate(wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, t.Sha256, next, reason); err != nil {
+				klog.V(2).Info(err)
+				return
+			}
+			if wo.Recorder != nil {
+				wo.Recorder.Eventf(hs, corev1.EventTypeNormal, ImageUpdatePending, MessageImageUpdatePending, wo.Image, t.Sha256, reason)
+			}
+			return
+		}
+	}
+	ws.applyImageChange(wo, t.Sha256)
+}
+
+// applyImageChange rolls the apps of the HelixSaga which were using the image to the new digest,
+// and the pending update of the image would be cleared.
+// The update would be queued while any PreUpdate hook or another update of the image was running,
+// and it would be applied again by the SyncPendingUpdates.
+func (ws *Watchers) applyImageChange(wo *WatchOption, digest string) {
+	name := wo.Name()
+	ws.mu.Lock()
+	if applying, ok := ws.applying[name]; ok {
+		ws.mu.Unlock()
+		klog.Infof("HelixSaga:%s image:%s the update of digest:%s is in progress", wo.OperatorName, wo.Image, applying)
+		if applying == digest {
+			return
+		}
+		// the registry would not send the newer digest again
+		reason := fmt.Sprintf("waiting for the update of digest %s in progress", applying)
+		if err := QueueImageUpdate(wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, digest, time.Time{}, reason); err != nil {
+			klog.V(2).Info(err)
+		}
+		return
+	}
+	ws.applying[name] = digest
+	ws.mu.Unlock()
+	defer func() {
+		ws.mu.Lock()
+		delete(ws.applying, name)
+		ws.mu.Unlock()
+	}()
+	// the PreUpdate hooks were run without holding the locker, the update would be queued until they have been succeeded,
+	// and it would be aborted if any of them failed
+	reason, err := CheckPreUpdateHooks(context.Background(), wo, digest)
+	if err != nil {
+		wo.Eventf(corev1.EventTypeWarning, ImageUpdateFailed, MessageImageUpdateFailed, wo.Image, digest, err)
+		if err = ClearPendingUpdate(wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, digest); err != nil {
+			klog.V(2).Info(err)
+		}
+		return
+	}
+	if reason != "" {
+		klog.Infof("HelixSaga:%s image:%s digest:%s is pending: %s", wo.OperatorName, wo.Image, digest, reason)
+		if err = QueueImageUpdate(wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, digest, time.Time{}, reason); err != nil {
+			klog.V(2).Info(err)
+		}
+		return
+	}
+	klog.Infof("HelixSaga:%s get the locker", wo.OperatorName)
+	locker := ws.Locker(wo.Namespace, wo.OperatorName)
+	klog.Infof("HelixSaga:%s start locking", wo.OperatorName)
+	locker.Lock()
+	defer locker.Unlock()
+	// the update would not be canceled by the WatchOption, since the apps might have been scaled down
+	ctx, cancel := context.WithTimeout(context.Background(), PatchTimeout)
+	defer cancel()
+	// persist the update, it would be resumed or rolled back if the operator was restarted before it has been finished
+	if err = BeginImageUpdate(wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, digest); err != nil {
+		wo.Eventf(corev1.EventTypeWarning, ImageUpdateFailed, MessageImageUpdateFailed, wo.Image, digest, err)
+		return
+	}
+	replica, err := RetryPatchHelixSaga(ctx, wo.K8sClientSet, wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, make(map[string]int32, 0))
+	if err != nil {
+		wo.Eventf(corev1.EventTypeWarning, ImageUpdateFailed, MessageImageUpdateFailed, wo.Image, digest, err)
+		if err = ClearImageUpdate(wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image); err != nil {
+			klog.V(2).Info(err)
+		}
+		return
+	}
+	// record the new digest before the apps were scaled up again, the apps with the UpdateTrigger
+	// digestPin or restart would be rolled by it
+	if err = RecordImageDigest(wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, digest); err != nil {
+		klog.V(2).Info(err)
+	}
+	if _, err = RetryPatchHelixSaga(ctx, wo.K8sClientSet, wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, replica); err != nil {
+		wo.Eventf(corev1.EventTypeWarning, ImageUpdateFailed, MessageImageUpdateFailed, wo.Image, digest, err)
+		if len(replica) == 0 {
+			return
+		}
+		// never leave the apps scaled down, restore the replicas without waiting for the pods
+		restoreCtx, restoreCancel := context.WithTimeout(context.Background(), PatchRestoreTimeout)
+		defer restoreCancel()
+		if _, err = retryPatchHelixSaga(restoreCtx, wo.K8sClientSet, wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, replica, false); err != nil {
+			wo.Eventf(corev1.EventTypeWarning, ImageUpdateFailed, MessageImageUpdateScaledDown, wo.Image, replica, err)
+		}
+		return
+	}
+	if err = ClearPendingUpdate(wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, digest); err != nil {
+		klog.V(2).Info(err)
+	}
+	if err = ws.startPostUpdateHooks(wo, digest); err != nil {
+		klog.V(2).Info(err)
+	}
+}
+
+// DefaultWatcherBackoff is the backoff between the reconnections of a Watcher.
+// The Steps was the retry budget, which would be refilled once a watch has kept stable for WatcherStableDuration.
+var DefaultWatcherBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.2,
+	Steps:    10,
+	Cap:      time.Minute * 5,
+}
+
+// WatcherStableDuration is the minimum lifetime of a watch which was regarded as a stable one
+var WatcherStableDuration = time.Minute
+
+// PodImageTimeout is the deadline of looking up the running pods of the image after a change has been received,
+// e.g. the HelixSaga might have been scaled to zero or kept crashing
+var PodImageTimeout = time.Minute
+
+// WatcherRestartDelay is the minimum duration before a failed Watcher could be subscribed again
+var WatcherRestartDelay = time.Minute * 10
+
+var errWatchClosed = fmt.Errorf("the result channel of the registry watch was closed")
+
+// Watcher watches an image in the registry. It was driven by Run as a state machine:
+// Connecting -> Watching -> Backoff -> Connecting ... until the retry budget has been used up, then Failed.
 type Watcher struct {
 	harborHub harbor.HubInterface
 
 	name        string
-	opt         *WatchOption
+	info        *ImageInfo
 	watchResult watch.Interface
 
+	mu sync.Mutex
+	// subscribers are the WatchOptions of the HelixSagas which were using the image, keyed by WatchOption.Name
+	subscribers    map[string]*WatchOption
+	state          helixsagav1.ImageWatchState
+	failedAt       time.Time
+	retries        int32
+	backoff        wait.Backoff
+	stableDuration time.Duration
+	// handler would be called with every message received from the registry
+	handler func(t harbor.Option)
+	// reporter would be called after the state of the Watcher has been changed
+	reporter func(status helixsagav1.ImageWatchStatus)
+
 	once   sync.Once
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
-func NewWatcher(ctx context.Context, hi harbor.HubInterface, wo *WatchOption) (*Watcher, error) {
-	wi, err := WatchHarborImage(hi, wo)
+// NewWatcher returns a Watcher of the image without any subscriptions
+func NewWatcher(ctx context.Context, hi harbor.HubInterface, image string) *Watcher {
+	subCtx, cancel := context.WithCancel(ctx)
+	w := &Watcher{
+		harborHub:      hi,
+		name:           image,
+		info:           ConvertImageToObject(image),
+		subscribers:    make(map[string]*WatchOption, 0),
+		state:          helixsagav1.ImageWatchStateConnecting,
+		backoff:        DefaultWatcherBackoff,
+		stableDuration: WatcherStableDuration,
+		handler:        func(t harbor.Option) {},
+		ctx:            subCtx,
+		cancel:         cancel,
+	}
+	w.reporter = w.report
+	return w
+}
+
+// Add subscribes the image changes for the HelixSaga of the WatchOption, the present one would be replaced.
+// The replaced one was not closed, since it might be still handling a change.
+// It returns the number of the subscriptions.
+func (w *Watcher) Add(wo *WatchOption) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers[wo.Name()] = wo
+	return len(w.subscribers)
+}
+
+// Remove closes and removes the subscription. It returns the number of the remaining subscriptions.
+func (w *Watcher) Remove(name string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t, ok := w.subscribers[name]; ok {
+		t.Close()
+		delete(w.subscribers, name)
+	}
+	return len(w.subscribers)
+}
+
+// Subscribers returns the WatchOptions of all the subscriptions
+func (w *Watcher) Subscribers() []*WatchOption {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	res := make([]*WatchOption, 0, len(w.subscribers))
+	for _, v := range w.subscribers {
+		res = append(res, v)
+	}
+	return res
+}
+
+// Run drives the Watcher until it has been closed or the retry budget has been used up
+func (w *Watcher) Run() {
+	var (
+		relogin = true
+		lastErr error
+	)
+	for {
+		switch w.getState() {
+		case helixsagav1.ImageWatchStateConnecting:
+			wi, err := w.connect(relogin)
+			if err != nil {
+				klog.V(2).Infof("Watcher connect name:%s retries:%d err:%v", w.name, w.retries, err)
+				lastErr = err
+				// the session might have been expired, login again before the next connection
+				relogin = isAuthError(err)
+				w.setState(helixsagav1.ImageWatchStateBackoff, lastErr)
+				continue
+			}
+			relogin = false
+			w.watchResult = wi
+			w.setState(helixsagav1.ImageWatchStateWatching, nil)
+		case helixsagav1.ImageWatchStateWatching:
+			started := time.Now()
+			err := w.watch()
+			w.watchResult.Stop()
+			if err == nil {
+				// the Watcher has been closed
+				return
+			}
+			if time.Since(started) >= w.stableDuration {
+				w.retries = 0
+			}
+			klog.V(2).Infof("Watcher ResultChan reconnect name:%s err:%v", w.name, err)
+			lastErr = err
+			w.setState(helixsagav1.ImageWatchStateBackoff, lastErr)
+		case helixsagav1.ImageWatchStateBackoff:
+			if w.retries >= int32(w.backoff.Steps) {
+				klog.Errorf("Watcher name:%s failed after %d retries, last err:%v", w.name, w.retries, lastErr)
+				w.setState(helixsagav1.ImageWatchStateFailed, lastErr)
+				// the subscriptions would be kept for the restart
+				w.cancel()
+				return
+			}
+			d := w.delay()
+			w.retries++
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(d):
+			}
+			w.mu.Lock()
+			w.state = helixsagav1.ImageWatchStateConnecting
+			w.mu.Unlock()
+		default:
+			return
+		}
+	}
+}
+
+// delay returns the exponential backoff of the present retry with jitter
+func (w *Watcher) delay() time.Duration {
+	d := float64(w.backoff.Duration) * math.Pow(w.backoff.Factor, float64(w.retries))
+	if w.backoff.Cap > 0 && d > float64(w.backoff.Cap) {
+		d = float64(w.backoff.Cap)
+	}
+	return wait.Jitter(time.Duration(d), w.backoff.Jitter)
+}
+
+func (w *Watcher) connect(relogin bool) (watch.Interface, error) {
+	select {
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	default:
+	}
+	hb, err := w.harborHub.Get(fmt.Sprintf("%s%s", harbor.HttpPrefix, w.info.Domain))
 	if err != nil {
-		klog.V(2).Info(err)
 		return nil, err
 	}
-	subCtx, cancel := context.WithCancel(ctx)
-	w := &Watcher{
-		harborHub:   hi,
-		name:        wo.Name(),
-		opt:         wo,
-		watchResult: wi,
-		ctx:         subCtx,
-		cancel:      cancel,
+	if relogin {
+		if err = hb.Login(); err != nil {
+			return nil, err
+		}
 	}
-	return w, nil
+	return hb.Watch(NewHarborOption(w.info))
 }
 
-func (ws *Watchers) Loop(w *Watcher) {
-	defer w.watchResult.Stop()
+// watch handles the messages until the result channel was closed or the Watcher was closed.
+// It returns nil only if the Watcher has been closed.
+func (w *Watcher) watch() error {
 	for {
 		select {
 		case <-w.ctx.Done():
-			return
-		case msg, isClose := <-w.watchResult.ResultChan():
+			return nil
+		case msg, ok := <-w.watchResult.ResultChan():
+			if !ok {
+				return errWatchClosed
+			}
 			klog.Info("Watcher Loop msg:", msg)
-			if !isClose {
-				// reWatch
-				tick := time.NewTicker(time.Millisecond * 200)
-				for {
-					select {
-					case <-w.ctx.Done():
-						return
-					case <-tick.C:
-						klog.V(2).Infof("Watcher ResultChan reconnect name:%s", w.name)
-						wi, err := WatchHarborImage(w.harborHub, w.opt)
-						if err != nil {
-							klog.V(2).Info(err)
-							continue
-						}
-						w.watchResult = wi
-						break
-					}
-				}
+			if msg.Type == watch.Error {
+				return fmt.Errorf("the registry watch returned an error: %v", msg.Object)
 			}
-			t := msg.Object.(harbor.Option)
-			// handle the message which was received from the watch channel
-			image, err := w.opt.GetPodImage()
-			if err != nil {
-				klog.V(2).Info(err)
-				// todo handle error
+			t, ok := msg.Object.(harbor.Option)
+			if !ok {
+				klog.V(2).Infof("Watcher name:%s unexpected object:%v", w.name, msg.Object)
 				continue
 			}
-			hash := harbor.GetHashFromDockerImageId(image)
-			if hash == "" {
-				klog.V(2).Infof("image:%s hash:%s", image, hash)
+			w.handler(t)
+		}
+	}
+}
+
+// Failed reports whether the Watcher has failed for longer than d
+func (w *Watcher) Failed(d time.Duration) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state == helixsagav1.ImageWatchStateFailed && time.Since(w.failedAt) >= d
+}
+
+func (w *Watcher) getState() helixsagav1.ImageWatchState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state
+}
+
+func (w *Watcher) setState(state helixsagav1.ImageWatchState, err error) {
+	w.mu.Lock()
+	w.state = state
+	if state == helixsagav1.ImageWatchStateFailed {
+		w.failedAt = time.Now()
+	}
+	w.mu.Unlock()
+	status := helixsagav1.ImageWatchStatus{
+		State:              state,
+		Retries:            w.retries,
+		RetryBudget:        int32(w.backoff.Steps),
+		LastTransitionTime: metav1.Now(),
+	}
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	w.reporter(status)
+}
+
+// report records the status into the apps of the subscribed HelixSagas which were using the image, and emits events
+func (w *Watcher) report(status helixsagav1.ImageWatchStatus) {
+	for _, wo := range w.Subscribers() {
+		if wo.Recorder != nil && wo.HelixSaga != nil {
+			switch status.State {
+			case helixsagav1.ImageWatchStateWatching:
+				wo.Recorder.Eventf(wo.HelixSaga, corev1.EventTypeNormal, ImageWatchStarted, MessageImageWatchStarted, wo.Image)
+			case helixsagav1.ImageWatchStateBackoff:
+				wo.Recorder.Eventf(wo.HelixSaga, corev1.EventTypeWarning, ImageWatchBackoff, MessageImageWatchBackoff, wo.Image, status.Retries, status.RetryBudget, status.LastError)
+			case helixsagav1.ImageWatchStateFailed:
+				wo.Recorder.Eventf(wo.HelixSaga, corev1.EventTypeWarning, ImageWatchFailed, MessageImageWatchFailed, wo.Image, status.Retries, status.LastError)
+			}
+		}
+		if wo.HelixSagaClient == nil {
+			continue
+		}
+		if err := updateImageWatchStatus(wo.HelixSagaClient, wo.Namespace, wo.OperatorName, wo.Image, status); err != nil {
+			klog.V(2).Info(err)
+		}
+	}
+}
+
+func updateImageWatchStatus(clientSet helixSagaClientSet.Interface, namespace, crdName, image string, status helixsagav1.ImageWatchStatus) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+		defer cancel()
+		hs, err := clientSet.NevercaseV1().HelixSagas(namespace).Get(ctx, crdName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		hs = hs.DeepCopy()
+		changed := false
+		for i, v := range GetAppSpecs(hs) {
+			if v.Image != image {
 				continue
 			}
-			if hash != t.Sha256 {
-				klog.Infof("HelixSaga:%s get the locker", w.opt.HelixSaga.Name)
-				t := ws.Locker(w.opt.HelixSaga.Name)
-				klog.Infof("HelixSaga:%s start locking", w.opt.HelixSaga.Name)
-				t.Lock()
-				var replica = make(map[string]int32, 0)
-				if replica, err = RetryPatchHelixSaga(w.opt.K8sClientSet, w.opt.HelixSagaClient, w.opt.HelixSaga.Namespace, w.opt.HelixSaga.Name, w.opt.Image, replica); err != nil {
-					klog.V(2).Info(err)
-					t.Unlock()
-					continue
-				}
-				if _, err = RetryPatchHelixSaga(w.opt.K8sClientSet, w.opt.HelixSagaClient, w.opt.HelixSaga.Namespace, w.opt.HelixSaga.Name, w.opt.Image, replica); err != nil {
-					klog.V(2).Info(err)
-					t.Unlock()
-					continue
-				}
-				t.Unlock()
+			t := hs.Spec.Applications[i].Status.ImageWatch
+			if t.State == status.State && t.Retries == status.Retries && t.LastError == status.LastError {
+				continue
 			}
+			hs.Spec.Applications[i].Status.ImageWatch = status
+			changed = true
+		}
+		if !changed {
+			return nil
 		}
+		_, err = clientSet.NevercaseV1().HelixSagas(namespace).Update(ctx, hs, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// isAuthError reports whether the error was caused by an expired or a rejected session of the registry
+func isAuthError(err error) bool {
+	if err == nil {
+		return false
 	}
+	s := strings.ToLower(err.Error())
+	for _, v := range []string{"401", "403", "unauthorized", "forbidden"} {
+		if strings.Contains(s, v) {
+			return true
+		}
+	}
+	return false
 }
 
+// Close stops the Watcher and closes all the subscriptions
 func (w *Watcher) Close() {
 	w.once.Do(func() {
 		w.cancel()
-		w.opt.Close()
+		for _, v := range w.Subscribers() {
+			v.Close()
+		}
 	})
 }
 
@@ -186,6 +617,7 @@ type WatchOption struct {
 
 	K8sClientSet    kubernetes.Interface
 	HelixSagaClient helixSagaClientSet.Interface
+	Recorder        record.EventRecorder
 	StatefulSet     *appsv1.StatefulSet
 	Deployment      *appsv1.Deployment
 	HelixSaga       *helixsagav1.HelixSaga
@@ -222,6 +654,8 @@ func (wo *WatchOption) Name() string {
 }
 
 func (wo *WatchOption) GetPodImage() (string, error) {
+	ctx, cancel := context.WithTimeout(wo.ctx, PodImageTimeout)
+	defer cancel()
 	tick := time.NewTicker(time.Millisecond * 500)
 	defer tick.Stop()
 	for {
@@ -242,7 +676,7 @@ func (wo *WatchOption) GetPodImage() (string, error) {
 					klog.V(5).Infof("Pod name:%s ContainerStatuses was empty", v.Name)
 					continue
 				}
-				if v.Status.ContainerStatuses[0].Image != wo.Image {
+				if !sameImage(v.Status.ContainerStatuses[0].Image, wo.Image) {
 					klog.V(5).Infof("Pod name:%s image:%s was not match the WatchOption image:%s", v.Name, v.Status.ContainerStatuses[0].Image, wo.Image)
 					continue
 				}
@@ -259,28 +693,59 @@ func (wo *WatchOption) GetPodImage() (string, error) {
 				continue
 			}
 			return hash, nil
-		case <-wo.ctx.Done():
-			return "", fmt.Errorf("WatchOption GetPodImage ctx cancel")
+		case <-ctx.Done():
+			return "", fmt.Errorf("WatchOption GetPodImage name:%s no running pod of the image was found: %v", wo.Name(), ctx.Err())
 		}
 	}
 }
 
+// Eventf emits an event of the HelixSaga if the WatchOption has a Recorder
+func (wo *WatchOption) Eventf(eventType, reason, messageFmt string, args ...interface{}) {
+	klog.V(2).Infof("HelixSaga:%s %s: %s", wo.OperatorName, reason, fmt.Sprintf(messageFmt, args...))
+	if wo.Recorder == nil || wo.HelixSaga == nil {
+		return
+	}
+	wo.Recorder.Eventf(wo.HelixSaga, eventType, reason, messageFmt, args...)
+}
+
 func (wo *WatchOption) Close() {
 	wo.cancel()
 }
 
-// ConvertImageToObject returns the ImageInfo with an image string
+// ConvertImageToObject returns the ImageInfo with an image string.
+// The parts which couldn't be parsed would be empty, see ParseImage.
 func ConvertImageToObject(image string) *ImageInfo {
+	info, err := ParseImage(image)
+	if err != nil {
+		klog.V(2).Info(err)
+	}
+	return info
+}
+
+// ParseImage returns the ImageInfo of an image in the form of domain/project/repository[:tag],
+// and the tag would be empty if the image was without it, e.g. the one whose tag was selected by the ImagePolicy.
+// The parts which have been parsed would be returned with the error if the image was not in the form.
+func ParseImage(image string) (*ImageInfo, error) {
 	// image: harbor.domain.com/helix-saga/go-all:latest
 	// image: xxxx.xxx.xxx.xxx:8080/helix-saga/go-all:latest
+	info := &ImageInfo{}
 	t := strings.Split(image, "/")
-	t2 := strings.Split(t[2], ":")
-	return &ImageInfo{
-		Domain:     t[0],
-		Project:    t[1],
-		Repository: t2[0],
-		Tag:        t2[1],
+	info.Domain = t[0]
+	if len(t) > 1 {
+		info.Project = t[1]
 	}
+	if len(t) != 3 || t[0] == "" || t[1] == "" {
+		return info, fmt.Errorf("image:%s was not in the form of domain/project/repository[:tag]", image)
+	}
+	t2 := strings.SplitN(t[2], ":", 2)
+	info.Repository = t2[0]
+	if len(t2) > 1 {
+		info.Tag = t2[1]
+	}
+	if info.Repository == "" {
+		return info, fmt.Errorf("image:%s was without the repository", image)
+	}
+	return info, nil
 }
 
 func WatchHarborImage(hi harbor.HubInterface, wo *WatchOption) (watch.Interface, error) {
@@ -293,17 +758,21 @@ func WatchHarborImage(hi harbor.HubInterface, wo *WatchOption) (watch.Interface,
 		klog.V(2).Info(err)
 		return nil, err
 	}
-	opt := harbor.Option{
-		APIVersion: "v1",
-		Kind:       "",
-		Project:    wo.ImageInfo.Project,
-		Repository: wo.ImageInfo.Repository,
-		Tag:        wo.ImageInfo.Tag,
-	}
 	var t watch.Interface
-	if t, err = hb.Watch(opt); err != nil {
+	if t, err = hb.Watch(NewHarborOption(wo.ImageInfo)); err != nil {
 		klog.V(2).Info(err)
 		return nil, err
 	}
 	return t, nil
 }
+
+// NewHarborOption returns the harbor.Option of the image
+func NewHarborOption(info *ImageInfo) harbor.Option {
+	return harbor.Option{
+		APIVersion: "v1",
+		Kind:       "",
+		Project:    info.Project,
+		Repository: info.Repository,
+		Tag:        info.Tag,
+	}
+}
@@ -1,8 +1,21 @@
 package helixsaga
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
+
+	helixsagav1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixsagaFake "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/fake"
+	"github.com/Shanghai-Lunara/helixsaga-operator/pkg/registrywebhook"
+	harbor "github.com/nevercase/harbor-api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
 )
 
 func TestConvertImageToObject(t *testing.T) {
@@ -35,3 +48,381 @@ func TestConvertImageToObject(t *testing.T) {
 		})
 	}
 }
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   string
+		want    *ImageInfo
+		wantErr bool
+	}{
+		{
+			name:  "TestParseImage_port",
+			image: "10.0.0.1:8080/helix-saga/go-all:1.4.2",
+			want:  &ImageInfo{Domain: "10.0.0.1:8080", Project: "helix-saga", Repository: "go-all", Tag: "1.4.2"},
+		},
+		{
+			name:  "TestParseImage_tagless",
+			image: "harbor.domain.com/helix-saga/go-all",
+			want:  &ImageInfo{Domain: "harbor.domain.com", Project: "helix-saga", Repository: "go-all"},
+		},
+		{
+			name:    "TestParseImage_without_project",
+			image:   "go-all:latest",
+			want:    &ImageInfo{Domain: "go-all:latest"},
+			wantErr: true,
+		},
+		{
+			name:    "TestParseImage_nested",
+			image:   "harbor.domain.com/helix-saga/server/go-all:latest",
+			want:    &ImageInfo{Domain: "harbor.domain.com", Project: "helix-saga"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseImage(tt.image)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseImage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeRegistry is a harbor.HubInterface whose Watch returns the results in order
+type fakeRegistry struct {
+	harbor.HarborInterface
+
+	mu      sync.Mutex
+	results []fakeWatchResult
+	logins  int
+	watches int
+}
+
+type fakeWatchResult struct {
+	err    error
+	events []harbor.Option
+}
+
+func (f *fakeRegistry) List() []string {
+	return []string{"http://harbor.domain.com"}
+}
+
+func (f *fakeRegistry) Get(url string) (harbor.HarborInterface, error) {
+	return f, nil
+}
+
+func (f *fakeRegistry) Login() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logins++
+	return nil
+}
+
+func (f *fakeRegistry) Watch(opt harbor.Option) (watch.Interface, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.watches++
+	if len(f.results) == 0 {
+		// keep the last watch open until the Watcher was closed
+		return watch.NewFake(), nil
+	}
+	r := f.results[0]
+	f.results = f.results[1:]
+	if r.err != nil {
+		return nil, r.err
+	}
+	// the stream would be closed after the events have been sent
+	fw := watch.NewFakeWithChanSize(len(r.events), false)
+	for _, v := range r.events {
+		fw.Modify(v)
+	}
+	fw.Stop()
+	return fw, nil
+}
+
+func (f *fakeRegistry) counts() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.logins, f.watches
+}
+
+func newTestWatcher(ctx context.Context, f *fakeRegistry, steps int) (*Watcher, *[]helixsagav1.ImageWatchStatus, chan harbor.Option) {
+	hs := &helixsagav1.HelixSaga{ObjectMeta: metav1.ObjectMeta{Name: "hs", Namespace: "default"}}
+	wo := NewWatchOption(ctx, nil, nil, hs, "harbor.domain.com/helix-saga/go-all:latest")
+	w := NewWatcher(ctx, f, wo.Image)
+	w.Add(wo)
+	w.backoff = wait.Backoff{Duration: time.Millisecond, Factor: 2, Jitter: 0.1, Steps: steps, Cap: time.Millisecond * 10}
+	w.stableDuration = time.Hour
+	var mu sync.Mutex
+	states := make([]helixsagav1.ImageWatchStatus, 0)
+	w.reporter = func(status helixsagav1.ImageWatchStatus) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, status)
+	}
+	events := make(chan harbor.Option, 10)
+	w.handler = func(t harbor.Option) {
+		events <- t
+	}
+	return w, &states, events
+}
+
+func TestWatcherReconnect(t *testing.T) {
+	f := &fakeRegistry{
+		results: []fakeWatchResult{
+			{events: []harbor.Option{{Sha256: "sha256:1"}}},
+			{err: fmt.Errorf("dial tcp: connection refused")},
+			{events: []harbor.Option{{Sha256: "sha256:2"}}},
+		},
+	}
+	w, _, events := newTestWatcher(context.Background(), f, 5)
+	done := make(chan struct{})
+	go func() {
+		w.Run()
+		close(done)
+	}()
+	for _, want := range []string{"sha256:1", "sha256:2"} {
+		select {
+		case got := <-events:
+			if got.Sha256 != want {
+				t.Errorf("Watcher handler = %v, want %v", got.Sha256, want)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("Watcher handler was not called with %v", want)
+		}
+	}
+	if err := wait.PollImmediate(time.Millisecond*10, time.Second*5, func() (bool, error) {
+		return w.getState() == helixsagav1.ImageWatchStateWatching, nil
+	}); err != nil {
+		t.Fatalf("Watcher state = %v, want %v", w.getState(), helixsagav1.ImageWatchStateWatching)
+	}
+	w.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Watcher Run was not returned after Close")
+	}
+	if logins, watches := f.counts(); logins != 1 || watches != 4 {
+		t.Errorf("Watcher logins = %d watches = %d, want 1 and 4", logins, watches)
+	}
+}
+
+func TestWatcherRelogin(t *testing.T) {
+	f := &fakeRegistry{
+		results: []fakeWatchResult{
+			{err: fmt.Errorf("unexpected status code: 401 Unauthorized")},
+		},
+	}
+	w, _, _ := newTestWatcher(context.Background(), f, 5)
+	go w.Run()
+	defer w.Close()
+	if err := wait.PollImmediate(time.Millisecond*10, time.Second*5, func() (bool, error) {
+		return w.getState() == helixsagav1.ImageWatchStateWatching, nil
+	}); err != nil {
+		t.Fatalf("Watcher state = %v, want %v", w.getState(), helixsagav1.ImageWatchStateWatching)
+	}
+	if logins, _ := f.counts(); logins != 2 {
+		t.Errorf("Watcher logins = %d, want 2", logins)
+	}
+}
+
+func TestWatcherRetryBudget(t *testing.T) {
+	results := make([]fakeWatchResult, 0)
+	for i := 0; i < 10; i++ {
+		results = append(results, fakeWatchResult{err: fmt.Errorf("dial tcp: connection refused")})
+	}
+	f := &fakeRegistry{results: results}
+	w, states, _ := newTestWatcher(context.Background(), f, 3)
+	done := make(chan struct{})
+	go func() {
+		w.Run()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Watcher Run was not returned after the retry budget has been used up")
+	}
+	if !w.Failed(0) {
+		t.Errorf("Watcher state = %v, want %v", w.getState(), helixsagav1.ImageWatchStateFailed)
+	}
+	if _, watches := f.counts(); watches != 4 {
+		t.Errorf("Watcher watches = %d, want 4", watches)
+	}
+	last := (*states)[len(*states)-1]
+	if last.State != helixsagav1.ImageWatchStateFailed || last.Retries != 3 || last.RetryBudget != 3 || last.LastError == "" {
+		t.Errorf("Watcher reported = %+v", last)
+	}
+}
+
+func TestWatchersNotify(t *testing.T) {
+	ws := NewWatchers(nil)
+	ws.DisableRegistryWatch()
+	hs := &helixsagav1.HelixSaga{ObjectMeta: metav1.ObjectMeta{Name: "hs", Namespace: "default"}}
+	hs2 := &helixsagav1.HelixSaga{ObjectMeta: metav1.ObjectMeta{Name: "hs2", Namespace: "default"}}
+	for _, image := range []string{
+		"harbor.domain.com/helix-saga/go-all:latest",
+		"harbor.domain.com/helix-saga/go-all:v1",
+		"other.domain.com/helix-saga/go-all:latest",
+	} {
+		if err := ws.Subscribe(NewWatchOption(context.Background(), nil, nil, hs, image)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ws.Subscribe(NewWatchOption(context.Background(), nil, nil, hs2, "harbor.domain.com/helix-saga/go-all:latest")); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range ws.items {
+		// the image update flow was out of the scope of the test
+		for _, wo := range w.Subscribers() {
+			wo.Close()
+		}
+		if w.getState() != helixsagav1.ImageWatchStateConnecting {
+			t.Errorf("Watcher state = %v, the registry watch should not be started", w.getState())
+		}
+	}
+	tests := []struct {
+		name  string
+		event registrywebhook.PushEvent
+		want  int
+	}{
+		{
+			name:  "TestWatchersNotify_domain",
+			event: registrywebhook.PushEvent{Domain: "harbor.domain.com", Project: "helix-saga", Repository: "go-all", Tag: "latest", Digest: "sha256:abc"},
+			want:  2,
+		},
+		{
+			name:  "TestWatchersNotify_without_domain",
+			event: registrywebhook.PushEvent{Project: "helix-saga", Repository: "go-all", Tag: "latest", Digest: "sha256:abc"},
+			want:  3,
+		},
+		{
+			name:  "TestWatchersNotify_not_matched",
+			event: registrywebhook.PushEvent{Project: "helix-saga", Repository: "go-all", Tag: "v2", Digest: "sha256:abc"},
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ws.Notify(tt.event); got != tt.want {
+				t.Errorf("Notify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchersSubscribe(t *testing.T) {
+	ws := NewWatchers(nil)
+	ws.DisableRegistryWatch()
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	options := make([]*WatchOption, 0)
+	for _, name := range []string{"hs", "hs2", "hs3"} {
+		hs := &helixsagav1.HelixSaga{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		wo := NewWatchOption(context.Background(), nil, nil, hs, image)
+		if err := ws.Subscribe(wo); err != nil {
+			t.Fatal(err)
+		}
+		options = append(options, wo)
+	}
+	// subscribing the same HelixSaga again replaces the present subscription with the latest one
+	latest := NewWatchOption(context.Background(), nil, nil, options[0].HelixSaga, image)
+	if err := ws.Subscribe(latest); err != nil {
+		t.Fatal(err)
+	}
+	if len(ws.items) != 1 {
+		t.Fatalf("Watchers items = %d, want 1", len(ws.items))
+	}
+	w := ws.items[image]
+	if got := w.subscribers[latest.Name()]; got != latest {
+		t.Errorf("Watcher subscriber name:%s was not replaced by the latest one", latest.Name())
+	}
+	options[0] = latest
+	for i, wo := range options {
+		if got := len(w.Subscribers()); got != len(options)-i {
+			t.Errorf("Watcher subscribers = %d, want %d", got, len(options)-i)
+		}
+		ws.UnSubscribe(wo)
+		select {
+		case <-wo.ctx.Done():
+		default:
+			t.Errorf("WatchOption name:%s was not closed after UnSubscribe", wo.Name())
+		}
+	}
+	if len(ws.items) != 0 {
+		t.Errorf("Watchers items = %d, want 0", len(ws.items))
+	}
+	select {
+	case <-w.ctx.Done():
+	default:
+		t.Error("Watcher was not closed after the last subscription has been removed")
+	}
+}
+
+func TestWatchOptionGetPodImage_timeout(t *testing.T) {
+	timeout := PodImageTimeout
+	PodImageTimeout = time.Millisecond * 600
+	defer func() { PodImageTimeout = timeout }()
+	// the HelixSaga has been scaled to zero
+	hs := &helixsagav1.HelixSaga{ObjectMeta: metav1.ObjectMeta{Name: "hs", Namespace: "default"}}
+	wo := NewWatchOption(context.Background(), k8sFake.NewSimpleClientset(), nil, hs, "harbor.domain.com/helix-saga/go-all:latest")
+	done := make(chan error, 1)
+	go func() {
+		_, err := wo.GetPodImage()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("GetPodImage() error = nil, want the timeout")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("GetPodImage() was not returned after the PodImageTimeout")
+	}
+}
+
+func TestApplyImageChange_inProgress(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	applying, newer := "sha256:1", "sha256:2"
+	hs := &helixsagav1.HelixSaga{
+		ObjectMeta: metav1.ObjectMeta{Name: "hs", Namespace: "default"},
+		Spec: helixsagav1.HelixSagaSpec{
+			Applications: []helixsagav1.HelixSagaApp{
+				{Spec: helixsagav1.HelixSagaAppSpec{Name: "game", Image: image, WatchPolicy: helixsagav1.WatchPolicyAuto}},
+			},
+		},
+	}
+	clientSet := helixsagaFake.NewSimpleClientset(hs)
+	wo := NewWatchOption(context.Background(), k8sFake.NewSimpleClientset(), clientSet, hs, image)
+	defer wo.Close()
+	ws := NewWatchers(nil)
+	ws.applying[wo.Name()] = applying
+	pending := func() *helixsagav1.PendingUpdate {
+		got, err := clientSet.NevercaseV1().HelixSagas("default").Get(context.Background(), "hs", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got.Spec.Applications[0].Status.PendingUpdate
+	}
+	// the newer digest would be queued instead of being dropped
+	ws.applyImageChange(wo, newer)
+	if got := pending(); got == nil || got.Digest != newer {
+		t.Fatalf("applyImageChange() pendingUpdate = %+v, want the digest %s", got, newer)
+	}
+	// the update in progress would only clear its own digest
+	if err := ClearPendingUpdate(clientSet, "default", "hs", image, applying); err != nil {
+		t.Fatal(err)
+	}
+	if got := pending(); got == nil || got.Digest != newer {
+		t.Errorf("ClearPendingUpdate() of the digest %s pendingUpdate = %+v, want the digest %s", applying, got, newer)
+	}
+	if err := ClearPendingUpdate(clientSet, "default", "hs", image, newer); err != nil {
+		t.Fatal(err)
+	}
+	if got := pending(); got != nil {
+		t.Errorf("ClearPendingUpdate() pendingUpdate = %+v, want nil", got)
+	}
+}
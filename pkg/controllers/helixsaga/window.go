@@ -0,0 +1,248 @@
+package helixsaga
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaClientSet "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	"github.com/nevercase/k8s-controller-custom-resource/pkg/env"
+	"github.com/robfig/cron"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// PendingUpdateInterval is the interval between the checks of the pending updates
+var PendingUpdateInterval = time.Minute
+
+// GetUpdateWindow returns the UpdateWindow of the app, the one of the HelixSaga would be used if the app has none
+func GetUpdateWindow(hs *helixSagaV1.HelixSaga, spec *helixSagaV1.HelixSagaAppSpec) *helixSagaV1.UpdateWindow {
+	if spec.UpdateWindow != nil {
+		return spec.UpdateWindow
+	}
+	return hs.Spec.UpdateWindow
+}
+
+// ParseSchedule parses the Schedule of the UpdateWindow and the ReplicaSchedule.
+// The standard cron expression without the seconds field would be started at the second 0.
+func ParseSchedule(spec string) (cron.Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("the schedule was empty")
+	}
+	if !strings.HasPrefix(spec, "@") {
+		if n := len(strings.Fields(spec)); n != 5 {
+			return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, found %d", spec, n)
+		}
+		spec = "0 " + spec
+	}
+	return cron.Parse(spec)
+}
+
+// LoadLocation returns the time zone of the name, defaults to UTC
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// WindowOpen reports whether the UpdateWindow was open at the time t.
+// It returns the start of the present window if it was open, otherwise the start of the next window.
+// A nil UpdateWindow was always open.
+func WindowOpen(w *helixSagaV1.UpdateWindow, t time.Time) (bool, time.Time, error) {
+	if w == nil {
+		return true, t, nil
+	}
+	if w.Duration.Duration <= 0 {
+		return false, time.Time{}, fmt.Errorf("the duration of the update window was not positive")
+	}
+	sched, err := ParseSchedule(w.Schedule)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	loc, err := LoadLocation(w.TimeZone)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	lt := t.In(loc)
+	// the first start after the present window would have been opened
+	start := sched.Next(lt.Add(-w.Duration.Duration))
+	if start.IsZero() {
+		return false, time.Time{}, fmt.Errorf("the schedule %q would never be started", w.Schedule)
+	}
+	return !start.After(lt), start, nil
+}
+
+// ApplyNow reports whether the pending update of the app would be applied regardless of the UpdateWindow.
+// The ApplyNowAnnotation was "true", "*" or the comma separated names of the apps.
+func ApplyNow(hs *helixSagaV1.HelixSaga, name string) bool {
+	t, ok := hs.Annotations[ApplyNowAnnotation]
+	if !ok {
+		return false
+	}
+	for _, v := range strings.Split(t, ",") {
+		v = strings.TrimSpace(v)
+		if v == "true" || v == "*" || v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckUpdateWindow reports whether the update of the image would be pending for the apps with WatchPolicy auto.
+// The update would be pending until the UpdateWindows of all the apps which were using the image are open,
+// and none of them was paused.
+func CheckUpdateWindow(hs *helixSagaV1.HelixSaga, image string, now time.Time) (bool, time.Time, string) {
+	for _, v := range GetAppSpecs(hs) {
+		if v.Image != image || v.WatchPolicy != helixSagaV1.WatchPolicyAuto {
+			continue
+		}
+		// the ApplyNowAnnotation couldn't override the Paused
+		if IsPaused(hs, &v) {
+			return true, time.Time{}, fmt.Sprintf("app %s was paused", v.Name)
+		}
+		if ApplyNow(hs, v.Name) {
+			continue
+		}
+		open, next, err := WindowOpen(GetUpdateWindow(hs, &v), now)
+		if err != nil {
+			return true, time.Time{}, fmt.Sprintf("invalid update window of app %s: %v", v.Name, err)
+		}
+		if !open {
+			return true, next, fmt.Sprintf("waiting for the update window of app %s", v.Name)
+		}
+	}
+	return false, time.Time{}, ""
+}
+
+// QueueImageUpdate records the pending update in the status of the apps with WatchPolicy auto which were using the image
+func QueueImageUpdate(clientSet helixSagaClientSet.Interface, namespace, crdName, image, digest string, next time.Time, reason string) error {
+	return updateApplications(clientSet, namespace, crdName, func(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec) bool {
+		if spec.Image != image || spec.WatchPolicy != helixSagaV1.WatchPolicyAuto {
+			return false
+		}
+		t := app.Status.PendingUpdate
+		if t != nil && t.Image == image && t.Digest == digest && t.Reason == reason && t.NextWindow.Time.Equal(next) {
+			return false
+		}
+		pending := &helixSagaV1.PendingUpdate{
+			Image:      image,
+			Digest:     digest,
+			DetectedAt: metav1.Now(),
+			NextWindow: metav1.NewTime(next),
+			Reason:     reason,
+		}
+		// keep the time of the first detection of the same digest
+		if t != nil && t.Image == image && t.Digest == digest {
+			pending.DetectedAt = t.DetectedAt
+		}
+		app.Status.PendingUpdate = pending
+		return true
+	})
+}
+
+// ClearPendingUpdate removes the pending update of the image digest from the status of the apps.
+// The pending update of a newer digest which was queued during the update would be kept.
+func ClearPendingUpdate(clientSet helixSagaClientSet.Interface, namespace, crdName, image, digest string) error {
+	return updateApplications(clientSet, namespace, crdName, func(app *helixSagaV1.HelixSagaApp, _ *helixSagaV1.HelixSagaAppSpec) bool {
+		t := app.Status.PendingUpdate
+		if t == nil || t.Image != image || t.Digest != digest {
+			return false
+		}
+		app.Status.PendingUpdate = nil
+		return true
+	})
+}
+
+// updateApplications updates the HelixSaga if fn has changed any of its apps.
+// The spec with the Defaults would be passed to fn for reading, the changes should be made on the app.
+func updateApplications(clientSet helixSagaClientSet.Interface, namespace, crdName string, fn func(app *helixSagaV1.HelixSagaApp, spec *helixSagaV1.HelixSagaAppSpec) bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+		defer cancel()
+		hs, err := clientSet.NevercaseV1().HelixSagas(namespace).Get(ctx, crdName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		hs = hs.DeepCopy()
+		changed := false
+		for i, spec := range GetAppSpecs(hs) {
+			if fn(&hs.Spec.Applications[i], &spec) {
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		_, err = clientSet.NevercaseV1().HelixSagas(namespace).Update(ctx, hs, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// removeApplyNowAnnotation removes the ApplyNowAnnotation after it has been consumed
+func removeApplyNowAnnotation(clientSet helixSagaClientSet.Interface, namespace, crdName string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(env.DefaultExecutionDuration))
+		defer cancel()
+		hs, err := clientSet.NevercaseV1().HelixSagas(namespace).Get(ctx, crdName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if _, ok := hs.Annotations[ApplyNowAnnotation]; !ok {
+			return nil
+		}
+		hs = hs.DeepCopy()
+		delete(hs.Annotations, ApplyNowAnnotation)
+		_, err = clientSet.NevercaseV1().HelixSagas(namespace).Update(ctx, hs, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// SyncPendingUpdates applies the pending updates of all the HelixSagas whose UpdateWindows are open
+func (c *controller) SyncPendingUpdates() {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		klog.V(2).Info(err)
+		return
+	}
+	for _, hs := range list {
+		c.syncPendingUpdates(hs)
+	}
+}
+
+// syncPendingUpdates applies the pending updates of the HelixSaga whose UpdateWindows are open or
+// which were required by the ApplyNowAnnotation, then the ApplyNowAnnotation would be removed
+func (c *controller) syncPendingUpdates(hs *helixSagaV1.HelixSaga) {
+	now := time.Now()
+	images := make(map[string]string, 0)
+	specs := GetAppSpecs(hs)
+	for i, v := range hs.Spec.Applications {
+		t := v.Status.PendingUpdate
+		if t == nil || t.Image != specs[i].Image {
+			continue
+		}
+		if pending, _, _ := CheckUpdateWindow(hs, t.Image, now); pending {
+			continue
+		}
+		images[t.Image] = t.Digest
+	}
+	for image, digest := range images {
+		klog.Infof("HelixSaga crdName:%s image:%s digest:%s applies the pending update", hs.Name, image, digest)
+		wo := NewWatchOption(context.Background(), c.kubeClientSet, c.clientSet, hs, image)
+		wo.Recorder = c.getRecorder()
+		go func(wo *WatchOption, digest string) {
+			defer wo.Close()
+			c.watchers.applyImageChange(wo, digest)
+		}(wo, digest)
+	}
+	if _, ok := hs.Annotations[ApplyNowAnnotation]; ok {
+		if err := removeApplyNowAnnotation(c.clientSet, hs.Namespace, hs.Name); err != nil {
+			klog.V(2).Info(err)
+		}
+	}
+}
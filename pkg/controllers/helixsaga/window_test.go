@@ -0,0 +1,175 @@
+package helixsaga
+
+import (
+	"testing"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWindowOpen(t *testing.T) {
+	// 03:00-05:00 in Asia/Shanghai is 19:00-21:00 in UTC
+	window := &helixSagaV1.UpdateWindow{
+		Schedule: "0 3 * * *",
+		Duration: metaV1.Duration{Duration: time.Hour * 2},
+		TimeZone: "Asia/Shanghai",
+	}
+	tests := []struct {
+		name     string
+		window   *helixSagaV1.UpdateWindow
+		now      time.Time
+		wantOpen bool
+		wantNext time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "TestWindowOpen_nil",
+			window:   nil,
+			now:      time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+			wantOpen: true,
+			wantNext: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "TestWindowOpen_before",
+			window:   window,
+			now:      time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+			wantOpen: false,
+			wantNext: time.Date(2021, 6, 1, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "TestWindowOpen_start",
+			window:   window,
+			now:      time.Date(2021, 6, 1, 19, 0, 0, 0, time.UTC),
+			wantOpen: true,
+			wantNext: time.Date(2021, 6, 1, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "TestWindowOpen_inside",
+			window:   window,
+			now:      time.Date(2021, 6, 1, 20, 59, 0, 0, time.UTC),
+			wantOpen: true,
+			wantNext: time.Date(2021, 6, 1, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "TestWindowOpen_after",
+			window:   window,
+			now:      time.Date(2021, 6, 1, 21, 0, 0, 0, time.UTC),
+			wantOpen: false,
+			wantNext: time.Date(2021, 6, 2, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestWindowOpen_invalid_schedule",
+			window:  &helixSagaV1.UpdateWindow{Schedule: "0 3 * *", Duration: metaV1.Duration{Duration: time.Hour}},
+			now:     time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+		{
+			name:    "TestWindowOpen_invalid_time_zone",
+			window:  &helixSagaV1.UpdateWindow{Schedule: "@daily", Duration: metaV1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"},
+			now:     time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+		{
+			name:    "TestWindowOpen_zero_duration",
+			window:  &helixSagaV1.UpdateWindow{Schedule: "@daily"},
+			now:     time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, next, err := WindowOpen(tt.window, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WindowOpen() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if open != tt.wantOpen {
+				t.Errorf("WindowOpen() open = %v, want %v", open, tt.wantOpen)
+			}
+			if !next.Equal(tt.wantNext) {
+				t.Errorf("WindowOpen() next = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestCheckUpdateWindow(t *testing.T) {
+	image := "harbor.domain.com/helix-saga/go-all:latest"
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	closed := &helixSagaV1.UpdateWindow{Schedule: "0 3 * * *", Duration: metaV1.Duration{Duration: time.Hour}}
+	opened := &helixSagaV1.UpdateWindow{Schedule: "0 11 * * *", Duration: metaV1.Duration{Duration: time.Hour * 2}}
+	tests := []struct {
+		name        string
+		window      *helixSagaV1.UpdateWindow
+		appWindow   *helixSagaV1.UpdateWindow
+		policy      helixSagaV1.WatchPolicy
+		annotations map[string]string
+		paused      bool
+		want        bool
+	}{
+		{
+			name:   "TestCheckUpdateWindow_no_window",
+			policy: helixSagaV1.WatchPolicyAuto,
+			want:   false,
+		},
+		{
+			name:   "TestCheckUpdateWindow_closed",
+			window: closed,
+			policy: helixSagaV1.WatchPolicyAuto,
+			want:   true,
+		},
+		{
+			name:      "TestCheckUpdateWindow_app_override",
+			window:    closed,
+			appWindow: opened,
+			policy:    helixSagaV1.WatchPolicyAuto,
+			want:      false,
+		},
+		{
+			name:   "TestCheckUpdateWindow_manual",
+			window: closed,
+			policy: helixSagaV1.WatchPolicyManual,
+			want:   false,
+		},
+		{
+			name:        "TestCheckUpdateWindow_apply_now",
+			window:      closed,
+			policy:      helixSagaV1.WatchPolicyAuto,
+			annotations: map[string]string{ApplyNowAnnotation: "lobby, game"},
+			want:        false,
+		},
+		{
+			name:        "TestCheckUpdateWindow_apply_now_other_app",
+			window:      closed,
+			policy:      helixSagaV1.WatchPolicyAuto,
+			annotations: map[string]string{ApplyNowAnnotation: "lobby"},
+			want:        true,
+		},
+		{
+			name:        "TestCheckUpdateWindow_paused",
+			policy:      helixSagaV1.WatchPolicyAuto,
+			annotations: map[string]string{ApplyNowAnnotation: "game"},
+			paused:      true,
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &helixSagaV1.HelixSaga{
+				ObjectMeta: metaV1.ObjectMeta{Annotations: tt.annotations},
+				Spec: helixSagaV1.HelixSagaSpec{
+					UpdateWindow: tt.window,
+					Applications: []helixSagaV1.HelixSagaApp{
+						{Spec: helixSagaV1.HelixSagaAppSpec{Name: "game", Image: image, WatchPolicy: tt.policy, UpdateWindow: tt.appWindow, Paused: tt.paused}},
+					},
+				},
+			}
+			if got, _, reason := CheckUpdateWindow(hs, image, now); got != tt.want {
+				t.Errorf("CheckUpdateWindow() = %v (%s), want %v", got, reason, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package conversionwebhook
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ConversionReview is the ConversionReview of apiextensions.k8s.io, which has the same json in v1 and v1beta1.
+// It was declared here since the apiextensions-apiserver of the same version as the client-go was not required.
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	// Request is the conversion request sent by the kube-apiserver
+	Request *ConversionRequest `json:"request,omitempty"`
+	// Response is the result of the conversion, the Request would be dropped from the response
+	Response *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest describes the conversion request parameters
+type ConversionRequest struct {
+	// UID identifies the request, which would be copied into the response
+	UID types.UID `json:"uid"`
+	// DesiredAPIVersion is the version to convert the objects to, e.g. "nevercase.io/v2"
+	DesiredAPIVersion string `json:"desiredAPIVersion"`
+	// Objects are the objects to be converted, which might be in different versions
+	Objects []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse describes the conversion response
+type ConversionResponse struct {
+	// UID is the UID of the request
+	UID types.UID `json:"uid"`
+	// ConvertedObjects are the objects in the DesiredAPIVersion, in the same order of the Objects of the request
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	// Result contains the result of the conversion with the details if it failed
+	Result metav1.Status `json:"result"`
+}
@@ -0,0 +1,142 @@
+package conversionwebhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaV2 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+)
+
+const maxReviewBytes = 3 << 20
+
+// Handler converts the HelixSagas between the versions for the kube-apiserver
+type Handler struct {
+	scheme  *runtime.Scheme
+	decoder runtime.Decoder
+}
+
+// NewHandler returns the Handler which converts all the served versions of the HelixSaga
+func NewHandler() *Handler {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(helixSagaV1.AddToScheme(scheme))
+	utilruntime.Must(helixSagaV2.AddToScheme(scheme))
+	return &Handler{
+		scheme:  scheme,
+		decoder: serializer.NewCodecFactory(scheme).UniversalDeserializer(),
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxReviewBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review := &ConversionReview{}
+	if err = json.Unmarshal(data, review); err != nil || review.Request == nil {
+		klog.V(2).Infof("conversion webhook invalid review err:%v", err)
+		http.Error(w, "invalid ConversionReview", http.StatusBadRequest)
+		return
+	}
+	review.Response = h.Convert(review.Request)
+	review.Request = nil
+	res, err := json.Marshal(review)
+	if err != nil {
+		klog.V(2).Info(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(res); err != nil {
+		klog.V(2).Info(err)
+	}
+}
+
+// Convert converts all the objects of the request to the DesiredAPIVersion.
+// The response would be failed if any one of the objects couldn't be converted.
+func (h *Handler) Convert(req *ConversionRequest) *ConversionResponse {
+	res := &ConversionResponse{UID: req.UID}
+	objects, err := h.convert(req)
+	if err != nil {
+		klog.V(2).Infof("conversion webhook uid:%s to:%s err:%v", req.UID, req.DesiredAPIVersion, err)
+		res.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+		return res
+	}
+	res.ConvertedObjects = objects
+	res.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return res
+}
+
+func (h *Handler) convert(req *ConversionRequest) ([]runtime.RawExtension, error) {
+	gv, err := schema.ParseGroupVersion(req.DesiredAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !h.scheme.IsVersionRegistered(gv) {
+		return nil, fmt.Errorf("unsupported desiredAPIVersion %s", req.DesiredAPIVersion)
+	}
+	objects := make([]runtime.RawExtension, 0, len(req.Objects))
+	for _, v := range req.Objects {
+		obj, _, err := h.decoder.Decode(v.Raw, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		out, err := h.scheme.ConvertToVersion(obj, gv)
+		if err != nil {
+			return nil, err
+		}
+		t, err := json.Marshal(out)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, runtime.RawExtension{Raw: t})
+	}
+	return objects, nil
+}
+
+// ListenAndServeTLS serves the Handler on the addr until stopCh was closed.
+// The kube-apiserver only calls the webhooks over https, the certFile and keyFile could be empty behind a proxy.
+func ListenAndServeTLS(addr, certFile, keyFile string, h http.Handler, stopCh <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle("/convert", h)
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  time.Second * 10,
+		WriteTimeout: time.Second * 10,
+	}
+	go func() {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			klog.V(2).Info(err)
+		}
+	}()
+	klog.Infof("conversion webhook listening on %s", addr)
+	var err error
+	if certFile == "" && keyFile == "" {
+		err = srv.ListenAndServe()
+	} else {
+		err = srv.ListenAndServeTLS(certFile, keyFile)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
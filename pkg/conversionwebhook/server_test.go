@@ -0,0 +1,147 @@
+package conversionwebhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	helixSagaV1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	helixSagaV2 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	v1Object = `{"apiVersion":"nevercase.io/v1","kind":"HelixSaga","metadata":{"name":"hs","namespace":"default"},"spec":{"applications":[{"spec":{"name":"game","image":"harbor.domain.com/helix-saga/go-all:latest"},"status":{"currentImage":{"digest":"sha256:1"}}}]}}`
+	v2Object = `{"apiVersion":"nevercase.io/v2","kind":"HelixSaga","metadata":{"name":"hs","namespace":"default"},"spec":{"apps":[{"name":"game","image":"harbor.domain.com/helix-saga/go-all:latest"}]},"status":{"apps":[{"name":"game","currentImage":{"digest":"sha256:1"}}]}}`
+)
+
+func review(desired string, objects ...string) string {
+	raw := make([]json.RawMessage, 0, len(objects))
+	for _, v := range objects {
+		raw = append(raw, json.RawMessage(v))
+	}
+	t, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "ConversionReview",
+		"request":    map[string]interface{}{"uid": "1", "desiredAPIVersion": desired, "objects": raw},
+	})
+	return string(t)
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		wantCode    int
+		wantStatus  string
+		wantVersion string
+	}{
+		{
+			name:        "TestHandler_v1_to_v2",
+			method:      http.MethodPost,
+			body:        review("nevercase.io/v2", v1Object),
+			wantCode:    http.StatusOK,
+			wantStatus:  metav1.StatusSuccess,
+			wantVersion: "nevercase.io/v2",
+		},
+		{
+			name:        "TestHandler_v2_to_v1",
+			method:      http.MethodPost,
+			body:        review("nevercase.io/v1", v2Object, v1Object),
+			wantCode:    http.StatusOK,
+			wantStatus:  metav1.StatusSuccess,
+			wantVersion: "nevercase.io/v1",
+		},
+		{
+			name:       "TestHandler_unsupported_version",
+			method:     http.MethodPost,
+			body:       review("nevercase.io/v3", v1Object),
+			wantCode:   http.StatusOK,
+			wantStatus: metav1.StatusFailure,
+		},
+		{
+			name:     "TestHandler_without_request",
+			method:   http.MethodPost,
+			body:     `{"apiVersion":"apiextensions.k8s.io/v1","kind":"ConversionReview"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "TestHandler_method",
+			method:   http.MethodGet,
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+	h := NewHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/convert", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.wantCode {
+				t.Fatalf("ServeHTTP() code = %v, want %v", w.Code, tt.wantCode)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			res := &ConversionReview{}
+			if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Request != nil || res.Response == nil || res.Response.UID != "1" {
+				t.Fatalf("ServeHTTP() review = %+v", res)
+			}
+			if res.Response.Result.Status != tt.wantStatus {
+				t.Fatalf("ServeHTTP() status = %v, want %v", res.Response.Result, tt.wantStatus)
+			}
+			for _, v := range res.Response.ConvertedObjects {
+				meta := &metav1.TypeMeta{}
+				if err := json.Unmarshal(v.Raw, meta); err != nil {
+					t.Fatal(err)
+				}
+				if meta.APIVersion != tt.wantVersion || meta.Kind != "HelixSaga" {
+					t.Errorf("ServeHTTP() converted = %v, want %v", meta, tt.wantVersion)
+				}
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	h := NewHandler()
+	res := h.Convert(&ConversionRequest{UID: "1", DesiredAPIVersion: "nevercase.io/v2"})
+	if res.Result.Status != metav1.StatusSuccess || len(res.ConvertedObjects) != 0 {
+		t.Fatalf("Convert() = %+v", res)
+	}
+	req := &ConversionRequest{}
+	if err := json.Unmarshal([]byte(`{"uid":"1","desiredAPIVersion":"nevercase.io/v2","objects":[`+v1Object+`]}`), req); err != nil {
+		t.Fatal(err)
+	}
+	res = h.Convert(req)
+	if res.Result.Status != metav1.StatusSuccess || len(res.ConvertedObjects) != 1 {
+		t.Fatalf("Convert() = %+v", res)
+	}
+	hs := &helixSagaV2.HelixSaga{}
+	if err := json.Unmarshal(res.ConvertedObjects[0].Raw, hs); err != nil {
+		t.Fatal(err)
+	}
+	if len(hs.Spec.Apps) != 1 || hs.Spec.Apps[0].Name != "game" || len(hs.Status.Apps) != 1 || hs.Status.Apps[0].CurrentImage.Digest != "sha256:1" {
+		t.Errorf("Convert() = %+v", hs)
+	}
+
+	// and back to the v1
+	req = &ConversionRequest{UID: "2", DesiredAPIVersion: "nevercase.io/v1", Objects: res.ConvertedObjects}
+	res = h.Convert(req)
+	if res.Result.Status != metav1.StatusSuccess || len(res.ConvertedObjects) != 1 {
+		t.Fatalf("Convert() = %+v", res)
+	}
+	back := &helixSagaV1.HelixSaga{}
+	if err := json.Unmarshal(res.ConvertedObjects[0].Raw, back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Spec.Applications) != 1 || back.Spec.Applications[0].Status.CurrentImage.Digest != "sha256:1" {
+		t.Errorf("Convert() = %+v", back)
+	}
+}
@@ -22,6 +22,8 @@ import (
 	clientset "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
 	nevercasev1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/typed/helixsaga/v1"
 	fakenevercasev1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/typed/helixsaga/v1/fake"
+	nevercasev2 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/typed/helixsaga/v2"
+	fakenevercasev2 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/typed/helixsaga/v2/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -80,3 +82,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) NevercaseV1() nevercasev1.NevercaseV1Interface {
 	return &fakenevercasev1.FakeNevercaseV1{Fake: &c.Fake}
 }
+
+// NevercaseV2 retrieves the NevercaseV2Client
+func (c *Clientset) NevercaseV2() nevercasev2.NevercaseV2Interface {
+	return &fakenevercasev2.FakeNevercaseV2{Fake: &c.Fake}
+}
@@ -20,6 +20,7 @@ package scheme
 
 import (
 	nevercasev1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	nevercasev2 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -32,6 +33,7 @@ var Codecs = serializer.NewCodecFactory(Scheme)
 var ParameterCodec = runtime.NewParameterCodec(Scheme)
 var localSchemeBuilder = runtime.SchemeBuilder{
 	nevercasev1.AddToScheme,
+	nevercasev2.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
@@ -32,6 +32,14 @@ func (c *FakeNevercaseV1) HelixSagas(namespace string) v1.HelixSagaInterface {
 	return &FakeHelixSagas{c, namespace}
 }
 
+func (c *FakeNevercaseV1) HelixSagaSets(namespace string) v1.HelixSagaSetInterface {
+	return &FakeHelixSagaSets{c, namespace}
+}
+
+func (c *FakeNevercaseV1) HelixSagaTemplates() v1.HelixSagaTemplateInterface {
+	return &FakeHelixSagaTemplates{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeNevercaseV1) RESTClient() rest.Interface {
@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	helixsagav1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeHelixSagaSets implements HelixSagaSetInterface
+type FakeHelixSagaSets struct {
+	Fake *FakeNevercaseV1
+	ns   string
+}
+
+var helixsagasetsResource = schema.GroupVersionResource{Group: "nevercase.io", Version: "v1", Resource: "helixsagasets"}
+
+var helixsagasetsKind = schema.GroupVersionKind{Group: "nevercase.io", Version: "v1", Kind: "HelixSagaSet"}
+
+// Get takes name of the helixSagaSet, and returns the corresponding helixSagaSet object, and an error if there is any.
+func (c *FakeHelixSagaSets) Get(ctx context.Context, name string, options v1.GetOptions) (result *helixsagav1.HelixSagaSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(helixsagasetsResource, c.ns, name), &helixsagav1.HelixSagaSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*helixsagav1.HelixSagaSet), err
+}
+
+// List takes label and field selectors, and returns the list of HelixSagaSets that match those selectors.
+func (c *FakeHelixSagaSets) List(ctx context.Context, opts v1.ListOptions) (result *helixsagav1.HelixSagaSetList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(helixsagasetsResource, helixsagasetsKind, c.ns, opts), &helixsagav1.HelixSagaSetList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &helixsagav1.HelixSagaSetList{ListMeta: obj.(*helixsagav1.HelixSagaSetList).ListMeta}
+	for _, item := range obj.(*helixsagav1.HelixSagaSetList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested helixSagaSets.
+func (c *FakeHelixSagaSets) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(helixsagasetsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a helixSagaSet and creates it.  Returns the server's representation of the helixSagaSet, and an error, if there is any.
+func (c *FakeHelixSagaSets) Create(ctx context.Context, helixSagaSet *helixsagav1.HelixSagaSet, opts v1.CreateOptions) (result *helixsagav1.HelixSagaSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(helixsagasetsResource, c.ns, helixSagaSet), &helixsagav1.HelixSagaSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*helixsagav1.HelixSagaSet), err
+}
+
+// Update takes the representation of a helixSagaSet and updates it. Returns the server's representation of the helixSagaSet, and an error, if there is any.
+func (c *FakeHelixSagaSets) Update(ctx context.Context, helixSagaSet *helixsagav1.HelixSagaSet, opts v1.UpdateOptions) (result *helixsagav1.HelixSagaSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(helixsagasetsResource, c.ns, helixSagaSet), &helixsagav1.HelixSagaSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*helixsagav1.HelixSagaSet), err
+}
+
+// Delete takes name of the helixSagaSet and deletes it. Returns an error if one occurs.
+func (c *FakeHelixSagaSets) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(helixsagasetsResource, c.ns, name), &helixsagav1.HelixSagaSet{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeHelixSagaSets) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(helixsagasetsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &helixsagav1.HelixSagaSetList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched helixSagaSet.
+func (c *FakeHelixSagaSets) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *helixsagav1.HelixSagaSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(helixsagasetsResource, c.ns, name, pt, data, subresources...), &helixsagav1.HelixSagaSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*helixsagav1.HelixSagaSet), err
+}
@@ -0,0 +1,122 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	helixsagav1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeHelixSagaTemplates implements HelixSagaTemplateInterface
+type FakeHelixSagaTemplates struct {
+	Fake *FakeNevercaseV1
+}
+
+var helixsagatemplatesResource = schema.GroupVersionResource{Group: "nevercase.io", Version: "v1", Resource: "helixsagatemplates"}
+
+var helixsagatemplatesKind = schema.GroupVersionKind{Group: "nevercase.io", Version: "v1", Kind: "HelixSagaTemplate"}
+
+// Get takes name of the helixSagaTemplate, and returns the corresponding helixSagaTemplate object, and an error if there is any.
+func (c *FakeHelixSagaTemplates) Get(ctx context.Context, name string, options v1.GetOptions) (result *helixsagav1.HelixSagaTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(helixsagatemplatesResource, name), &helixsagav1.HelixSagaTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*helixsagav1.HelixSagaTemplate), err
+}
+
+// List takes label and field selectors, and returns the list of HelixSagaTemplates that match those selectors.
+func (c *FakeHelixSagaTemplates) List(ctx context.Context, opts v1.ListOptions) (result *helixsagav1.HelixSagaTemplateList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(helixsagatemplatesResource, helixsagatemplatesKind, opts), &helixsagav1.HelixSagaTemplateList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &helixsagav1.HelixSagaTemplateList{ListMeta: obj.(*helixsagav1.HelixSagaTemplateList).ListMeta}
+	for _, item := range obj.(*helixsagav1.HelixSagaTemplateList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested helixSagaTemplates.
+func (c *FakeHelixSagaTemplates) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(helixsagatemplatesResource, opts))
+}
+
+// Create takes the representation of a helixSagaTemplate and creates it.  Returns the server's representation of the helixSagaTemplate, and an error, if there is any.
+func (c *FakeHelixSagaTemplates) Create(ctx context.Context, helixSagaTemplate *helixsagav1.HelixSagaTemplate, opts v1.CreateOptions) (result *helixsagav1.HelixSagaTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(helixsagatemplatesResource, helixSagaTemplate), &helixsagav1.HelixSagaTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*helixsagav1.HelixSagaTemplate), err
+}
+
+// Update takes the representation of a helixSagaTemplate and updates it. Returns the server's representation of the helixSagaTemplate, and an error, if there is any.
+func (c *FakeHelixSagaTemplates) Update(ctx context.Context, helixSagaTemplate *helixsagav1.HelixSagaTemplate, opts v1.UpdateOptions) (result *helixsagav1.HelixSagaTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(helixsagatemplatesResource, helixSagaTemplate), &helixsagav1.HelixSagaTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*helixsagav1.HelixSagaTemplate), err
+}
+
+// Delete takes name of the helixSagaTemplate and deletes it. Returns an error if one occurs.
+func (c *FakeHelixSagaTemplates) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(helixsagatemplatesResource, name), &helixsagav1.HelixSagaTemplate{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeHelixSagaTemplates) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(helixsagatemplatesResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &helixsagav1.HelixSagaTemplateList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched helixSagaTemplate.
+func (c *FakeHelixSagaTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *helixsagav1.HelixSagaTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(helixsagatemplatesResource, name, pt, data, subresources...), &helixsagav1.HelixSagaTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*helixsagav1.HelixSagaTemplate), err
+}
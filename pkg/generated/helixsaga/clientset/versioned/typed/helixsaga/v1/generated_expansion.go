@@ -19,3 +19,7 @@ limitations under the License.
 package v1
 
 type HelixSagaExpansion interface{}
+
+type HelixSagaSetExpansion interface{}
+
+type HelixSagaTemplateExpansion interface{}
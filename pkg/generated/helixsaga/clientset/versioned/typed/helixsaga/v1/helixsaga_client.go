@@ -27,6 +27,8 @@ import (
 type NevercaseV1Interface interface {
 	RESTClient() rest.Interface
 	HelixSagasGetter
+	HelixSagaSetsGetter
+	HelixSagaTemplatesGetter
 }
 
 // NevercaseV1Client is used to interact with features provided by the nevercase.io group.
@@ -38,6 +40,14 @@ func (c *NevercaseV1Client) HelixSagas(namespace string) HelixSagaInterface {
 	return newHelixSagas(c, namespace)
 }
 
+func (c *NevercaseV1Client) HelixSagaSets(namespace string) HelixSagaSetInterface {
+	return newHelixSagaSets(c, namespace)
+}
+
+func (c *NevercaseV1Client) HelixSagaTemplates() HelixSagaTemplateInterface {
+	return newHelixSagaTemplates(c)
+}
+
 // NewForConfig creates a new NevercaseV1Client for the given config.
 func NewForConfig(c *rest.Config) (*NevercaseV1Client, error) {
 	config := *c
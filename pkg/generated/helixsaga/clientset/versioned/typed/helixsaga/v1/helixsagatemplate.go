@@ -0,0 +1,168 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	scheme "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// HelixSagaTemplatesGetter has a method to return a HelixSagaTemplateInterface.
+// A group's client should implement this interface.
+type HelixSagaTemplatesGetter interface {
+	HelixSagaTemplates() HelixSagaTemplateInterface
+}
+
+// HelixSagaTemplateInterface has methods to work with HelixSagaTemplate resources.
+type HelixSagaTemplateInterface interface {
+	Create(ctx context.Context, helixSagaTemplate *v1.HelixSagaTemplate, opts metav1.CreateOptions) (*v1.HelixSagaTemplate, error)
+	Update(ctx context.Context, helixSagaTemplate *v1.HelixSagaTemplate, opts metav1.UpdateOptions) (*v1.HelixSagaTemplate, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.HelixSagaTemplate, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.HelixSagaTemplateList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.HelixSagaTemplate, err error)
+	HelixSagaTemplateExpansion
+}
+
+// helixSagaTemplates implements HelixSagaTemplateInterface
+type helixSagaTemplates struct {
+	client rest.Interface
+}
+
+// newHelixSagaTemplates returns a HelixSagaTemplates
+func newHelixSagaTemplates(c *NevercaseV1Client) *helixSagaTemplates {
+	return &helixSagaTemplates{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the helixSagaTemplate, and returns the corresponding helixSagaTemplate object, and an error if there is any.
+func (c *helixSagaTemplates) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.HelixSagaTemplate, err error) {
+	result = &v1.HelixSagaTemplate{}
+	err = c.client.Get().
+		Resource("helixsagatemplates").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of HelixSagaTemplates that match those selectors.
+func (c *helixSagaTemplates) List(ctx context.Context, opts metav1.ListOptions) (result *v1.HelixSagaTemplateList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.HelixSagaTemplateList{}
+	err = c.client.Get().
+		Resource("helixsagatemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested helixSagaTemplates.
+func (c *helixSagaTemplates) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("helixsagatemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a helixSagaTemplate and creates it.  Returns the server's representation of the helixSagaTemplate, and an error, if there is any.
+func (c *helixSagaTemplates) Create(ctx context.Context, helixSagaTemplate *v1.HelixSagaTemplate, opts metav1.CreateOptions) (result *v1.HelixSagaTemplate, err error) {
+	result = &v1.HelixSagaTemplate{}
+	err = c.client.Post().
+		Resource("helixsagatemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(helixSagaTemplate).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a helixSagaTemplate and updates it. Returns the server's representation of the helixSagaTemplate, and an error, if there is any.
+func (c *helixSagaTemplates) Update(ctx context.Context, helixSagaTemplate *v1.HelixSagaTemplate, opts metav1.UpdateOptions) (result *v1.HelixSagaTemplate, err error) {
+	result = &v1.HelixSagaTemplate{}
+	err = c.client.Put().
+		Resource("helixsagatemplates").
+		Name(helixSagaTemplate.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(helixSagaTemplate).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the helixSagaTemplate and deletes it. Returns an error if one occurs.
+func (c *helixSagaTemplates) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("helixsagatemplates").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *helixSagaTemplates) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("helixsagatemplates").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched helixSagaTemplate.
+func (c *helixSagaTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.HelixSagaTemplate, err error) {
+	result = &v1.HelixSagaTemplate{}
+	err = c.client.Patch(pt).
+		Resource("helixsagatemplates").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v2 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v2"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeHelixSagas implements HelixSagaInterface
+type FakeHelixSagas struct {
+	Fake *FakeNevercaseV2
+	ns   string
+}
+
+var helixsagasResource = schema.GroupVersionResource{Group: "nevercase.io", Version: "v2", Resource: "helixsagas"}
+
+var helixsagasKind = schema.GroupVersionKind{Group: "nevercase.io", Version: "v2", Kind: "HelixSaga"}
+
+// Get takes name of the helixSaga, and returns the corresponding helixSaga object, and an error if there is any.
+func (c *FakeHelixSagas) Get(ctx context.Context, name string, options v1.GetOptions) (result *v2.HelixSaga, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(helixsagasResource, c.ns, name), &v2.HelixSaga{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v2.HelixSaga), err
+}
+
+// List takes label and field selectors, and returns the list of HelixSagas that match those selectors.
+func (c *FakeHelixSagas) List(ctx context.Context, opts v1.ListOptions) (result *v2.HelixSagaList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(helixsagasResource, helixsagasKind, c.ns, opts), &v2.HelixSagaList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v2.HelixSagaList{ListMeta: obj.(*v2.HelixSagaList).ListMeta}
+	for _, item := range obj.(*v2.HelixSagaList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested helixSagas.
+func (c *FakeHelixSagas) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(helixsagasResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a helixSaga and creates it.  Returns the server's representation of the helixSaga, and an error, if there is any.
+func (c *FakeHelixSagas) Create(ctx context.Context, helixSaga *v2.HelixSaga, opts v1.CreateOptions) (result *v2.HelixSaga, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(helixsagasResource, c.ns, helixSaga), &v2.HelixSaga{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v2.HelixSaga), err
+}
+
+// Update takes the representation of a helixSaga and updates it. Returns the server's representation of the helixSaga, and an error, if there is any.
+func (c *FakeHelixSagas) Update(ctx context.Context, helixSaga *v2.HelixSaga, opts v1.UpdateOptions) (result *v2.HelixSaga, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(helixsagasResource, c.ns, helixSaga), &v2.HelixSaga{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v2.HelixSaga), err
+}
+
+// Delete takes name of the helixSaga and deletes it. Returns an error if one occurs.
+func (c *FakeHelixSagas) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(helixsagasResource, c.ns, name), &v2.HelixSaga{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeHelixSagas) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(helixsagasResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v2.HelixSagaList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched helixSaga.
+func (c *FakeHelixSagas) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v2.HelixSaga, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(helixsagasResource, c.ns, name, pt, data, subresources...), &v2.HelixSaga{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v2.HelixSaga), err
+}
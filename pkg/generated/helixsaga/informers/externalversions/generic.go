@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	v1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	v2 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v2"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -55,6 +56,14 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=nevercase.io, Version=v1
 	case v1.SchemeGroupVersion.WithResource("helixsagas"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nevercase().V1().HelixSagas().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("helixsagasets"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nevercase().V1().HelixSagaSets().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("helixsagatemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nevercase().V1().HelixSagaTemplates().Informer()}, nil
+
+		// Group=nevercase.io, Version=v2
+	case v2.SchemeGroupVersion.WithResource("helixsagas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nevercase().V2().HelixSagas().Informer()}, nil
 
 	}
 
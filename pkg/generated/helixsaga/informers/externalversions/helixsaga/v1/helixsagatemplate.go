@@ -0,0 +1,89 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	helixsagav1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	versioned "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/clientset/versioned"
+	internalinterfaces "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/informers/externalversions/internalinterfaces"
+	v1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/generated/helixsaga/listers/helixsaga/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// HelixSagaTemplateInformer provides access to a shared informer and lister for
+// HelixSagaTemplates.
+type HelixSagaTemplateInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.HelixSagaTemplateLister
+}
+
+type helixSagaTemplateInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewHelixSagaTemplateInformer constructs a new informer for HelixSagaTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewHelixSagaTemplateInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredHelixSagaTemplateInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredHelixSagaTemplateInformer constructs a new informer for HelixSagaTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredHelixSagaTemplateInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.NevercaseV1().HelixSagaTemplates().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.NevercaseV1().HelixSagaTemplates().Watch(context.TODO(), options)
+			},
+		},
+		&helixsagav1.HelixSagaTemplate{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *helixSagaTemplateInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredHelixSagaTemplateInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *helixSagaTemplateInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&helixsagav1.HelixSagaTemplate{}, f.defaultInformer)
+}
+
+func (f *helixSagaTemplateInformer) Lister() v1.HelixSagaTemplateLister {
+	return v1.NewHelixSagaTemplateLister(f.Informer().GetIndexer())
+}
@@ -26,6 +26,10 @@ import (
 type Interface interface {
 	// HelixSagas returns a HelixSagaInformer.
 	HelixSagas() HelixSagaInformer
+	// HelixSagaSets returns a HelixSagaSetInformer.
+	HelixSagaSets() HelixSagaSetInformer
+	// HelixSagaTemplates returns a HelixSagaTemplateInformer.
+	HelixSagaTemplates() HelixSagaTemplateInformer
 }
 
 type version struct {
@@ -43,3 +47,13 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) HelixSagas() HelixSagaInformer {
 	return &helixSagaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// HelixSagaSets returns a HelixSagaSetInformer.
+func (v *version) HelixSagaSets() HelixSagaSetInformer {
+	return &helixSagaSetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// HelixSagaTemplates returns a HelixSagaTemplateInformer.
+func (v *version) HelixSagaTemplates() HelixSagaTemplateInformer {
+	return &helixSagaTemplateInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
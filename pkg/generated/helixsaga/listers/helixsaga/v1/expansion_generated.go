@@ -25,3 +25,15 @@ type HelixSagaListerExpansion interface{}
 // HelixSagaNamespaceListerExpansion allows custom methods to be added to
 // HelixSagaNamespaceLister.
 type HelixSagaNamespaceListerExpansion interface{}
+
+// HelixSagaSetListerExpansion allows custom methods to be added to
+// HelixSagaSetLister.
+type HelixSagaSetListerExpansion interface{}
+
+// HelixSagaSetNamespaceListerExpansion allows custom methods to be added to
+// HelixSagaSetNamespaceLister.
+type HelixSagaSetNamespaceListerExpansion interface{}
+
+// HelixSagaTemplateListerExpansion allows custom methods to be added to
+// HelixSagaTemplateLister.
+type HelixSagaTemplateListerExpansion interface{}
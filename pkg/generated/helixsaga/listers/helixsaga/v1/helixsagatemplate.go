@@ -0,0 +1,68 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// HelixSagaTemplateLister helps list HelixSagaTemplates.
+// All objects returned here must be treated as read-only.
+type HelixSagaTemplateLister interface {
+	// List lists all HelixSagaTemplates in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.HelixSagaTemplate, err error)
+	// Get retrieves the HelixSagaTemplate from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.HelixSagaTemplate, error)
+	HelixSagaTemplateListerExpansion
+}
+
+// helixSagaTemplateLister implements the HelixSagaTemplateLister interface.
+type helixSagaTemplateLister struct {
+	indexer cache.Indexer
+}
+
+// NewHelixSagaTemplateLister returns a new HelixSagaTemplateLister.
+func NewHelixSagaTemplateLister(indexer cache.Indexer) HelixSagaTemplateLister {
+	return &helixSagaTemplateLister{indexer: indexer}
+}
+
+// List lists all HelixSagaTemplates in the indexer.
+func (s *helixSagaTemplateLister) List(selector labels.Selector) (ret []*v1.HelixSagaTemplate, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.HelixSagaTemplate))
+	})
+	return ret, err
+}
+
+// Get retrieves the HelixSagaTemplate from the index for a given name.
+func (s *helixSagaTemplateLister) Get(name string) (*v1.HelixSagaTemplate, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("helixsagatemplate"), name)
+	}
+	return obj.(*v1.HelixSagaTemplate), nil
+}
@@ -0,0 +1,104 @@
+package imagepolicy
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	helixsagav1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+)
+
+// Tag is a tag of the repository in the registry
+type Tag struct {
+	Name     string
+	PushTime time.Time
+}
+
+// Validate checks the ImagePolicy
+func Validate(p *helixsagav1.ImagePolicy) error {
+	_, _, err := compile(p)
+	return err
+}
+
+func compile(p *helixsagav1.ImagePolicy) (*Range, *regexp.Regexp, error) {
+	var (
+		r   *Range
+		reg *regexp.Regexp
+		err error
+	)
+	if p.Pattern != "" {
+		if reg, err = regexp.Compile(p.Pattern); err != nil {
+			return nil, nil, fmt.Errorf("invalid image policy pattern %q: %v", p.Pattern, err)
+		}
+	}
+	switch p.Type {
+	case helixsagav1.ImagePolicyTypeSemver:
+		if p.Range == "" {
+			return nil, nil, fmt.Errorf("the range of the image policy semver was empty")
+		}
+		if r, err = ParseRange(p.Range); err != nil {
+			return nil, nil, err
+		}
+	case helixsagav1.ImagePolicyTypeRegex:
+		if reg == nil {
+			return nil, nil, fmt.Errorf("the pattern of the image policy regex was empty")
+		}
+	case helixsagav1.ImagePolicyTypeNewest:
+	default:
+		return nil, nil, fmt.Errorf("unknown image policy type %q", p.Type)
+	}
+	return r, reg, nil
+}
+
+// Select returns the best tag of the ImagePolicy and the reason why it was selected.
+// An error would be returned if the ImagePolicy was invalid or no tag has been matched.
+func Select(p *helixsagav1.ImagePolicy, tags []Tag) (Tag, string, error) {
+	var best Tag
+	r, reg, err := compile(p)
+	if err != nil {
+		return best, "", err
+	}
+	var (
+		found   bool
+		version Version
+	)
+	for _, t := range tags {
+		if reg != nil && !reg.MatchString(t.Name) {
+			continue
+		}
+		switch p.Type {
+		case helixsagav1.ImagePolicyTypeSemver:
+			v, err := ParseVersion(t.Name)
+			if err != nil || !r.Check(v) {
+				continue
+			}
+			// prefer the newer push of the same version like v1.4.2 and 1.4.2
+			if c := v.Compare(version); !found || c > 0 || (c == 0 && t.PushTime.After(best.PushTime)) {
+				best, version, found = t, v, true
+			}
+		default:
+			if !found || t.PushTime.After(best.PushTime) || (t.PushTime.Equal(best.PushTime) && t.Name > best.Name) {
+				best, found = t, true
+			}
+		}
+	}
+	if !found {
+		return best, "", fmt.Errorf("no tag matched the image policy %s", describe(p))
+	}
+	switch p.Type {
+	case helixsagav1.ImagePolicyTypeSemver:
+		return best, fmt.Sprintf("%s is the highest version in the range %q", version, p.Range), nil
+	}
+	return best, fmt.Sprintf("the newest tag of the image policy %s was pushed at %s", describe(p), best.PushTime.UTC().Format(time.RFC3339)), nil
+}
+
+func describe(p *helixsagav1.ImagePolicy) string {
+	s := string(p.Type)
+	if p.Range != "" {
+		s = fmt.Sprintf("%s range:%q", s, p.Range)
+	}
+	if p.Pattern != "" {
+		s = fmt.Sprintf("%s pattern:%q", s, p.Pattern)
+	}
+	return s
+}
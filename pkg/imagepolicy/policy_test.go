@@ -0,0 +1,81 @@
+package imagepolicy
+
+import (
+	"testing"
+	"time"
+
+	helixsagav1 "github.com/Shanghai-Lunara/helixsaga-operator/pkg/apis/helixsaga/v1"
+)
+
+func TestSelect(t *testing.T) {
+	now := time.Now()
+	tags := []Tag{
+		{Name: "latest", PushTime: now},
+		{Name: "1.3.0", PushTime: now.Add(-time.Hour * 5)},
+		{Name: "1.4.0", PushTime: now.Add(-time.Hour * 4)},
+		{Name: "v1.5.1", PushTime: now.Add(-time.Hour * 2)},
+		{Name: "1.5.0", PushTime: now.Add(-time.Hour * 1)},
+		{Name: "2.0.0", PushTime: now.Add(-time.Hour * 3)},
+		{Name: "release-9", PushTime: now.Add(-time.Minute * 10)},
+		{Name: "release-10", PushTime: now.Add(-time.Minute * 20)},
+	}
+	tests := []struct {
+		name    string
+		policy  helixsagav1.ImagePolicy
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "TestSelect_semver",
+			policy: helixsagav1.ImagePolicy{Type: helixsagav1.ImagePolicyTypeSemver, Range: ">=1.4.0 <2"},
+			want:   "v1.5.1",
+		},
+		{
+			name:   "TestSelect_semver_pattern",
+			policy: helixsagav1.ImagePolicy{Type: helixsagav1.ImagePolicyTypeSemver, Range: ">=1.4.0 <2", Pattern: `^\d`},
+			want:   "1.5.0",
+		},
+		{
+			name:   "TestSelect_regex",
+			policy: helixsagav1.ImagePolicy{Type: helixsagav1.ImagePolicyTypeRegex, Pattern: `^release-\d+$`},
+			want:   "release-9",
+		},
+		{
+			name:   "TestSelect_newest",
+			policy: helixsagav1.ImagePolicy{Type: helixsagav1.ImagePolicyTypeNewest},
+			want:   "latest",
+		},
+		{
+			name:    "TestSelect_not_matched",
+			policy:  helixsagav1.ImagePolicy{Type: helixsagav1.ImagePolicyTypeSemver, Range: ">=3"},
+			wantErr: true,
+		},
+		{
+			name:    "TestSelect_regex_without_pattern",
+			policy:  helixsagav1.ImagePolicy{Type: helixsagav1.ImagePolicyTypeRegex},
+			wantErr: true,
+		},
+		{
+			name:    "TestSelect_unknown_type",
+			policy:  helixsagav1.ImagePolicy{Type: "oldest"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason, err := Select(&tt.policy, tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Select() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Name != tt.want {
+				t.Errorf("Select() = %v, want %v", got.Name, tt.want)
+			}
+			if reason == "" {
+				t.Errorf("Select() reason was empty")
+			}
+		})
+	}
+}
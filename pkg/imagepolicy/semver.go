@@ -0,0 +1,236 @@
+package imagepolicy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version parsed from an image tag
+type Version struct {
+	Major      int64
+	Minor      int64
+	Patch      int64
+	Prerelease []string
+}
+
+// ParseVersion parses the tag like v1.4.2, 1.4.2-rc.1 or 1.4 as a Version, the build metadata would be ignored
+func ParseVersion(s string) (Version, error) {
+	return parseVersion(s, 2)
+}
+
+func parseVersion(s string, minParts int) (Version, error) {
+	v, _, err := parseVersionParts(s, minParts)
+	return v, err
+}
+
+// parseVersionParts also returns the number of the parts which were given, e.g. 2 for 1.4
+func parseVersionParts(s string, minParts int) (Version, int, error) {
+	var v Version
+	t := strings.TrimPrefix(s, "v")
+	if i := strings.Index(t, "+"); i >= 0 {
+		t = t[:i]
+	}
+	if i := strings.Index(t, "-"); i >= 0 {
+		if i == len(t)-1 {
+			return v, 0, fmt.Errorf("invalid version %q: empty prerelease", s)
+		}
+		v.Prerelease = strings.Split(t[i+1:], ".")
+		t = t[:i]
+	}
+	parts := strings.Split(t, ".")
+	if len(parts) < minParts || len(parts) > 3 {
+		return v, 0, fmt.Errorf("invalid version %q", s)
+	}
+	nums := make([]int64, 3)
+	for i, p := range parts {
+		if p == "" || (len(p) > 1 && p[0] == '0') {
+			return v, 0, fmt.Errorf("invalid version %q", s)
+		}
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || n < 0 {
+			return v, 0, fmt.Errorf("invalid version %q", s)
+		}
+		nums[i] = n
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, len(parts), nil
+}
+
+// Compare returns -1, 0 or 1 if the v was lower than, equal to or higher than the o
+func (v Version) Compare(o Version) int {
+	for _, d := range []int64{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	// a version without the prerelease has a higher precedence
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.Prerelease) < len(o.Prerelease):
+		return -1
+	case len(v.Prerelease) > len(o.Prerelease):
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier compares the numeric identifiers numerically and the others lexically,
+// the numeric identifiers have a lower precedence than the others
+func compareIdentifier(a, b string) int {
+	na, errA := strconv.ParseInt(a, 10, 64)
+	nb, errB := strconv.ParseInt(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s = fmt.Sprintf("%s-%s", s, strings.Join(v.Prerelease, "."))
+	}
+	return s
+}
+
+type constraint struct {
+	op      string
+	version Version
+}
+
+func (c constraint) check(v Version) bool {
+	r := v.Compare(c.version)
+	switch c.op {
+	case "", "=":
+		return r == 0
+	case "!=":
+		return r != 0
+	case ">":
+		return r > 0
+	case ">=":
+		return r >= 0
+	case "<":
+		return r < 0
+	case "<=":
+		return r <= 0
+	}
+	return false
+}
+
+// Range is a set of the semver constraints like ">=1.4.0 <2 || ^3.1".
+// The space separated constraints must be all satisfied, and one of the groups separated by || must be satisfied.
+// Besides the comparisons, ~1.4 means >=1.4.0 <1.5.0 and ~1 means >=1.0.0 <2.0.0.
+// The ^ allows the changes which don't modify the left-most non-zero part as the npm does,
+// ^1.4 means >=1.4.0 <2.0.0, ^0.2.3 means >=0.2.3 <0.3.0 and ^0.0.3 means >=0.0.3 <0.0.4.
+// The prerelease versions only match the groups which have a prerelease constraint of the same major.minor.patch.
+type Range struct {
+	groups [][]constraint
+}
+
+// ParseRange parses the Range from a string
+func ParseRange(s string) (*Range, error) {
+	r := &Range{}
+	for _, group := range strings.Split(s, "||") {
+		fields := strings.Fields(group)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid semver range %q: empty constraints", s)
+		}
+		cs := make([]constraint, 0, len(fields))
+		for i := 0; i < len(fields); i++ {
+			f := fields[i]
+			// allow the spaces between the operator and the version like ">= 1.4.0"
+			if strings.Trim(f, "<>=!~^") == "" && i+1 < len(fields) {
+				f += fields[i+1]
+				i++
+			}
+			t, err := parseConstraint(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid semver range %q: %v", s, err)
+			}
+			cs = append(cs, t...)
+		}
+		r.groups = append(r.groups, cs)
+	}
+	return r, nil
+}
+
+func parseConstraint(s string) ([]constraint, error) {
+	op := s[:len(s)-len(strings.TrimLeft(s, "<>=!~^"))]
+	v, parts, err := parseVersionParts(s[len(op):], 1)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "", "=", "!=", ">", ">=", "<", "<=":
+		return []constraint{{op: op, version: v}}, nil
+	case "~":
+		upper := Version{Major: v.Major, Minor: v.Minor + 1}
+		if parts == 1 {
+			upper = Version{Major: v.Major + 1}
+		}
+		return []constraint{{op: ">=", version: v}, {op: "<", version: upper}}, nil
+	case "^":
+		upper := Version{Major: v.Major + 1}
+		switch {
+		case v.Major > 0:
+		case v.Minor > 0 || parts == 2:
+			// ^0.2.3 and ^0.0
+			upper = Version{Minor: v.Minor + 1}
+		case parts == 3:
+			// ^0.0.3
+			upper = Version{Patch: v.Patch + 1}
+		}
+		return []constraint{{op: ">=", version: v}, {op: "<", version: upper}}, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+// Check reports whether the version satisfies the Range
+func (r *Range) Check(v Version) bool {
+	for _, group := range r.groups {
+		if checkGroup(group, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkGroup(group []constraint, v Version) bool {
+	prerelease := len(v.Prerelease) == 0
+	for _, c := range group {
+		if !c.check(v) {
+			return false
+		}
+		if len(c.version.Prerelease) > 0 && c.version.Major == v.Major && c.version.Minor == v.Minor && c.version.Patch == v.Patch {
+			prerelease = true
+		}
+	}
+	return prerelease
+}
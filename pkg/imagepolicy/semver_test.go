@@ -0,0 +1,104 @@
+package imagepolicy
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		{name: "TestParseVersion_full", tag: "1.4.2", want: "1.4.2"},
+		{name: "TestParseVersion_prefix", tag: "v1.4.2", want: "1.4.2"},
+		{name: "TestParseVersion_minor", tag: "1.4", want: "1.4.0"},
+		{name: "TestParseVersion_prerelease", tag: "1.4.2-rc.1+build.7", want: "1.4.2-rc.1"},
+		{name: "TestParseVersion_major_only", tag: "20210101", wantErr: true},
+		{name: "TestParseVersion_leading_zero", tag: "1.04.2", wantErr: true},
+		{name: "TestParseVersion_latest", tag: "latest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVersion(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	// in the ascending order
+	versions := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0", "2.0.0"}
+	for i := 0; i < len(versions)-1; i++ {
+		a, err := ParseVersion(versions[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseVersion(versions[i+1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Compare(b) != -1 || b.Compare(a) != 1 || a.Compare(a) != 0 {
+			t.Errorf("Compare() %v and %v was not in order", a, b)
+		}
+	}
+}
+
+func TestRangeCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       string
+		version string
+		want    bool
+	}{
+		{name: "TestRangeCheck_in", r: ">=1.4.0 <2", version: "1.9.3", want: true},
+		{name: "TestRangeCheck_lower", r: ">=1.4.0 <2", version: "1.3.9", want: false},
+		{name: "TestRangeCheck_upper", r: ">=1.4.0 <2", version: "2.0.0", want: false},
+		{name: "TestRangeCheck_spaces", r: ">= 1.4.0 < 2", version: "1.4.0", want: true},
+		{name: "TestRangeCheck_prerelease_excluded", r: ">=1.4.0 <2", version: "1.5.0-rc.1", want: false},
+		{name: "TestRangeCheck_prerelease_included", r: ">=1.5.0-rc.0 <2", version: "1.5.0-rc.1", want: true},
+		{name: "TestRangeCheck_tilde", r: "~1.4", version: "1.4.7", want: true},
+		{name: "TestRangeCheck_tilde_upper", r: "~1.4", version: "1.5.0", want: false},
+		{name: "TestRangeCheck_caret", r: "^1.4", version: "1.9.0", want: true},
+		{name: "TestRangeCheck_caret_upper", r: "^1.4", version: "2.0.0", want: false},
+		{name: "TestRangeCheck_caret_zero_major", r: "^0.2.3", version: "0.2.9", want: true},
+		{name: "TestRangeCheck_caret_zero_major_upper", r: "^0.2.3", version: "0.3.0", want: false},
+		{name: "TestRangeCheck_caret_zero_major_lower", r: "^0.2.3", version: "0.2.2", want: false},
+		{name: "TestRangeCheck_caret_zero_minor", r: "^0.0.3", version: "0.0.3", want: true},
+		{name: "TestRangeCheck_caret_zero_minor_upper", r: "^0.0.3", version: "0.0.4", want: false},
+		{name: "TestRangeCheck_caret_zero_partial", r: "^0.0", version: "0.0.9", want: true},
+		{name: "TestRangeCheck_caret_zero_partial_upper", r: "^0.0", version: "0.1.0", want: false},
+		{name: "TestRangeCheck_caret_zero", r: "^0", version: "0.9.0", want: true},
+		{name: "TestRangeCheck_caret_zero_upper", r: "^0", version: "1.0.0", want: false},
+		{name: "TestRangeCheck_tilde_major", r: "~1", version: "1.9.0", want: true},
+		{name: "TestRangeCheck_tilde_major_upper", r: "~1", version: "2.0.0", want: false},
+		{name: "TestRangeCheck_or", r: "^1.4 || ^3", version: "3.1.0", want: true},
+		{name: "TestRangeCheck_not", r: ">=1 !=1.2.0", version: "1.2.0", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRange(tt.r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := ParseVersion(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.Check(v); got != tt.want {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	for _, s := range []string{"", ">=1.4.0 ||", "=>1.4", ">=latest"} {
+		if _, err := ParseRange(s); err == nil {
+			t.Errorf("ParseRange(%q) error = nil, want an error", s)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package registrywebhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PushEvent is an image which has been pushed into the registry
+type PushEvent struct {
+	// Domain was the host of the registry, it could be empty if the payload didn't carry it
+	Domain     string
+	Project    string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// Image returns the image in the form of domain/project/repository:tag
+func (e PushEvent) Image() string {
+	if e.Domain == "" {
+		return fmt.Sprintf("%s/%s:%s", e.Project, e.Repository, e.Tag)
+	}
+	return fmt.Sprintf("%s/%s/%s:%s", e.Domain, e.Project, e.Repository, e.Tag)
+}
+
+const (
+	harborPushArtifact = "PUSH_ARTIFACT"
+	harborPushImage    = "pushImage"
+
+	registryActionPush = "push"
+)
+
+// harborPayload is the payload of the Harbor webhook
+type harborPayload struct {
+	Type      string `json:"type"`
+	EventData struct {
+		Resources []struct {
+			Digest      string `json:"digest"`
+			Tag         string `json:"tag"`
+			ResourceURL string `json:"resource_url"`
+		} `json:"resources"`
+		Repository struct {
+			Name         string `json:"name"`
+			Namespace    string `json:"namespace"`
+			RepoFullName string `json:"repo_full_name"`
+		} `json:"repository"`
+	} `json:"event_data"`
+}
+
+// registryPayload is the envelope of the Docker Registry notifications
+type registryPayload struct {
+	Events []struct {
+		Action string `json:"action"`
+		Target struct {
+			MediaType  string `json:"mediaType"`
+			Digest     string `json:"digest"`
+			Repository string `json:"repository"`
+			Tag        string `json:"tag"`
+		} `json:"target"`
+		Request struct {
+			Host string `json:"host"`
+		} `json:"request"`
+	} `json:"events"`
+}
+
+// genericPayload is the payload of the other registries, the repository could be prefixed by the domain
+type genericPayload struct {
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	Digest     string `json:"digest"`
+}
+
+// Parse returns the PushEvents in the payload of Harbor, Docker Registry notifications or the generic one.
+// The events which were not pushes of a tag would be ignored.
+func Parse(data []byte) ([]PushEvent, error) {
+	probe := make(map[string]json.RawMessage, 0)
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+	switch {
+	case probe["event_data"] != nil:
+		return parseHarbor(data)
+	case probe["events"] != nil:
+		return parseRegistry(data)
+	case probe["repository"] != nil:
+		return parseGeneric(data)
+	}
+	return nil, fmt.Errorf("unknown payload of the registry webhook")
+}
+
+func parseHarbor(data []byte) ([]PushEvent, error) {
+	p := &harborPayload{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	if p.Type != harborPushArtifact && p.Type != harborPushImage {
+		return nil, nil
+	}
+	res := make([]PushEvent, 0, len(p.EventData.Resources))
+	for _, v := range p.EventData.Resources {
+		if v.Tag == "" {
+			continue
+		}
+		repo := p.EventData.Repository.RepoFullName
+		if repo == "" {
+			repo = fmt.Sprintf("%s/%s", p.EventData.Repository.Namespace, p.EventData.Repository.Name)
+		}
+		e, err := newPushEvent(domainOfResourceURL(v.ResourceURL), repo, v.Tag, v.Digest)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+func parseRegistry(data []byte) ([]PushEvent, error) {
+	p := &registryPayload{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	res := make([]PushEvent, 0, len(p.Events))
+	for _, v := range p.Events {
+		// the pushes of the blobs have no tag
+		if v.Action != registryActionPush || v.Target.Tag == "" {
+			continue
+		}
+		e, err := newPushEvent(v.Request.Host, v.Target.Repository, v.Target.Tag, v.Target.Digest)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+func parseGeneric(data []byte) ([]PushEvent, error) {
+	p := &genericPayload{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	var domain string
+	repo := p.Repository
+	if t := strings.SplitN(repo, "/", 2); len(t) == 2 && isDomain(t[0]) {
+		domain, repo = t[0], t[1]
+	}
+	e, err := newPushEvent(domain, repo, p.Tag, p.Digest)
+	if err != nil {
+		return nil, err
+	}
+	return []PushEvent{e}, nil
+}
+
+func newPushEvent(domain, repo, tag, digest string) (PushEvent, error) {
+	t := strings.SplitN(repo, "/", 2)
+	if len(t) != 2 || t[0] == "" || t[1] == "" {
+		return PushEvent{}, fmt.Errorf("invalid repository %q, it must be in the form of project/repository", repo)
+	}
+	if tag == "" {
+		return PushEvent{}, fmt.Errorf("repository %q: tag must be specified", repo)
+	}
+	if digest == "" {
+		return PushEvent{}, fmt.Errorf("repository %q: digest must be specified", repo)
+	}
+	return PushEvent{
+		Domain:     domain,
+		Project:    t[0],
+		Repository: t[1],
+		Tag:        tag,
+		Digest:     digest,
+	}, nil
+}
+
+// domainOfResourceURL returns the domain of the resource_url like harbor.domain.com/helix-saga/go-all:latest
+func domainOfResourceURL(s string) string {
+	t := strings.SplitN(s, "/", 2)
+	if len(t) != 2 || !isDomain(t[0]) {
+		return ""
+	}
+	return t[0]
+}
+
+// isDomain follows the docker convention that the first component was a domain if it contains a dot or a port
+func isDomain(s string) bool {
+	return strings.ContainsAny(s, ".:") || s == "localhost"
+}
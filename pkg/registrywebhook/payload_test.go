@@ -0,0 +1,82 @@
+package registrywebhook
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []PushEvent
+		wantErr bool
+	}{
+		{
+			name: "TestParse_harbor",
+			data: `{"type":"PUSH_ARTIFACT","event_data":{"resources":[{"digest":"sha256:abc","tag":"latest","resource_url":"harbor.domain.com/helix-saga/go-all:latest"}],"repository":{"name":"go-all","namespace":"helix-saga","repo_full_name":"helix-saga/go-all"}}}`,
+			want: []PushEvent{
+				{Domain: "harbor.domain.com", Project: "helix-saga", Repository: "go-all", Tag: "latest", Digest: "sha256:abc"},
+			},
+		},
+		{
+			name: "TestParse_harbor_delete",
+			data: `{"type":"DELETE_ARTIFACT","event_data":{"resources":[{"digest":"sha256:abc","tag":"latest"}],"repository":{"repo_full_name":"helix-saga/go-all"}}}`,
+			want: nil,
+		},
+		{
+			name: "TestParse_registry",
+			data: `{"events":[{"action":"push","target":{"mediaType":"application/vnd.docker.image.rootfs.diff.tar.gzip","digest":"sha256:layer","repository":"helix-saga/go-all"}},{"action":"push","target":{"mediaType":"application/vnd.docker.distribution.manifest.v2+json","digest":"sha256:abc","repository":"helix-saga/go-all","tag":"v1.0.0"},"request":{"host":"10.0.0.1:5000"}},{"action":"pull","target":{"digest":"sha256:abc","repository":"helix-saga/go-all","tag":"v1.0.0"}}]}`,
+			want: []PushEvent{
+				{Domain: "10.0.0.1:5000", Project: "helix-saga", Repository: "go-all", Tag: "v1.0.0", Digest: "sha256:abc"},
+			},
+		},
+		{
+			name: "TestParse_generic",
+			data: `{"repository":"harbor.domain.com/helix-saga/go-all","tag":"latest","digest":"sha256:abc"}`,
+			want: []PushEvent{
+				{Domain: "harbor.domain.com", Project: "helix-saga", Repository: "go-all", Tag: "latest", Digest: "sha256:abc"},
+			},
+		},
+		{
+			name: "TestParse_generic_without_domain",
+			data: `{"repository":"helix-saga/go-all","tag":"latest","digest":"sha256:abc"}`,
+			want: []PushEvent{
+				{Project: "helix-saga", Repository: "go-all", Tag: "latest", Digest: "sha256:abc"},
+			},
+		},
+		{
+			name:    "TestParse_generic_without_digest",
+			data:    `{"repository":"helix-saga/go-all","tag":"latest"}`,
+			wantErr: true,
+		},
+		{
+			name:    "TestParse_unknown",
+			data:    `{"foo":"bar"}`,
+			wantErr: true,
+		},
+		{
+			name:    "TestParse_invalid_json",
+			data:    `{`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package registrywebhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SecretHeader is the header which carries the shared secret for the registries which couldn't set the Authorization
+	SecretHeader = "X-Webhook-Secret"
+
+	maxPayloadBytes = 1 << 20
+)
+
+// Handler receives the push webhooks of the registries and notifies every PushEvent.
+// The notify returns the number of the subscriptions which have been triggered.
+type Handler struct {
+	secret string
+	notify func(e PushEvent) int
+}
+
+// NewHandler returns the Handler which verifies the requests with the shared secret
+func NewHandler(secret string, notify func(e PushEvent) int) *Handler {
+	return &Handler{
+		secret: secret,
+		notify: notify,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorized(r) {
+		klog.V(2).Infof("registry webhook unauthorized request from:%s", r.RemoteAddr)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	events, err := Parse(data)
+	if err != nil {
+		klog.V(2).Infof("registry webhook parse err:%v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, e := range events {
+		n := h.notify(e)
+		klog.Infof("registry webhook image:%s digest:%s triggered:%d", e.Image(), e.Digest, n)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// authorized compares the secret in the Authorization header, which could be prefixed by Bearer,
+// or in the SecretHeader with the shared secret in constant time.
+// Every request would be refused without the shared secret, since a push could roll out the HelixSagas.
+func (h *Handler) authorized(r *http.Request) bool {
+	if h.secret == "" {
+		return false
+	}
+	for _, v := range []string{r.Header.Get(SecretHeader), strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")} {
+		if v != "" && subtle.ConstantTimeCompare([]byte(v), []byte(h.secret)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// ListenAndServeTLS serves the Handler on the addr until stopCh was closed.
+// It serves plain HTTP if neither the certFile nor the keyFile was given, e.g. behind a TLS terminating proxy.
+func ListenAndServeTLS(addr, certFile, keyFile string, h http.Handler, stopCh <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle("/", h)
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  time.Second * 10,
+		WriteTimeout: time.Second * 10,
+	}
+	go func() {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			klog.V(2).Info(err)
+		}
+	}()
+	klog.Infof("registry webhook listening on %s", addr)
+	var err error
+	if certFile == "" && keyFile == "" {
+		err = srv.ListenAndServe()
+	} else {
+		err = srv.ListenAndServeTLS(certFile, keyFile)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
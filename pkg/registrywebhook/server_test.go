@@ -0,0 +1,91 @@
+package registrywebhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	const payload = `{"repository":"helix-saga/go-all","tag":"latest","digest":"sha256:abc"}`
+	tests := []struct {
+		name       string
+		method     string
+		header     map[string]string
+		body       string
+		wantCode   int
+		wantNotify int
+	}{
+		{
+			name:       "TestHandler_authorization",
+			method:     http.MethodPost,
+			header:     map[string]string{"Authorization": "Bearer s3cret"},
+			body:       payload,
+			wantCode:   http.StatusAccepted,
+			wantNotify: 1,
+		},
+		{
+			name:       "TestHandler_secret_header",
+			method:     http.MethodPost,
+			header:     map[string]string{SecretHeader: "s3cret"},
+			body:       payload,
+			wantCode:   http.StatusAccepted,
+			wantNotify: 1,
+		},
+		{
+			name:     "TestHandler_wrong_secret",
+			method:   http.MethodPost,
+			header:   map[string]string{"Authorization": "wrong"},
+			body:     payload,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "TestHandler_bad_payload",
+			method:   http.MethodPost,
+			header:   map[string]string{"Authorization": "s3cret"},
+			body:     `{"foo":"bar"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "TestHandler_method",
+			method:   http.MethodGet,
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notified := 0
+			h := NewHandler("s3cret", func(e PushEvent) int {
+				notified++
+				return 1
+			})
+			r := httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body))
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.wantCode {
+				t.Errorf("ServeHTTP() code = %v, want %v", w.Code, tt.wantCode)
+			}
+			if notified != tt.wantNotify {
+				t.Errorf("ServeHTTP() notified = %v, want %v", notified, tt.wantNotify)
+			}
+		})
+	}
+}
+
+func TestHandler_emptySecret(t *testing.T) {
+	notified := 0
+	h := NewHandler("", func(e PushEvent) int {
+		notified++
+		return 1
+	})
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"repository":"helix-saga/go-all","tag":"latest"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized || notified != 0 {
+		t.Errorf("ServeHTTP() code = %v notified = %v, want %v and 0", w.Code, notified, http.StatusUnauthorized)
+	}
+}
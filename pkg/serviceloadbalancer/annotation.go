@@ -1,24 +1,54 @@
 package serviceloadbalancer
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"strings"
 
-func Annotation(svc corev1.ServiceType, isWhiteListOn bool) map[string]string {
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Annotation returns the annotations of a Service in the given type.
+// Once the sourceRanges was not empty, the allowlist takes precedence over the whiteListOn/whiteListOff groups,
+// and it would be rendered into the SourceRangesAnnotation if the provider was configured to read it from there.
+func Annotation(svc corev1.ServiceType, isWhiteListOn bool, sourceRanges []string) map[string]string {
 	annotations := make(map[string]string, 0)
 	switch svc {
 	case corev1.ServiceTypeLoadBalancer:
-		for k, v := range Get().Annotations {
+		conf := Get()
+		for k, v := range conf.Annotations {
 			annotations[k] = v
 		}
+		if len(sourceRanges) > 0 {
+			if conf.SourceRangesAnnotation != "" {
+				annotations[conf.SourceRangesAnnotation] = strings.Join(sourceRanges, ",")
+			}
+			return annotations
+		}
 		switch isWhiteListOn {
 		case true:
-			for k, v := range Get().WhiteListOn {
+			for k, v := range conf.WhiteListOn {
 				annotations[k] = v
 			}
 		case false:
-			for k, v := range Get().WhiteListOff {
+			for k, v := range conf.WhiteListOff {
 				annotations[k] = v
 			}
 		}
 	}
 	return annotations
 }
+
+// WhiteListKeys returns the annotation keys of the whiteListOn and the whiteListOff groups.
+// They were the ones which might have been set on a Service before the managed annotations have been recorded.
+func WhiteListKeys() []string {
+	conf := Get()
+	keys := make([]string, 0, len(conf.WhiteListOn)+len(conf.WhiteListOff))
+	for k := range conf.WhiteListOn {
+		keys = append(keys, k)
+	}
+	for k := range conf.WhiteListOff {
+		if _, ok := conf.WhiteListOn[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
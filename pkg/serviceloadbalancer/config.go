@@ -1,39 +1,187 @@
 package serviceloadbalancer
 
 import (
+	"bytes"
+	"io/ioutil"
+	"sync"
+	"time"
+
 	"github.com/Shanghai-Lunara/pkg/zaplogger"
 	"gopkg.in/yaml.v3"
-	"io/ioutil"
+	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 type Annotations struct {
 	Annotations  map[string]string `yaml:"annotations"`
 	WhiteListOn  map[string]string `yaml:"whiteListOn"`
 	WhiteListOff map[string]string `yaml:"whiteListOff"`
+	// SourceRangesAnnotation is the provider annotation which the allowlist would be rendered into.
+	// The allowlist would be set in the spec.loadBalancerSourceRanges of the Service if it was empty.
+	SourceRangesAnnotation string `yaml:"sourceRangesAnnotation"`
+	// SourceRanges are the shared allowlists which could be referenced by name in the serviceSourceRangeRefs
+	SourceRanges map[string][]string `yaml:"sourceRanges"`
 }
 
-var annotations *Annotations
+// Validate checks that every group of annotations could be set on a Service.
+// The annotations were required, since an empty or a half-written file would remove the managed annotations
+// like the id of the load balancer from all the Services.
+func (a *Annotations) Validate() error {
+	errs := field.ErrorList{}
+	if len(a.Annotations) == 0 {
+		errs = append(errs, field.Required(field.NewPath("annotations"), "the annotations of the load balancer were required"))
+	}
+	errs = append(errs, validation.ValidateAnnotations(a.Annotations, field.NewPath("annotations"))...)
+	errs = append(errs, validation.ValidateAnnotations(a.WhiteListOn, field.NewPath("whiteListOn"))...)
+	errs = append(errs, validation.ValidateAnnotations(a.WhiteListOff, field.NewPath("whiteListOff"))...)
+	if a.SourceRangesAnnotation != "" {
+		errs = append(errs, validation.ValidateAnnotations(map[string]string{a.SourceRangesAnnotation: ""}, field.NewPath("sourceRangesAnnotation"))...)
+	}
+	for name, cidrs := range a.SourceRanges {
+		for i, v := range cidrs {
+			if err := validateCIDR(v); err != nil {
+				errs = append(errs, field.Invalid(field.NewPath("sourceRanges").Key(name).Index(i), v, err.Error()))
+			}
+		}
+	}
+	return errs.ToAggregate()
+}
+
+// keys returns the annotation keys which would be managed by the annotations
+func (a *Annotations) keys() map[string]bool {
+	res := make(map[string]bool, 0)
+	for _, group := range []map[string]string{a.Annotations, a.WhiteListOn, a.WhiteListOff} {
+		for k := range group {
+			res[k] = true
+		}
+	}
+	if a.SourceRangesAnnotation != "" {
+		res[a.SourceRangesAnnotation] = true
+	}
+	return res
+}
+
+// dropsAllKeys reports whether none of the annotation keys managed by prev would be managed by a
+func dropsAllKeys(prev, a *Annotations) bool {
+	if prev == nil {
+		return false
+	}
+	before, after := prev.keys(), a.keys()
+	if len(before) == 0 {
+		return false
+	}
+	for k := range before {
+		if after[k] {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	mu          sync.RWMutex
+	annotations *Annotations
+	handlers    []func(*Annotations)
+)
+
+// Load reads and validates the config file without replacing the annotations in use
+func Load(configFile string) (*Annotations, error) {
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return parse(data)
+}
+
+func parse(data []byte) (*Annotations, error) {
+	a := &Annotations{}
+	if err := yaml.Unmarshal(data, a); err != nil {
+		return nil, err
+	}
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
 
 func Init(configFile string) *Annotations {
-	annotations = &Annotations{}
-	var data []byte
-	var err error
-	if data, err = ioutil.ReadFile(configFile); err != nil {
+	a, err := Load(configFile)
+	if err != nil {
 		zaplogger.Sugar().Fatal(err)
 	}
-	if err := yaml.Unmarshal(data, annotations); err != nil {
-		zaplogger.Sugar().Fatal(err)
+	Set(a)
+	return a
+}
+
+// Set replaces the annotations in use and notifies the handlers registered by OnChange
+func Set(a *Annotations) {
+	mu.Lock()
+	annotations = a
+	hs := make([]func(*Annotations), len(handlers))
+	copy(hs, handlers)
+	mu.Unlock()
+	for _, h := range hs {
+		h(a)
 	}
-	return annotations
 }
 
 func Get() *Annotations {
+	mu.RLock()
+	defer mu.RUnlock()
 	if annotations == nil {
-		annotations = &Annotations{
+		return &Annotations{
 			Annotations:  make(map[string]string, 0),
 			WhiteListOn:  make(map[string]string, 0),
 			WhiteListOff: make(map[string]string, 0),
+			SourceRanges: make(map[string][]string, 0),
 		}
 	}
 	return annotations
 }
+
+// OnChange registers a handler which would be called after the annotations have been replaced
+func OnChange(h func(*Annotations)) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers = append(handlers, h)
+}
+
+// Watch polls the config file every interval until stopCh was closed.
+// A changed file would be validated before swapping, and a bad one would only be reported,
+// so that the annotations in use were kept until the file has been fixed.
+// A file which drops all the managed annotation keys would be regarded as a bad one.
+// Polling the content also follows the symlink swap of a ConfigMap-mounted file.
+func Watch(configFile string, interval time.Duration, stopCh <-chan struct{}) {
+	last, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		zaplogger.Sugar().Errorw("serviceloadbalancer read config", "file", configFile, "err", err)
+	}
+	wait.Until(func() {
+		data, err := ioutil.ReadFile(configFile)
+		if err != nil {
+			zaplogger.Sugar().Errorw("serviceloadbalancer read config", "file", configFile, "err", err)
+			return
+		}
+		if bytes.Equal(data, last) {
+			return
+		}
+		last = data
+		a, err := parse(data)
+		if err != nil {
+			zaplogger.Sugar().Errorw("serviceloadbalancer reload config, keep the previous one", "file", configFile, "err", err)
+			return
+		}
+		// a file which replaced all the managed keys was more likely a broken one, which would detach every Service
+		// from its load balancer
+		mu.RLock()
+		prev := annotations
+		mu.RUnlock()
+		if dropsAllKeys(prev, a) {
+			zaplogger.Sugar().Errorw("serviceloadbalancer reload config drops all the managed annotations, keep the previous one", "file", configFile)
+			return
+		}
+		zaplogger.Sugar().Infow("serviceloadbalancer config reloaded", "file", configFile)
+		Set(a)
+	}, interval, stopCh)
+}
@@ -2,9 +2,11 @@ package serviceloadbalancer
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestInit(t *testing.T) {
@@ -124,3 +126,109 @@ func TestPointer(t *testing.T) {
 	fmt.Printf("Annotations: %#v\n", Get().Annotations)
 	fmt.Printf("now:%#v\n", annotations)
 }
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serviceloadbalancer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name:    "TestLoad_valid",
+			content: "annotations:\n  service.beta.kubernetes.io/alibaba-cloud-loadbalancer-id: 'abc'\n",
+			wantErr: false,
+		},
+		{
+			name:    "TestLoad_bad_yaml",
+			content: "annotations: [",
+			wantErr: true,
+		},
+		{
+			name:    "TestLoad_empty",
+			content: "",
+			wantErr: true,
+		},
+		{
+			name:    "TestLoad_without_annotations",
+			content: "whiteListOff:\n  a.b/acl-status: 'off'\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestLoad_bad_key",
+			content: "whiteListOn:\n  'bad key!': 'on'\n",
+			wantErr: true,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := fmt.Sprintf("%s/svc-%d.yaml", dir, i)
+			if err := ioutil.WriteFile(configFile, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(configFile); (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serviceloadbalancer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	configFile := fmt.Sprintf("%s/svc.yaml", dir)
+	if err := ioutil.WriteFile(configFile, []byte("annotations:\n  a.b/id: 'v1'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_ = Init(configFile)
+	changed := make(chan *Annotations, 1)
+	OnChange(func(a *Annotations) {
+		select {
+		case changed <- a:
+		default:
+		}
+	})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go Watch(configFile, time.Millisecond*10, stopCh)
+
+	// a bad file must be reported and the previous annotations must be kept
+	if err := ioutil.WriteFile(configFile, []byte("annotations:\n  'bad key!': 'v2'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 100)
+	if got := Get().Annotations["a.b/id"]; got != "v1" {
+		t.Errorf("Get() after bad file = %v, want v1", got)
+	}
+
+	// a file which drops all the managed keys must be kept out as well
+	if err := ioutil.WriteFile(configFile, []byte("annotations:\n  c.d/id: 'v2'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 100)
+	if got := Get().Annotations["a.b/id"]; got != "v1" {
+		t.Errorf("Get() after the managed keys have been dropped = %v, want v1", got)
+	}
+
+	if err := ioutil.WriteFile(configFile, []byte("annotations:\n  a.b/id: 'v3'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case a := <-changed:
+		if got := a.Annotations["a.b/id"]; got != "v3" {
+			t.Errorf("OnChange() = %v, want v3", got)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("OnChange() was not called")
+	}
+	if got := Get().Annotations["a.b/id"]; got != "v3" {
+		t.Errorf("Get() = %v, want v3", got)
+	}
+}
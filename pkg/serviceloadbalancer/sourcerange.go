@@ -0,0 +1,57 @@
+package serviceloadbalancer
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SourceRanges merges the CIDRs with the shared allowlists referenced by refs.
+// The result was deduplicated and sorted, so that it could be compared with the one in use directly.
+func SourceRanges(cidrs []string, refs []string) ([]string, error) {
+	conf := Get()
+	set := make(map[string]struct{}, len(cidrs))
+	for _, v := range cidrs {
+		set[strings.TrimSpace(v)] = struct{}{}
+	}
+	for _, ref := range refs {
+		t, ok := conf.SourceRanges[ref]
+		if !ok {
+			return nil, fmt.Errorf("serviceloadbalancer sourceRanges %q not found", ref)
+		}
+		for _, v := range t {
+			set[strings.TrimSpace(v)] = struct{}{}
+		}
+	}
+	res := make([]string, 0, len(set))
+	for k := range set {
+		if err := validateCIDR(k); err != nil {
+			return nil, err
+		}
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// LoadBalancerSourceRanges returns the CIDRs which should be set in the spec.loadBalancerSourceRanges of the Service.
+// It returns nil when the allowlist would be rendered into the SourceRangesAnnotation instead.
+func LoadBalancerSourceRanges(svc corev1.ServiceType, sourceRanges []string) []string {
+	if svc != corev1.ServiceTypeLoadBalancer || len(sourceRanges) == 0 {
+		return nil
+	}
+	if Get().SourceRangesAnnotation != "" {
+		return nil
+	}
+	return sourceRanges
+}
+
+func validateCIDR(cidr string) error {
+	if _, _, err := net.ParseCIDR(cidr); err != nil {
+		return fmt.Errorf("invalid CIDR %q: %v", cidr, err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package serviceloadbalancer
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSourceRanges(t *testing.T) {
+	Set(&Annotations{
+		SourceRanges: map[string][]string{
+			"testers": {"10.0.0.0/8", "192.168.1.0/24"},
+		},
+	})
+	defer Set(nil)
+	type args struct {
+		cidrs []string
+		refs  []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "TestSourceRanges_cidrs",
+			args: args{
+				cidrs: []string{"1.2.3.4/32"},
+			},
+			want: []string{"1.2.3.4/32"},
+		},
+		{
+			name: "TestSourceRanges_refs_merged",
+			args: args{
+				cidrs: []string{"192.168.1.0/24", "1.2.3.4/32"},
+				refs:  []string{"testers"},
+			},
+			want: []string{"1.2.3.4/32", "10.0.0.0/8", "192.168.1.0/24"},
+		},
+		{
+			name: "TestSourceRanges_empty",
+			args: args{},
+			want: []string{},
+		},
+		{
+			name: "TestSourceRanges_unknown_ref",
+			args: args{
+				refs: []string{"nobody"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestSourceRanges_invalid_cidr",
+			args: args{
+				cidrs: []string{"1.2.3.4"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SourceRanges(tt.args.cidrs, tt.args.refs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SourceRanges() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SourceRanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnotationSourceRanges(t *testing.T) {
+	conf := &Annotations{
+		Annotations:  map[string]string{"lb-id": "abc"},
+		WhiteListOn:  map[string]string{"acl-status": "on"},
+		WhiteListOff: map[string]string{"acl-status": "off"},
+	}
+	Set(conf)
+	defer Set(nil)
+	ranges := []string{"1.2.3.4/32", "10.0.0.0/8"}
+	if got, want := Annotation(corev1.ServiceTypeLoadBalancer, true, ranges), map[string]string{"lb-id": "abc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Annotation() = %v, want %v", got, want)
+	}
+	if got := LoadBalancerSourceRanges(corev1.ServiceTypeLoadBalancer, ranges); !reflect.DeepEqual(got, ranges) {
+		t.Errorf("LoadBalancerSourceRanges() = %v, want %v", got, ranges)
+	}
+	if got := LoadBalancerSourceRanges(corev1.ServiceTypeClusterIP, ranges); got != nil {
+		t.Errorf("LoadBalancerSourceRanges() = %v, want nil", got)
+	}
+	conf.SourceRangesAnnotation = "acl-cidrs"
+	if got, want := Annotation(corev1.ServiceTypeLoadBalancer, true, ranges), map[string]string{"lb-id": "abc", "acl-cidrs": "1.2.3.4/32,10.0.0.0/8"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Annotation() = %v, want %v", got, want)
+	}
+	if got := LoadBalancerSourceRanges(corev1.ServiceTypeLoadBalancer, ranges); got != nil {
+		t.Errorf("LoadBalancerSourceRanges() = %v, want nil", got)
+	}
+	if got, want := Annotation(corev1.ServiceTypeLoadBalancer, true, nil), map[string]string{"lb-id": "abc", "acl-status": "on"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Annotation() = %v, want %v", got, want)
+	}
+}